@@ -209,6 +209,7 @@ func MapType() *types.Type {
 	//    oldbuckets unsafe.Pointer
 	//    nevacuate  uintptr
 	//    extra      unsafe.Pointer // *mapextra
+	//    writer     uint64
 	// }
 	// must match runtime/map.go:hmap.
 	fields := []*types.Field{
@@ -221,6 +222,7 @@ func MapType() *types.Type {
 		makefield("oldbuckets", types.Types[types.TUNSAFEPTR]),
 		makefield("nevacuate", types.Types[types.TUINTPTR]),
 		makefield("extra", types.Types[types.TUNSAFEPTR]),
+		makefield("writer", types.Types[types.TUINT64]),
 	}
 
 	n := ir.NewDeclNameAt(src.NoXPos, ir.OTYPE, ir.Pkgs.Runtime.Lookup("hmap"))
@@ -231,9 +233,9 @@ func MapType() *types.Type {
 	hmap.SetUnderlying(types.NewStruct(fields))
 	types.CalcSize(hmap)
 
-	// The size of hmap should be 48 bytes on 64 bit
-	// and 28 bytes on 32 bit platforms.
-	if size := int64(8 + 5*types.PtrSize); hmap.Size() != size {
+	// The size of hmap should be 56 bytes on 64 bit
+	// and 36 bytes on 32 bit platforms.
+	if size := int64(16 + 5*types.PtrSize); hmap.Size() != size {
 		base.Fatalf("hmap size not correct: got %d, want %d", hmap.Size(), size)
 	}
 
@@ -425,6 +425,123 @@ func isSameCall(sym interface{}, name string) bool {
 	return fn != nil && fn.String() == name
 }
 
+// mapAccessResults returns the number of results, 1 or 2, of the fast map
+// lookup called by callAux, and the key kind of the lookup ("fast32",
+// "fast64", "fast128" or "faststr"). It returns 0 if callAux is not a fast
+// map lookup.
+func mapAccessResults(callAux Aux) (int64, string) {
+	fn := callAux.(*AuxCall).Fn
+	if fn == nil {
+		return 0, ""
+	}
+	name, ok := strings.CutPrefix(fn.String(), "runtime.mapaccess")
+	if !ok || len(name) < 2 || name[1] != '_' {
+		return 0, ""
+	}
+	switch kind := name[2:]; kind {
+	case "fast32", "fast64", "fast128", "faststr":
+		switch name[0] {
+		case '1':
+			return 1, kind
+		case '2':
+			return 2, kind
+		}
+	}
+	return 0, ""
+}
+
+// isRepeatedMapAccess reports whether a call to the fast map lookup
+// callAux with map type descriptor typ, key argument key and memory
+// argument mem can reuse the results of an earlier lookup. sel is the
+// memory result of the earlier call. The caller has checked that both
+// calls use the same map. The earlier call must be a lookup of the same
+// kind that returns at least as many results as callAux, and nothing
+// but writes to the temporary holding the key (see mapKeyTemp in walk)
+// may happen between sel and mem.
+func isRepeatedMapAccess(callAux Aux, typ, key, mem, sel *Value) bool {
+	n, kind := mapAccessResults(callAux)
+	if n == 0 {
+		return false
+	}
+	prev := sel.Args[0]
+	prevN, prevKind := mapAccessResults(prev.Aux)
+	if prevN < n || prevKind != kind || sel.AuxInt != prevN {
+		return false
+	}
+	// The type descriptors are separate values until CSE.
+	prevTyp := prev.Args[0]
+	if typ != prevTyp &&
+		!(typ.Op == OpAddr && prevTyp.Op == OpAddr && typ.Aux == prevTyp.Aux && typ.Args[0] == prevTyp.Args[0]) {
+		return false
+	}
+	prevKey := prev.Args[2]
+	if key.Op != OpDereference || prevKey.Op != OpDereference {
+		return key == prevKey && mem == sel
+	}
+	if key.Args[1] != mem || prevKey.Args[1] != prev.Args[3] {
+		return false
+	}
+	// A 16-byte key is loaded from memory for each call. Map lookups do
+	// not write to memory the program can see, so two loads from the
+	// same address load the same key if nothing else is written between
+	// them.
+	p, m, tmp := mapKeyRead(key)
+	prevP, prevM, prevTmp := mapKeyRead(prevKey)
+	return p == prevP && skipLocalWrites(mem, tmp) == sel && m == sel &&
+		prevM == skipLocalWrites(prev.Args[3], prevTmp)
+}
+
+// mapKeyRead returns the address p that the 16-byte map key loaded by the
+// Dereference key is read from, and the memory state m it is read in. A
+// key whose type is not [2]uint64 is first copied into a temporary, which
+// is first zeroed; then p and m are those of the copy, and tmp is the
+// temporary.
+func mapKeyRead(key *Value) (p, m *Value, tmp Aux) {
+	p, m = key.Args[0], key.Args[1]
+	if p.Op != OpLocalAddr || m.Op != OpMove || localAddrOf(m.Args[0]) != p.Aux {
+		return p, m, nil
+	}
+	tmp = p.Aux
+	p, m = m.Args[1], m.Args[2]
+	if localAddrOf(p) == tmp {
+		return nil, nil, nil
+	}
+	return p, skipLocalWrites(m, tmp), tmp
+}
+
+// skipLocalWrites returns the memory state before the writes to the local
+// variable tmp that end in memory state m.
+func skipLocalWrites(m *Value, tmp Aux) *Value {
+	for tmp != nil {
+		switch m.Op {
+		case OpZero, OpMove, OpStore:
+			if localAddrOf(m.Args[0]) != tmp {
+				return m
+			}
+			m = m.MemoryArg()
+		case OpVarDef:
+			if m.Aux != tmp {
+				return m
+			}
+			m = m.Args[0]
+		default:
+			return m
+		}
+	}
+	return m
+}
+
+// localAddrOf returns the local variable that p is the address of, or nil.
+func localAddrOf(p *Value) Aux {
+	for p.Op == OpNilCheck || p.Op == OpCopy {
+		p = p.Args[0]
+	}
+	if p.Op != OpLocalAddr {
+		return nil
+	}
+	return p.Aux
+}
+
 // canLoadUnaligned reports if the architecture supports unaligned load operations.
 func canLoadUnaligned(c *Config) bool {
 	return c.ctxt.Arch.Alignment == 1
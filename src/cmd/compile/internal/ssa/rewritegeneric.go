@@ -29311,6 +29311,156 @@ func rewriteValuegeneric_OpStaticLECall(v *Value) bool {
 		v.AddArg2(v0, mem)
 		return true
 	}
+	// match: (StaticLECall {callAux} mt m key mem:(SelectN prev:(StaticLECall _ m _ _)))
+	// cond: isRepeatedMapAccess(callAux, mt, key, mem, mem) && v.Type.NumFields() == 2
+	// result: (MakeResult (SelectN <v.Type.FieldType(0)> [0] prev) mem)
+	for {
+		if len(v.Args) != 4 {
+			break
+		}
+		callAux := auxToCall(v.Aux)
+		_ = v.Args[3]
+		mt := v.Args[0]
+		m := v.Args[1]
+		key := v.Args[2]
+		mem := v.Args[3]
+		if mem.Op != OpSelectN {
+			break
+		}
+		prev := mem.Args[0]
+		if prev.Op != OpStaticLECall || len(prev.Args) != 4 {
+			break
+		}
+		_ = prev.Args[1]
+		if m != prev.Args[1] || !(isRepeatedMapAccess(callAux, mt, key, mem, mem) && v.Type.NumFields() == 2) {
+			break
+		}
+		v.reset(OpMakeResult)
+		v0 := b.NewValue0(v.Pos, OpSelectN, v.Type.FieldType(0))
+		v0.AuxInt = int64ToAuxInt(0)
+		v0.AddArg(prev)
+		v.AddArg2(v0, mem)
+		return true
+	}
+	// match: (StaticLECall {callAux} mt m key mem:(SelectN prev:(StaticLECall _ m _ _)))
+	// cond: isRepeatedMapAccess(callAux, mt, key, mem, mem) && v.Type.NumFields() == 3
+	// result: (MakeResult (SelectN <v.Type.FieldType(0)> [0] prev) (SelectN <v.Type.FieldType(1)> [1] prev) mem)
+	for {
+		if len(v.Args) != 4 {
+			break
+		}
+		callAux := auxToCall(v.Aux)
+		_ = v.Args[3]
+		mt := v.Args[0]
+		m := v.Args[1]
+		key := v.Args[2]
+		mem := v.Args[3]
+		if mem.Op != OpSelectN {
+			break
+		}
+		prev := mem.Args[0]
+		if prev.Op != OpStaticLECall || len(prev.Args) != 4 {
+			break
+		}
+		_ = prev.Args[1]
+		if m != prev.Args[1] || !(isRepeatedMapAccess(callAux, mt, key, mem, mem) && v.Type.NumFields() == 3) {
+			break
+		}
+		v.reset(OpMakeResult)
+		v0 := b.NewValue0(v.Pos, OpSelectN, v.Type.FieldType(0))
+		v0.AuxInt = int64ToAuxInt(0)
+		v0.AddArg(prev)
+		v1 := b.NewValue0(v.Pos, OpSelectN, v.Type.FieldType(1))
+		v1.AuxInt = int64ToAuxInt(1)
+		v1.AddArg(prev)
+		v.AddArg3(v0, v1, mem)
+		return true
+	}
+	// match: (StaticLECall {callAux} mt m key mem:(Move _ _ (Zero _ sel:(SelectN prev:(StaticLECall _ m _ _)))))
+	// cond: isRepeatedMapAccess(callAux, mt, key, mem, sel) && v.Type.NumFields() == 2
+	// result: (MakeResult (SelectN <v.Type.FieldType(0)> [0] prev) mem)
+	for {
+		if len(v.Args) != 4 {
+			break
+		}
+		callAux := auxToCall(v.Aux)
+		_ = v.Args[3]
+		mt := v.Args[0]
+		m := v.Args[1]
+		key := v.Args[2]
+		mem := v.Args[3]
+		if mem.Op != OpMove {
+			break
+		}
+		_ = mem.Args[2]
+		mem_2 := mem.Args[2]
+		if mem_2.Op != OpZero {
+			break
+		}
+		_ = mem_2.Args[1]
+		sel := mem_2.Args[1]
+		if sel.Op != OpSelectN {
+			break
+		}
+		prev := sel.Args[0]
+		if prev.Op != OpStaticLECall || len(prev.Args) != 4 {
+			break
+		}
+		_ = prev.Args[1]
+		if m != prev.Args[1] || !(isRepeatedMapAccess(callAux, mt, key, mem, sel) && v.Type.NumFields() == 2) {
+			break
+		}
+		v.reset(OpMakeResult)
+		v0 := b.NewValue0(v.Pos, OpSelectN, v.Type.FieldType(0))
+		v0.AuxInt = int64ToAuxInt(0)
+		v0.AddArg(prev)
+		v.AddArg2(v0, mem)
+		return true
+	}
+	// match: (StaticLECall {callAux} mt m key mem:(Move _ _ (Zero _ sel:(SelectN prev:(StaticLECall _ m _ _)))))
+	// cond: isRepeatedMapAccess(callAux, mt, key, mem, sel) && v.Type.NumFields() == 3
+	// result: (MakeResult (SelectN <v.Type.FieldType(0)> [0] prev) (SelectN <v.Type.FieldType(1)> [1] prev) mem)
+	for {
+		if len(v.Args) != 4 {
+			break
+		}
+		callAux := auxToCall(v.Aux)
+		_ = v.Args[3]
+		mt := v.Args[0]
+		m := v.Args[1]
+		key := v.Args[2]
+		mem := v.Args[3]
+		if mem.Op != OpMove {
+			break
+		}
+		_ = mem.Args[2]
+		mem_2 := mem.Args[2]
+		if mem_2.Op != OpZero {
+			break
+		}
+		_ = mem_2.Args[1]
+		sel := mem_2.Args[1]
+		if sel.Op != OpSelectN {
+			break
+		}
+		prev := sel.Args[0]
+		if prev.Op != OpStaticLECall || len(prev.Args) != 4 {
+			break
+		}
+		_ = prev.Args[1]
+		if m != prev.Args[1] || !(isRepeatedMapAccess(callAux, mt, key, mem, sel) && v.Type.NumFields() == 3) {
+			break
+		}
+		v.reset(OpMakeResult)
+		v0 := b.NewValue0(v.Pos, OpSelectN, v.Type.FieldType(0))
+		v0.AuxInt = int64ToAuxInt(0)
+		v0.AddArg(prev)
+		v1 := b.NewValue0(v.Pos, OpSelectN, v.Type.FieldType(1))
+		v1.AuxInt = int64ToAuxInt(1)
+		v1.AddArg(prev)
+		v.AddArg3(v0, v1, mem)
+		return true
+	}
 	// match: (StaticLECall {callAux} _ (Const64 [0]) (Const64 [0]) mem)
 	// cond: isSameCall(callAux, "runtime.makeslice")
 	// result: (MakeResult (Addr <v.Type.FieldType(0)> {ir.Syms.Zerobase} (SB)) mem)
@@ -57,7 +57,9 @@ func tightenTupleSelectors(f *Func) {
 			// If the selector is in the wrong block copy it into the target
 			// block.
 			if selector.Block != tuple.Block {
-				t := selector.copyInto(tuple.Block)
+				// Not copyInto: the tuple may be a call, whose
+				// results include memory.
+				t := tuple.Block.NewValue1I(selector.Pos.WithNotStmt(), selector.Op, selector.Type, selector.AuxInt, tuple)
 				selector.copyOf(t)
 				selectors[key] = t
 				continue
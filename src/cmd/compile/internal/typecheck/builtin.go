@@ -111,133 +111,137 @@ var runtimeDecls = [...]struct {
 	{"mapaccess1", funcTag, 80},
 	{"mapaccess1_fast32", funcTag, 81},
 	{"mapaccess1_fast64", funcTag, 82},
-	{"mapaccess1_faststr", funcTag, 83},
-	{"mapaccess1_fat", funcTag, 84},
-	{"mapaccess2", funcTag, 85},
-	{"mapaccess2_fast32", funcTag, 86},
-	{"mapaccess2_fast64", funcTag, 87},
-	{"mapaccess2_faststr", funcTag, 88},
-	{"mapaccess2_fat", funcTag, 89},
+	{"mapaccess1_fast128", funcTag, 84},
+	{"mapaccess1_faststr", funcTag, 85},
+	{"mapaccess1_fat", funcTag, 86},
+	{"mapaccess2", funcTag, 87},
+	{"mapaccess2_fast32", funcTag, 88},
+	{"mapaccess2_fast64", funcTag, 89},
+	{"mapaccess2_fast128", funcTag, 90},
+	{"mapaccess2_faststr", funcTag, 91},
+	{"mapaccess2_fat", funcTag, 92},
 	{"mapassign", funcTag, 80},
 	{"mapassign_fast32", funcTag, 81},
-	{"mapassign_fast32ptr", funcTag, 90},
+	{"mapassign_fast32ptr", funcTag, 93},
 	{"mapassign_fast64", funcTag, 82},
-	{"mapassign_fast64ptr", funcTag, 90},
-	{"mapassign_faststr", funcTag, 83},
-	{"mapiterinit", funcTag, 91},
-	{"mapdelete", funcTag, 91},
-	{"mapdelete_fast32", funcTag, 92},
-	{"mapdelete_fast64", funcTag, 93},
-	{"mapdelete_faststr", funcTag, 94},
-	{"mapiternext", funcTag, 95},
-	{"mapclear", funcTag, 96},
-	{"makechan64", funcTag, 98},
-	{"makechan", funcTag, 99},
-	{"chanrecv1", funcTag, 101},
-	{"chanrecv2", funcTag, 102},
-	{"chansend1", funcTag, 104},
-	{"closechan", funcTag, 105},
-	{"chanlen", funcTag, 106},
-	{"chancap", funcTag, 106},
-	{"writeBarrier", varTag, 108},
-	{"typedmemmove", funcTag, 109},
-	{"typedmemclr", funcTag, 110},
-	{"typedslicecopy", funcTag, 111},
-	{"selectnbsend", funcTag, 112},
-	{"selectnbrecv", funcTag, 113},
-	{"selectsetpc", funcTag, 114},
-	{"selectgo", funcTag, 115},
+	{"mapassign_fast64ptr", funcTag, 93},
+	{"mapassign_fast128", funcTag, 84},
+	{"mapassign_faststr", funcTag, 85},
+	{"mapiterinit", funcTag, 94},
+	{"mapdelete", funcTag, 94},
+	{"mapdelete_fast32", funcTag, 95},
+	{"mapdelete_fast64", funcTag, 96},
+	{"mapdelete_fast128", funcTag, 97},
+	{"mapdelete_faststr", funcTag, 98},
+	{"mapiternext", funcTag, 99},
+	{"mapclear", funcTag, 100},
+	{"makechan64", funcTag, 102},
+	{"makechan", funcTag, 103},
+	{"chanrecv1", funcTag, 105},
+	{"chanrecv2", funcTag, 106},
+	{"chansend1", funcTag, 108},
+	{"closechan", funcTag, 109},
+	{"chanlen", funcTag, 110},
+	{"chancap", funcTag, 110},
+	{"writeBarrier", varTag, 112},
+	{"typedmemmove", funcTag, 113},
+	{"typedmemclr", funcTag, 114},
+	{"typedslicecopy", funcTag, 115},
+	{"selectnbsend", funcTag, 116},
+	{"selectnbrecv", funcTag, 117},
+	{"selectsetpc", funcTag, 118},
+	{"selectgo", funcTag, 119},
 	{"block", funcTag, 9},
-	{"makeslice", funcTag, 116},
-	{"makeslice64", funcTag, 117},
-	{"makeslicecopy", funcTag, 118},
-	{"growslice", funcTag, 120},
-	{"unsafeslicecheckptr", funcTag, 121},
+	{"makeslice", funcTag, 120},
+	{"makeslice64", funcTag, 121},
+	{"makeslicecopy", funcTag, 122},
+	{"growslice", funcTag, 124},
+	{"unsafeslicecheckptr", funcTag, 125},
 	{"panicunsafeslicelen", funcTag, 9},
 	{"panicunsafeslicenilptr", funcTag, 9},
-	{"unsafestringcheckptr", funcTag, 122},
+	{"unsafestringcheckptr", funcTag, 126},
 	{"panicunsafestringlen", funcTag, 9},
 	{"panicunsafestringnilptr", funcTag, 9},
-	{"memmove", funcTag, 123},
-	{"memclrNoHeapPointers", funcTag, 124},
-	{"memclrHasPointers", funcTag, 124},
-	{"memequal", funcTag, 125},
-	{"memequal0", funcTag, 126},
-	{"memequal8", funcTag, 126},
-	{"memequal16", funcTag, 126},
-	{"memequal32", funcTag, 126},
-	{"memequal64", funcTag, 126},
-	{"memequal128", funcTag, 126},
-	{"f32equal", funcTag, 127},
-	{"f64equal", funcTag, 127},
-	{"c64equal", funcTag, 127},
-	{"c128equal", funcTag, 127},
-	{"strequal", funcTag, 127},
-	{"interequal", funcTag, 127},
-	{"nilinterequal", funcTag, 127},
-	{"memhash", funcTag, 128},
-	{"memhash0", funcTag, 129},
-	{"memhash8", funcTag, 129},
-	{"memhash16", funcTag, 129},
-	{"memhash32", funcTag, 129},
-	{"memhash64", funcTag, 129},
-	{"memhash128", funcTag, 129},
-	{"f32hash", funcTag, 130},
-	{"f64hash", funcTag, 130},
-	{"c64hash", funcTag, 130},
-	{"c128hash", funcTag, 130},
-	{"strhash", funcTag, 130},
-	{"interhash", funcTag, 130},
-	{"nilinterhash", funcTag, 130},
-	{"int64div", funcTag, 131},
-	{"uint64div", funcTag, 132},
-	{"int64mod", funcTag, 131},
-	{"uint64mod", funcTag, 132},
-	{"float64toint64", funcTag, 133},
-	{"float64touint64", funcTag, 134},
-	{"float64touint32", funcTag, 135},
-	{"int64tofloat64", funcTag, 136},
-	{"int64tofloat32", funcTag, 138},
-	{"uint64tofloat64", funcTag, 139},
-	{"uint64tofloat32", funcTag, 140},
-	{"uint32tofloat64", funcTag, 141},
-	{"complex128div", funcTag, 142},
-	{"getcallerpc", funcTag, 143},
-	{"getcallersp", funcTag, 143},
+	{"memmove", funcTag, 127},
+	{"memclrNoHeapPointers", funcTag, 128},
+	{"memclrHasPointers", funcTag, 128},
+	{"memequal", funcTag, 129},
+	{"memequal0", funcTag, 130},
+	{"memequal8", funcTag, 130},
+	{"memequal16", funcTag, 130},
+	{"memequal32", funcTag, 130},
+	{"memequal64", funcTag, 130},
+	{"memequal128", funcTag, 130},
+	{"f32equal", funcTag, 131},
+	{"f64equal", funcTag, 131},
+	{"c64equal", funcTag, 131},
+	{"c128equal", funcTag, 131},
+	{"strequal", funcTag, 131},
+	{"interequal", funcTag, 131},
+	{"nilinterequal", funcTag, 131},
+	{"memhash", funcTag, 132},
+	{"memhash0", funcTag, 133},
+	{"memhash8", funcTag, 133},
+	{"memhash16", funcTag, 133},
+	{"memhash32", funcTag, 133},
+	{"memhash64", funcTag, 133},
+	{"memhash128", funcTag, 133},
+	{"f32hash", funcTag, 134},
+	{"f64hash", funcTag, 134},
+	{"c64hash", funcTag, 134},
+	{"c128hash", funcTag, 134},
+	{"strhash", funcTag, 134},
+	{"interhash", funcTag, 134},
+	{"nilinterhash", funcTag, 134},
+	{"int64div", funcTag, 135},
+	{"uint64div", funcTag, 136},
+	{"int64mod", funcTag, 135},
+	{"uint64mod", funcTag, 136},
+	{"float64toint64", funcTag, 137},
+	{"float64touint64", funcTag, 138},
+	{"float64touint32", funcTag, 139},
+	{"int64tofloat64", funcTag, 140},
+	{"int64tofloat32", funcTag, 142},
+	{"uint64tofloat64", funcTag, 143},
+	{"uint64tofloat32", funcTag, 144},
+	{"uint32tofloat64", funcTag, 145},
+	{"complex128div", funcTag, 146},
+	{"getcallerpc", funcTag, 147},
+	{"getcallersp", funcTag, 147},
 	{"racefuncenter", funcTag, 31},
 	{"racefuncexit", funcTag, 9},
 	{"raceread", funcTag, 31},
 	{"racewrite", funcTag, 31},
-	{"racereadrange", funcTag, 144},
-	{"racewriterange", funcTag, 144},
-	{"msanread", funcTag, 144},
-	{"msanwrite", funcTag, 144},
-	{"msanmove", funcTag, 145},
-	{"asanread", funcTag, 144},
-	{"asanwrite", funcTag, 144},
-	{"checkptrAlignment", funcTag, 146},
-	{"checkptrArithmetic", funcTag, 148},
-	{"libfuzzerTraceCmp1", funcTag, 149},
-	{"libfuzzerTraceCmp2", funcTag, 150},
-	{"libfuzzerTraceCmp4", funcTag, 151},
-	{"libfuzzerTraceCmp8", funcTag, 152},
-	{"libfuzzerTraceConstCmp1", funcTag, 149},
-	{"libfuzzerTraceConstCmp2", funcTag, 150},
-	{"libfuzzerTraceConstCmp4", funcTag, 151},
-	{"libfuzzerTraceConstCmp8", funcTag, 152},
-	{"libfuzzerHookStrCmp", funcTag, 153},
-	{"libfuzzerHookEqualFold", funcTag, 153},
-	{"addCovMeta", funcTag, 155},
+	{"racereadrange", funcTag, 148},
+	{"racewriterange", funcTag, 148},
+	{"msanread", funcTag, 148},
+	{"msanwrite", funcTag, 148},
+	{"msanmove", funcTag, 149},
+	{"asanread", funcTag, 148},
+	{"asanwrite", funcTag, 148},
+	{"checkptrAlignment", funcTag, 150},
+	{"checkptrArithmetic", funcTag, 152},
+	{"libfuzzerTraceCmp1", funcTag, 153},
+	{"libfuzzerTraceCmp2", funcTag, 154},
+	{"libfuzzerTraceCmp4", funcTag, 155},
+	{"libfuzzerTraceCmp8", funcTag, 156},
+	{"libfuzzerTraceConstCmp1", funcTag, 153},
+	{"libfuzzerTraceConstCmp2", funcTag, 154},
+	{"libfuzzerTraceConstCmp4", funcTag, 155},
+	{"libfuzzerTraceConstCmp8", funcTag, 156},
+	{"libfuzzerHookStrCmp", funcTag, 157},
+	{"libfuzzerHookEqualFold", funcTag, 157},
+	{"addCovMeta", funcTag, 159},
 	{"x86HasPOPCNT", varTag, 6},
 	{"x86HasSSE41", varTag, 6},
 	{"x86HasFMA", varTag, 6},
 	{"armHasVFPv4", varTag, 6},
 	{"arm64HasATOMICS", varTag, 6},
-	{"asanregisterglobals", funcTag, 124},
+	{"asanregisterglobals", funcTag, 128},
 }
 
 func runtimeTypes() []*types.Type {
-	var typs [156]*types.Type
+	var typs [160]*types.Type
 	typs[0] = types.ByteType
 	typs[1] = types.NewPtr(typs[0])
 	typs[2] = types.Types[types.TANY]
@@ -321,79 +325,83 @@ func runtimeTypes() []*types.Type {
 	typs[80] = newSig(params(typs[1], typs[76], typs[3]), params(typs[3]))
 	typs[81] = newSig(params(typs[1], typs[76], typs[60]), params(typs[3]))
 	typs[82] = newSig(params(typs[1], typs[76], typs[24]), params(typs[3]))
-	typs[83] = newSig(params(typs[1], typs[76], typs[28]), params(typs[3]))
-	typs[84] = newSig(params(typs[1], typs[76], typs[3], typs[1]), params(typs[3]))
-	typs[85] = newSig(params(typs[1], typs[76], typs[3]), params(typs[3], typs[6]))
-	typs[86] = newSig(params(typs[1], typs[76], typs[60]), params(typs[3], typs[6]))
-	typs[87] = newSig(params(typs[1], typs[76], typs[24]), params(typs[3], typs[6]))
-	typs[88] = newSig(params(typs[1], typs[76], typs[28]), params(typs[3], typs[6]))
-	typs[89] = newSig(params(typs[1], typs[76], typs[3], typs[1]), params(typs[3], typs[6]))
-	typs[90] = newSig(params(typs[1], typs[76], typs[7]), params(typs[3]))
-	typs[91] = newSig(params(typs[1], typs[76], typs[3]), nil)
-	typs[92] = newSig(params(typs[1], typs[76], typs[60]), nil)
-	typs[93] = newSig(params(typs[1], typs[76], typs[24]), nil)
-	typs[94] = newSig(params(typs[1], typs[76], typs[28]), nil)
-	typs[95] = newSig(params(typs[3]), nil)
-	typs[96] = newSig(params(typs[1], typs[76]), nil)
-	typs[97] = types.NewChan(typs[2], types.Cboth)
-	typs[98] = newSig(params(typs[1], typs[22]), params(typs[97]))
-	typs[99] = newSig(params(typs[1], typs[15]), params(typs[97]))
-	typs[100] = types.NewChan(typs[2], types.Crecv)
-	typs[101] = newSig(params(typs[100], typs[3]), nil)
-	typs[102] = newSig(params(typs[100], typs[3]), params(typs[6]))
-	typs[103] = types.NewChan(typs[2], types.Csend)
-	typs[104] = newSig(params(typs[103], typs[3]), nil)
-	typs[105] = newSig(params(typs[103]), nil)
-	typs[106] = newSig(params(typs[2]), params(typs[15]))
-	typs[107] = types.NewArray(typs[0], 3)
-	typs[108] = types.NewStruct([]*types.Field{types.NewField(src.NoXPos, Lookup("enabled"), typs[6]), types.NewField(src.NoXPos, Lookup("pad"), typs[107]), types.NewField(src.NoXPos, Lookup("cgo"), typs[6]), types.NewField(src.NoXPos, Lookup("alignme"), typs[24])})
-	typs[109] = newSig(params(typs[1], typs[3], typs[3]), nil)
-	typs[110] = newSig(params(typs[1], typs[3]), nil)
-	typs[111] = newSig(params(typs[1], typs[3], typs[15], typs[3], typs[15]), params(typs[15]))
-	typs[112] = newSig(params(typs[103], typs[3]), params(typs[6]))
-	typs[113] = newSig(params(typs[3], typs[100]), params(typs[6], typs[6]))
-	typs[114] = newSig(params(typs[71]), nil)
-	typs[115] = newSig(params(typs[1], typs[1], typs[71], typs[15], typs[15], typs[6]), params(typs[15], typs[6]))
-	typs[116] = newSig(params(typs[1], typs[15], typs[15]), params(typs[7]))
-	typs[117] = newSig(params(typs[1], typs[22], typs[22]), params(typs[7]))
-	typs[118] = newSig(params(typs[1], typs[15], typs[15], typs[7]), params(typs[7]))
-	typs[119] = types.NewSlice(typs[2])
-	typs[120] = newSig(params(typs[3], typs[15], typs[15], typs[15], typs[1]), params(typs[119]))
-	typs[121] = newSig(params(typs[1], typs[7], typs[22]), nil)
-	typs[122] = newSig(params(typs[7], typs[22]), nil)
-	typs[123] = newSig(params(typs[3], typs[3], typs[5]), nil)
-	typs[124] = newSig(params(typs[7], typs[5]), nil)
-	typs[125] = newSig(params(typs[3], typs[3], typs[5]), params(typs[6]))
-	typs[126] = newSig(params(typs[3], typs[3]), params(typs[6]))
-	typs[127] = newSig(params(typs[7], typs[7]), params(typs[6]))
-	typs[128] = newSig(params(typs[3], typs[5], typs[5]), params(typs[5]))
-	typs[129] = newSig(params(typs[7], typs[5]), params(typs[5]))
-	typs[130] = newSig(params(typs[3], typs[5]), params(typs[5]))
-	typs[131] = newSig(params(typs[22], typs[22]), params(typs[22]))
-	typs[132] = newSig(params(typs[24], typs[24]), params(typs[24]))
-	typs[133] = newSig(params(typs[20]), params(typs[22]))
-	typs[134] = newSig(params(typs[20]), params(typs[24]))
-	typs[135] = newSig(params(typs[20]), params(typs[60]))
-	typs[136] = newSig(params(typs[22]), params(typs[20]))
-	typs[137] = types.Types[types.TFLOAT32]
-	typs[138] = newSig(params(typs[22]), params(typs[137]))
-	typs[139] = newSig(params(typs[24]), params(typs[20]))
-	typs[140] = newSig(params(typs[24]), params(typs[137]))
-	typs[141] = newSig(params(typs[60]), params(typs[20]))
-	typs[142] = newSig(params(typs[26], typs[26]), params(typs[26]))
-	typs[143] = newSig(nil, params(typs[5]))
-	typs[144] = newSig(params(typs[5], typs[5]), nil)
-	typs[145] = newSig(params(typs[5], typs[5], typs[5]), nil)
-	typs[146] = newSig(params(typs[7], typs[1], typs[5]), nil)
-	typs[147] = types.NewSlice(typs[7])
-	typs[148] = newSig(params(typs[7], typs[147]), nil)
-	typs[149] = newSig(params(typs[64], typs[64], typs[17]), nil)
-	typs[150] = newSig(params(typs[58], typs[58], typs[17]), nil)
-	typs[151] = newSig(params(typs[60], typs[60], typs[17]), nil)
-	typs[152] = newSig(params(typs[24], typs[24], typs[17]), nil)
-	typs[153] = newSig(params(typs[28], typs[28], typs[17]), nil)
-	typs[154] = types.NewArray(typs[0], 16)
-	typs[155] = newSig(params(typs[7], typs[60], typs[154], typs[28], typs[15], typs[64], typs[64]), params(typs[60]))
+	typs[83] = types.NewArray(typs[24], 2)
+	typs[84] = newSig(params(typs[1], typs[76], typs[83]), params(typs[3]))
+	typs[85] = newSig(params(typs[1], typs[76], typs[28]), params(typs[3]))
+	typs[86] = newSig(params(typs[1], typs[76], typs[3], typs[1]), params(typs[3]))
+	typs[87] = newSig(params(typs[1], typs[76], typs[3]), params(typs[3], typs[6]))
+	typs[88] = newSig(params(typs[1], typs[76], typs[60]), params(typs[3], typs[6]))
+	typs[89] = newSig(params(typs[1], typs[76], typs[24]), params(typs[3], typs[6]))
+	typs[90] = newSig(params(typs[1], typs[76], typs[83]), params(typs[3], typs[6]))
+	typs[91] = newSig(params(typs[1], typs[76], typs[28]), params(typs[3], typs[6]))
+	typs[92] = newSig(params(typs[1], typs[76], typs[3], typs[1]), params(typs[3], typs[6]))
+	typs[93] = newSig(params(typs[1], typs[76], typs[7]), params(typs[3]))
+	typs[94] = newSig(params(typs[1], typs[76], typs[3]), nil)
+	typs[95] = newSig(params(typs[1], typs[76], typs[60]), nil)
+	typs[96] = newSig(params(typs[1], typs[76], typs[24]), nil)
+	typs[97] = newSig(params(typs[1], typs[76], typs[83]), nil)
+	typs[98] = newSig(params(typs[1], typs[76], typs[28]), nil)
+	typs[99] = newSig(params(typs[3]), nil)
+	typs[100] = newSig(params(typs[1], typs[76]), nil)
+	typs[101] = types.NewChan(typs[2], types.Cboth)
+	typs[102] = newSig(params(typs[1], typs[22]), params(typs[101]))
+	typs[103] = newSig(params(typs[1], typs[15]), params(typs[101]))
+	typs[104] = types.NewChan(typs[2], types.Crecv)
+	typs[105] = newSig(params(typs[104], typs[3]), nil)
+	typs[106] = newSig(params(typs[104], typs[3]), params(typs[6]))
+	typs[107] = types.NewChan(typs[2], types.Csend)
+	typs[108] = newSig(params(typs[107], typs[3]), nil)
+	typs[109] = newSig(params(typs[107]), nil)
+	typs[110] = newSig(params(typs[2]), params(typs[15]))
+	typs[111] = types.NewArray(typs[0], 3)
+	typs[112] = types.NewStruct([]*types.Field{types.NewField(src.NoXPos, Lookup("enabled"), typs[6]), types.NewField(src.NoXPos, Lookup("pad"), typs[111]), types.NewField(src.NoXPos, Lookup("cgo"), typs[6]), types.NewField(src.NoXPos, Lookup("alignme"), typs[24])})
+	typs[113] = newSig(params(typs[1], typs[3], typs[3]), nil)
+	typs[114] = newSig(params(typs[1], typs[3]), nil)
+	typs[115] = newSig(params(typs[1], typs[3], typs[15], typs[3], typs[15]), params(typs[15]))
+	typs[116] = newSig(params(typs[107], typs[3]), params(typs[6]))
+	typs[117] = newSig(params(typs[3], typs[104]), params(typs[6], typs[6]))
+	typs[118] = newSig(params(typs[71]), nil)
+	typs[119] = newSig(params(typs[1], typs[1], typs[71], typs[15], typs[15], typs[6]), params(typs[15], typs[6]))
+	typs[120] = newSig(params(typs[1], typs[15], typs[15]), params(typs[7]))
+	typs[121] = newSig(params(typs[1], typs[22], typs[22]), params(typs[7]))
+	typs[122] = newSig(params(typs[1], typs[15], typs[15], typs[7]), params(typs[7]))
+	typs[123] = types.NewSlice(typs[2])
+	typs[124] = newSig(params(typs[3], typs[15], typs[15], typs[15], typs[1]), params(typs[123]))
+	typs[125] = newSig(params(typs[1], typs[7], typs[22]), nil)
+	typs[126] = newSig(params(typs[7], typs[22]), nil)
+	typs[127] = newSig(params(typs[3], typs[3], typs[5]), nil)
+	typs[128] = newSig(params(typs[7], typs[5]), nil)
+	typs[129] = newSig(params(typs[3], typs[3], typs[5]), params(typs[6]))
+	typs[130] = newSig(params(typs[3], typs[3]), params(typs[6]))
+	typs[131] = newSig(params(typs[7], typs[7]), params(typs[6]))
+	typs[132] = newSig(params(typs[3], typs[5], typs[5]), params(typs[5]))
+	typs[133] = newSig(params(typs[7], typs[5]), params(typs[5]))
+	typs[134] = newSig(params(typs[3], typs[5]), params(typs[5]))
+	typs[135] = newSig(params(typs[22], typs[22]), params(typs[22]))
+	typs[136] = newSig(params(typs[24], typs[24]), params(typs[24]))
+	typs[137] = newSig(params(typs[20]), params(typs[22]))
+	typs[138] = newSig(params(typs[20]), params(typs[24]))
+	typs[139] = newSig(params(typs[20]), params(typs[60]))
+	typs[140] = newSig(params(typs[22]), params(typs[20]))
+	typs[141] = types.Types[types.TFLOAT32]
+	typs[142] = newSig(params(typs[22]), params(typs[141]))
+	typs[143] = newSig(params(typs[24]), params(typs[20]))
+	typs[144] = newSig(params(typs[24]), params(typs[141]))
+	typs[145] = newSig(params(typs[60]), params(typs[20]))
+	typs[146] = newSig(params(typs[26], typs[26]), params(typs[26]))
+	typs[147] = newSig(nil, params(typs[5]))
+	typs[148] = newSig(params(typs[5], typs[5]), nil)
+	typs[149] = newSig(params(typs[5], typs[5], typs[5]), nil)
+	typs[150] = newSig(params(typs[7], typs[1], typs[5]), nil)
+	typs[151] = types.NewSlice(typs[7])
+	typs[152] = newSig(params(typs[7], typs[151]), nil)
+	typs[153] = newSig(params(typs[64], typs[64], typs[17]), nil)
+	typs[154] = newSig(params(typs[58], typs[58], typs[17]), nil)
+	typs[155] = newSig(params(typs[60], typs[60], typs[17]), nil)
+	typs[156] = newSig(params(typs[24], typs[24], typs[17]), nil)
+	typs[157] = newSig(params(typs[28], typs[28], typs[17]), nil)
+	typs[158] = types.NewArray(typs[0], 16)
+	typs[159] = newSig(params(typs[7], typs[60], typs[158], typs[28], typs[15], typs[64], typs[64]), params(typs[60]))
 	return typs[:]
 }
 
@@ -23,7 +23,8 @@ import (
 // order of evaluation. Makes walk easier, because it
 // can (after this runs) reorder at will within an expression.
 //
-// Rewrite m[k] op= r into m[k] = m[k] op r if op is / or %.
+// Rewrite m[k] op= r into m[k] = m[k] op r if op is / or %,
+// and m[k] = m[k] op r into m[k] op= r for other ops.
 //
 // Introduce temporaries as needed by runtime routines.
 // For example, the map runtime routines take the map key
@@ -276,14 +277,16 @@ func (o *orderState) mapKeyTemp(outerPos src.XPos, t *types.Type, n ir.Node) ir.
 		kt = types.Types[types.TUINT64]
 	case mapfast32ptr, mapfast64ptr:
 		kt = types.Types[types.TUNSAFEPTR]
+	case mapfast128:
+		kt = types.NewArray(types.Types[types.TUINT64], 2)
 	case mapfaststr:
 		kt = types.Types[types.TSTRING]
 	}
 	nt := n.Type()
 	switch {
-	case nt == kt:
+	case types.Identical(nt, kt):
 		return n
-	case nt.Kind() == kt.Kind(), nt.IsPtrShaped() && kt.IsPtrShaped():
+	case alg != mapfast128 && nt.Kind() == kt.Kind(), nt.IsPtrShaped() && kt.IsPtrShaped():
 		// can directly convert (e.g. named type to underlying type, or one pointer to another)
 		return typecheck.Expr(ir.NewConvExpr(pos, ir.OCONVNOP, kt, n))
 	case nt.IsInteger() && kt.IsInteger():
@@ -586,6 +589,46 @@ func (o *orderState) mapAssign(n ir.Node) {
 	}
 }
 
+// mapAssignOp returns the statement m[k] op= r if n is m[k] = m[k] op r,
+// so that the read-modify-write is done with a single call to mapassign
+// instead of a mapaccess followed by a mapassign. It returns nil if n
+// has any other form, if op is / or % (see OASOP in stmt) or a shift by a
+// count that may be negative, or if r has side effects that could observe
+// the order of the lookups. Like a division by zero, a negative shift
+// count must panic before the key is inserted.
+func mapAssignOp(n *ir.AssignStmt) *ir.AssignOpStmt {
+	if n.X == nil || n.X.Op() != ir.OINDEXMAP || n.Y == nil {
+		return nil
+	}
+	var op ir.Op
+	var l, r ir.Node
+	switch n.Y.Op() {
+	case ir.OADD, ir.OSUB, ir.OMUL, ir.OOR, ir.OAND, ir.OANDNOT, ir.OXOR:
+		y := n.Y.(*ir.BinaryExpr)
+		op, l, r = y.Op(), y.X, y.Y
+	case ir.OLSH, ir.ORSH:
+		y := n.Y.(*ir.BinaryExpr)
+		if y.Y.Op() != ir.OLITERAL && !y.Y.Type().IsUnsigned() {
+			return nil
+		}
+		op, l, r = y.Op(), y.X, y.Y
+	case ir.OADDSTR:
+		y := n.Y.(*ir.AddStringExpr)
+		if len(y.List) != 2 {
+			return nil
+		}
+		op, l, r = ir.OADD, y.List[0], y.List[1]
+	default:
+		return nil
+	}
+	if l.Op() != ir.OINDEXMAP || !types.Identical(n.X.Type(), n.Y.Type()) ||
+		!ir.SameSafeExpr(n.X, l) || staticinit.AnySideEffects(r) {
+		return nil
+	}
+	as := ir.NewAssignOpStmt(n.Pos(), op, n.X, r)
+	return typecheck.Stmt(as).(*ir.AssignOpStmt)
+}
+
 func (o *orderState) safeMapRHS(r ir.Node) ir.Node {
 	// Make sure we evaluate the RHS before starting the map insert.
 	// We need to make sure the RHS won't panic.  See issue 22881.
@@ -618,6 +661,10 @@ func (o *orderState) stmt(n ir.Node) {
 
 	case ir.OAS:
 		n := n.(*ir.AssignStmt)
+		if as := mapAssignOp(n); as != nil {
+			o.stmt(as)
+			break
+		}
 		t := o.markTemp()
 
 		// There's a delicate interaction here between two OINDEXMAP
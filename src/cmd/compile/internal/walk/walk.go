@@ -168,6 +168,7 @@ const (
 	mapfast32ptr
 	mapfast64
 	mapfast64ptr
+	mapfast128
 	mapfaststr
 	nmapfast
 )
@@ -175,7 +176,7 @@ const (
 type mapnames [nmapfast]string
 
 func mkmapnames(base string, ptr string) mapnames {
-	return mapnames{base, base + "_fast32", base + "_fast32" + ptr, base + "_fast64", base + "_fast64" + ptr, base + "_faststr"}
+	return mapnames{base, base + "_fast32", base + "_fast32" + ptr, base + "_fast64", base + "_fast64" + ptr, base + "_fast128", base + "_faststr"}
 }
 
 var mapaccess1 = mkmapnames("mapaccess1", "")
@@ -205,6 +206,10 @@ func mapfast(t *types.Type) int {
 		}
 		// Two-word object, at least one of which is a pointer.
 		// Use the slow path.
+	case types.AMEM128:
+		if !t.Key().HasPointers() {
+			return mapfast128
+		}
 	case types.ASTRING:
 		return mapfaststr
 	}
@@ -90,11 +90,13 @@ var builtins = [...]struct {
 	{"runtime.mapaccess1", 1},
 	{"runtime.mapaccess1_fast32", 1},
 	{"runtime.mapaccess1_fast64", 1},
+	{"runtime.mapaccess1_fast128", 1},
 	{"runtime.mapaccess1_faststr", 1},
 	{"runtime.mapaccess1_fat", 1},
 	{"runtime.mapaccess2", 1},
 	{"runtime.mapaccess2_fast32", 1},
 	{"runtime.mapaccess2_fast64", 1},
+	{"runtime.mapaccess2_fast128", 1},
 	{"runtime.mapaccess2_faststr", 1},
 	{"runtime.mapaccess2_fat", 1},
 	{"runtime.mapassign", 1},
@@ -102,11 +104,13 @@ var builtins = [...]struct {
 	{"runtime.mapassign_fast32ptr", 1},
 	{"runtime.mapassign_fast64", 1},
 	{"runtime.mapassign_fast64ptr", 1},
+	{"runtime.mapassign_fast128", 1},
 	{"runtime.mapassign_faststr", 1},
 	{"runtime.mapiterinit", 1},
 	{"runtime.mapdelete", 1},
 	{"runtime.mapdelete_fast32", 1},
 	{"runtime.mapdelete_fast64", 1},
+	{"runtime.mapdelete_fast128", 1},
 	{"runtime.mapdelete_faststr", 1},
 	{"runtime.mapiternext", 1},
 	{"runtime.mapclear", 1},
@@ -2340,6 +2340,7 @@ var blockedLinknames = map[string][]string{
 	// weak references
 	"internal/weak.runtime_registerWeakPointer": {"internal/weak"},
 	"internal/weak.runtime_makeStrongFromWeak":  {"internal/weak"},
+	"internal/weak.runtime_registerMapCleanup":  {"internal/weak"},
 }
 
 // check if a linkname reference to symbol s from pkg is allowed
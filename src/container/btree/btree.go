@@ -0,0 +1,516 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package btree implements sorted maps and sets backed by B-trees.
+//
+// A [Map] or [Set] keeps its keys in order, so besides lookups,
+// insertions and deletions, which take logarithmic time, it can
+// iterate over its keys in order, report the keys within a range,
+// and find the nearest key above or below a given one.
+//
+// Keys are ordered by a comparison function. [NewMap] and [NewSet]
+// use [cmp.Compare] for key types with a natural order;
+// [NewMapFunc] and [NewSetFunc] accept any comparison function that
+// defines a strict weak ordering.
+package btree
+
+import "slices"
+
+// degree is the minimum degree of the B-tree: every node other than
+// the root holds between degree-1 and 2*degree-1 items.
+const degree = 16
+
+const (
+	maxItems = 2*degree - 1
+	minItems = degree - 1
+)
+
+// An item is a key and its value.
+type item[K, V any] struct {
+	key   K
+	value V
+}
+
+// A node is a node of a B-tree.
+// A leaf has no children; an internal node has one more child
+// than it has items, and the keys in children[i] sort between
+// items[i-1].key and items[i].key.
+type node[K, V any] struct {
+	items    []item[K, V]
+	children []*node[K, V]
+}
+
+func (n *node[K, V]) leaf() bool { return n.children == nil }
+
+// A tree is a B-tree mapping keys of type K to values of type V.
+// It implements both Map and Set.
+type tree[K, V any] struct {
+	cmp  func(K, K) int
+	root *node[K, V]
+	len  int
+
+	// mod counts the changes made to the structure of the tree,
+	// so that an iteration can detect that its position is stale.
+	mod uint64
+}
+
+// find returns the index of the first item in n whose key is not less
+// than key, and whether that item's key is equal to key.
+func (t *tree[K, V]) find(n *node[K, V], key K) (int, bool) {
+	i, j := 0, len(n.items)
+	for i < j {
+		h := int(uint(i+j) >> 1)
+		if t.cmp(n.items[h].key, key) < 0 {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i, i < len(n.items) && t.cmp(n.items[i].key, key) == 0
+}
+
+// get returns the item for key, or nil if there is none.
+func (t *tree[K, V]) get(key K) *item[K, V] {
+	for n := t.root; n != nil; {
+		i, found := t.find(n, key)
+		if found {
+			return &n.items[i]
+		}
+		if n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+	return nil
+}
+
+// set sets the value for key, inserting it if it is not present.
+func (t *tree[K, V]) set(key K, value V) {
+	if t.root == nil {
+		t.root = &node[K, V]{items: []item[K, V]{{key, value}}}
+		t.len++
+		t.mod++
+		return
+	}
+	if len(t.root.items) == maxItems {
+		t.root = &node[K, V]{children: []*node[K, V]{t.root}}
+		t.split(t.root, 0)
+	}
+	n := t.root
+	for {
+		i, found := t.find(n, key)
+		if found {
+			n.items[i].value = value
+			return
+		}
+		if n.leaf() {
+			n.items = slices.Insert(n.items, i, item[K, V]{key, value})
+			t.len++
+			t.mod++
+			return
+		}
+		if len(n.children[i].items) == maxItems {
+			t.split(n, i)
+			switch c := t.cmp(key, n.items[i].key); {
+			case c == 0:
+				n.items[i].value = value
+				return
+			case c > 0:
+				i++
+			}
+		}
+		n = n.children[i]
+	}
+}
+
+// split splits the full child n.children[i] in two,
+// moving its middle item up into n.
+func (t *tree[K, V]) split(n *node[K, V], i int) {
+	left := n.children[i]
+	mid := left.items[minItems]
+	right := &node[K, V]{items: slices.Clone(left.items[minItems+1:])}
+	clear(left.items[minItems:])
+	left.items = left.items[:minItems]
+	if !left.leaf() {
+		right.children = slices.Clone(left.children[minItems+1:])
+		clear(left.children[minItems+1:])
+		left.children = left.children[:minItems+1]
+	}
+	n.items = slices.Insert(n.items, i, mid)
+	n.children = slices.Insert(n.children, i+1, right)
+	t.mod++
+}
+
+// delete removes key from the tree, if present.
+func (t *tree[K, V]) delete(key K) {
+	if t.root == nil {
+		return
+	}
+	if t.remove(t.root, key) {
+		t.len--
+	}
+	// Even if key was not present, the root may have been
+	// emptied by merging its last two children.
+	if len(t.root.items) == 0 {
+		if t.root.leaf() {
+			t.root = nil
+		} else {
+			t.root = t.root.children[0]
+		}
+	}
+}
+
+// remove removes key from the subtree rooted at n, reporting whether
+// it was present. Unless n is the root, it holds more than minItems items.
+func (t *tree[K, V]) remove(n *node[K, V], key K) bool {
+	for {
+		i, found := t.find(n, key)
+		if n.leaf() {
+			if !found {
+				return false
+			}
+			n.items = slices.Delete(n.items, i, i+1)
+			t.mod++
+			return true
+		}
+		if found {
+			switch {
+			case len(n.children[i].items) > minItems:
+				// Replace the item with its predecessor.
+				c := n.children[i]
+				for !c.leaf() {
+					c = c.children[len(c.children)-1]
+				}
+				n.items[i] = c.items[len(c.items)-1]
+				key = n.items[i].key
+				t.mod++
+			case len(n.children[i+1].items) > minItems:
+				// Replace the item with its successor.
+				c := n.children[i+1]
+				for !c.leaf() {
+					c = c.children[0]
+				}
+				n.items[i] = c.items[0]
+				key = n.items[i].key
+				t.mod++
+				i++
+			default:
+				t.merge(n, i)
+			}
+			n = n.children[i]
+			continue
+		}
+		if len(n.children[i].items) == minItems {
+			i = t.grow(n, i)
+		}
+		n = n.children[i]
+	}
+}
+
+// grow adds an item to the child n.children[i], which holds minItems
+// items, by taking one from a sibling or by merging with a sibling.
+// It returns the index of the child that now holds the keys
+// that were in n.children[i].
+func (t *tree[K, V]) grow(n *node[K, V], i int) int {
+	c := n.children[i]
+	t.mod++
+	switch {
+	case i > 0 && len(n.children[i-1].items) > minItems:
+		// Rotate an item from the left sibling through n.
+		left := n.children[i-1]
+		c.items = slices.Insert(c.items, 0, n.items[i-1])
+		n.items[i-1] = left.items[len(left.items)-1]
+		left.items[len(left.items)-1] = item[K, V]{}
+		left.items = left.items[:len(left.items)-1]
+		if !c.leaf() {
+			c.children = slices.Insert(c.children, 0, left.children[len(left.children)-1])
+			left.children[len(left.children)-1] = nil
+			left.children = left.children[:len(left.children)-1]
+		}
+	case i < len(n.items) && len(n.children[i+1].items) > minItems:
+		// Rotate an item from the right sibling through n.
+		right := n.children[i+1]
+		c.items = append(c.items, n.items[i])
+		n.items[i] = right.items[0]
+		right.items = slices.Delete(right.items, 0, 1)
+		if !c.leaf() {
+			c.children = append(c.children, right.children[0])
+			right.children = slices.Delete(right.children, 0, 1)
+		}
+	case i < len(n.items):
+		t.merge(n, i)
+	default:
+		t.merge(n, i-1)
+		i--
+	}
+	return i
+}
+
+// merge merges n.children[i+1] and the item n.items[i]
+// into n.children[i].
+func (t *tree[K, V]) merge(n *node[K, V], i int) {
+	left, right := n.children[i], n.children[i+1]
+	left.items = append(left.items, n.items[i])
+	left.items = append(left.items, right.items...)
+	left.children = append(left.children, right.children...)
+	n.items = slices.Delete(n.items, i, i+1)
+	n.children = slices.Delete(n.children, i+1, i+2)
+	t.mod++
+}
+
+// clear removes all keys from the tree.
+func (t *tree[K, V]) clear() {
+	t.root = nil
+	t.len = 0
+	t.mod++
+}
+
+// min returns the item with the smallest key, or nil if the tree is empty.
+func (t *tree[K, V]) min() *item[K, V] {
+	n := t.root
+	if n == nil {
+		return nil
+	}
+	for !n.leaf() {
+		n = n.children[0]
+	}
+	return &n.items[0]
+}
+
+// max returns the item with the largest key, or nil if the tree is empty.
+func (t *tree[K, V]) max() *item[K, V] {
+	n := t.root
+	if n == nil {
+		return nil
+	}
+	for !n.leaf() {
+		n = n.children[len(n.children)-1]
+	}
+	return &n.items[len(n.items)-1]
+}
+
+// ceil returns the item with the smallest key greater than or equal
+// to key, or nil if there is none.
+func (t *tree[K, V]) ceil(key K) *item[K, V] {
+	var best *item[K, V]
+	for n := t.root; n != nil; {
+		i, found := t.find(n, key)
+		if found {
+			return &n.items[i]
+		}
+		if i < len(n.items) {
+			best = &n.items[i]
+		}
+		if n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+	return best
+}
+
+// floor returns the item with the largest key less than or equal
+// to key, or nil if there is none.
+func (t *tree[K, V]) floor(key K) *item[K, V] {
+	var best *item[K, V]
+	for n := t.root; n != nil; {
+		i, found := t.find(n, key)
+		if found {
+			return &n.items[i]
+		}
+		if i > 0 {
+			best = &n.items[i-1]
+		}
+		if n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+	return best
+}
+
+// A frame is a position in a node during iteration.
+// Going forward, the next item to produce is n.items[i];
+// going backward, it is n.items[i-1].
+type frame[K, V any] struct {
+	n *node[K, V]
+	i int
+}
+
+// An iterator walks the items of a tree in order.
+// Each frame on the stack is a position in the child
+// the frame below it is about to produce items from.
+type iterator[K, V any] struct {
+	t     *tree[K, V]
+	stack []frame[K, V]
+}
+
+// seekGE positions it before the first item whose key is greater than
+// or equal to key, or, if after is set, greater than key.
+func (it *iterator[K, V]) seekGE(key K, after bool) {
+	it.stack = it.stack[:0]
+	for n := it.t.root; n != nil; {
+		i, found := it.t.find(n, key)
+		it.stack = append(it.stack, frame[K, V]{n, i})
+		if found {
+			if after {
+				it.next()
+			}
+			return
+		}
+		if n.leaf() {
+			return
+		}
+		n = n.children[i]
+	}
+}
+
+// seekFirst positions it before the first item.
+func (it *iterator[K, V]) seekFirst() {
+	it.stack = it.stack[:0]
+	it.pushLeft(it.t.root)
+}
+
+// pushLeft pushes the path to the smallest key in the subtree at n.
+func (it *iterator[K, V]) pushLeft(n *node[K, V]) {
+	for n != nil {
+		it.stack = append(it.stack, frame[K, V]{n, 0})
+		if n.leaf() {
+			return
+		}
+		n = n.children[0]
+	}
+}
+
+// next returns the next item in increasing order, or nil at the end.
+func (it *iterator[K, V]) next() *item[K, V] {
+	for len(it.stack) > 0 {
+		f := &it.stack[len(it.stack)-1]
+		if f.i < len(f.n.items) {
+			e := &f.n.items[f.i]
+			f.i++
+			if !f.n.leaf() {
+				it.pushLeft(f.n.children[f.i])
+			}
+			return e
+		}
+		it.stack = it.stack[:len(it.stack)-1]
+	}
+	return nil
+}
+
+// seekLE positions it before the last item whose key is less than
+// or equal to key, or, if before is set, less than key.
+func (it *iterator[K, V]) seekLE(key K, before bool) {
+	it.stack = it.stack[:0]
+	for n := it.t.root; n != nil; {
+		i, found := it.t.find(n, key)
+		if found {
+			it.stack = append(it.stack, frame[K, V]{n, i + 1})
+			if before {
+				it.prev()
+			}
+			return
+		}
+		it.stack = append(it.stack, frame[K, V]{n, i})
+		if n.leaf() {
+			return
+		}
+		n = n.children[i]
+	}
+}
+
+// seekLast positions it after the last item.
+func (it *iterator[K, V]) seekLast() {
+	it.stack = it.stack[:0]
+	it.pushRight(it.t.root)
+}
+
+// pushRight pushes the path to the largest key in the subtree at n.
+func (it *iterator[K, V]) pushRight(n *node[K, V]) {
+	for n != nil {
+		it.stack = append(it.stack, frame[K, V]{n, len(n.items)})
+		if n.leaf() {
+			return
+		}
+		n = n.children[len(n.children)-1]
+	}
+}
+
+// prev returns the next item in decreasing order, or nil at the end.
+func (it *iterator[K, V]) prev() *item[K, V] {
+	for len(it.stack) > 0 {
+		f := &it.stack[len(it.stack)-1]
+		if f.i > 0 {
+			f.i--
+			e := &f.n.items[f.i]
+			if !f.n.leaf() {
+				it.pushRight(f.n.children[f.i])
+			}
+			return e
+		}
+		it.stack = it.stack[:len(it.stack)-1]
+	}
+	return nil
+}
+
+// A bound is an optional bound of a range of keys.
+type bound[K any] struct {
+	key K
+	ok  bool
+}
+
+// ascend calls yield for each item with a key between lo and hi,
+// inclusive, in increasing order, until yield returns false.
+//
+// If the tree is modified during iteration, ascend continues with
+// the first key greater than the last key it produced.
+func (t *tree[K, V]) ascend(lo, hi bound[K], yield func(K, V) bool) {
+	it := iterator[K, V]{t: t, stack: make([]frame[K, V], 0, 8)}
+	if lo.ok {
+		it.seekGE(lo.key, false)
+	} else {
+		it.seekFirst()
+	}
+	for {
+		e := it.next()
+		if e == nil || hi.ok && t.cmp(e.key, hi.key) > 0 {
+			return
+		}
+		mod, key := t.mod, e.key
+		if !yield(key, e.value) {
+			return
+		}
+		if t.mod != mod {
+			it.seekGE(key, true)
+		}
+	}
+}
+
+// descend calls yield for each item with a key between lo and hi,
+// inclusive, in decreasing order, until yield returns false.
+//
+// If the tree is modified during iteration, descend continues with
+// the first key less than the last key it produced.
+func (t *tree[K, V]) descend(lo, hi bound[K], yield func(K, V) bool) {
+	it := iterator[K, V]{t: t, stack: make([]frame[K, V], 0, 8)}
+	if hi.ok {
+		it.seekLE(hi.key, false)
+	} else {
+		it.seekLast()
+	}
+	for {
+		e := it.prev()
+		if e == nil || lo.ok && t.cmp(e.key, lo.key) < 0 {
+			return
+		}
+		mod, key := t.mod, e.key
+		if !yield(key, e.value) {
+			return
+		}
+		if t.mod != mod {
+			it.seekLE(key, true)
+		}
+	}
+}
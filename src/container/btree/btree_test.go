@@ -0,0 +1,315 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package btree
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+	"testing/maptest"
+)
+
+// check checks the invariants of t and returns its keys in order.
+func (t *tree[K, V]) check(tb testing.TB) []K {
+	tb.Helper()
+	var keys []K
+	leafDepth := -1
+	var walk func(n *node[K, V], depth int)
+	walk = func(n *node[K, V], depth int) {
+		if n != t.root && (len(n.items) < minItems || len(n.items) > maxItems) {
+			tb.Fatalf("node at depth %d has %d items, want between %d and %d", depth, len(n.items), minItems, maxItems)
+		}
+		if n.leaf() {
+			if leafDepth < 0 {
+				leafDepth = depth
+			} else if depth != leafDepth {
+				tb.Fatalf("leaves at depths %d and %d", leafDepth, depth)
+			}
+			for _, e := range n.items {
+				keys = append(keys, e.key)
+			}
+			return
+		}
+		if len(n.children) != len(n.items)+1 {
+			tb.Fatalf("node with %d items has %d children", len(n.items), len(n.children))
+		}
+		for i, c := range n.children {
+			walk(c, depth+1)
+			if i < len(n.items) {
+				keys = append(keys, n.items[i].key)
+			}
+		}
+	}
+	if t.root != nil {
+		if len(t.root.items) == 0 {
+			tb.Fatalf("root has no items")
+		}
+		walk(t.root, 0)
+	}
+	if !slices.IsSortedFunc(keys, t.cmp) || len(slices.CompactFunc(slices.Clone(keys), func(a, b K) bool { return t.cmp(a, b) == 0 })) != len(keys) {
+		tb.Fatalf("keys not strictly increasing: %v", keys)
+	}
+	if len(keys) != t.len {
+		tb.Fatalf("tree has %d keys, len = %d", len(keys), t.len)
+	}
+	return keys
+}
+
+// testMap adapts a Map to maptest.Map.
+type testMap struct {
+	*Map[int, int]
+}
+
+func (m testMap) Load(key int) (int, bool) { return m.Get(key) }
+func (m testMap) Store(key, value int)     { m.Set(key, value) }
+
+func TestMaptest(t *testing.T) {
+	newMap := func() maptest.Map[int, int] { return testMap{NewMap[int, int]()} }
+	identity := func(i int) int { return i }
+	if err := maptest.TestMap(newMap, identity, identity); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// seq returns the keys produced by the iterator seq.
+func seq[K, V any](seq func(func(K, V) bool)) []K {
+	var keys []K
+	for k := range seq {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func TestRandom(t *testing.T) {
+	for _, n := range []int{10, 100, 1000, 10000} {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			r := rand.New(rand.NewPCG(1, uint64(n)))
+			m := NewMap[int, int]()
+			model := map[int]int{}
+			for i := range 10 * n {
+				// Even keys only, so that odd keys exercise Floor and Ceil.
+				k := 2 * r.IntN(n)
+				if r.IntN(3) == 0 {
+					m.Delete(k)
+					delete(model, k)
+				} else {
+					m.Set(k, i)
+					model[k] = i
+				}
+				if i%n == 0 {
+					m.t.check(t)
+				}
+			}
+			keys := m.t.check(t)
+			want := slices.Sorted(func(yield func(int) bool) {
+				for k := range model {
+					if !yield(k) {
+						return
+					}
+				}
+			})
+			if !slices.Equal(keys, want) {
+				t.Fatalf("keys = %v, want %v", keys, want)
+			}
+			for _, k := range want {
+				if v, ok := m.Get(k); !ok || v != model[k] {
+					t.Fatalf("Get(%d) = %d, %v, want %d, true", k, v, ok, model[k])
+				}
+			}
+			if got := seq(m.All()); !slices.Equal(got, want) {
+				t.Fatalf("All() = %v, want %v", got, want)
+			}
+			backward := slices.Clone(want)
+			slices.Reverse(backward)
+			if got := seq(m.Backward()); !slices.Equal(got, backward) {
+				t.Fatalf("Backward() = %v, want %v", got, backward)
+			}
+
+			for range 100 {
+				k := r.IntN(2*n+2) - 1
+				i, _ := slices.BinarySearch(want, k)
+				wantCeil, wantCeilOK := 0, i < len(want)
+				if wantCeilOK {
+					wantCeil = want[i]
+				}
+				if got, _, ok := m.Ceil(k); got != wantCeil || ok != wantCeilOK {
+					t.Errorf("Ceil(%d) = %d, %v, want %d, %v", k, got, ok, wantCeil, wantCeilOK)
+				}
+				j, found := slices.BinarySearch(want, k)
+				if !found {
+					j--
+				}
+				wantFloor, wantFloorOK := 0, j >= 0
+				if wantFloorOK {
+					wantFloor = want[j]
+				}
+				if got, _, ok := m.Floor(k); got != wantFloor || ok != wantFloorOK {
+					t.Errorf("Floor(%d) = %d, %v, want %d, %v", k, got, ok, wantFloor, wantFloorOK)
+				}
+
+				lo, hi := k, k+r.IntN(n/2+1)
+				var wantScan []int
+				for _, k := range want {
+					if lo <= k && k <= hi {
+						wantScan = append(wantScan, k)
+					}
+				}
+				if got := seq(m.Scan(lo, hi)); !slices.Equal(got, wantScan) {
+					t.Fatalf("Scan(%d, %d) = %v, want %v", lo, hi, got, wantScan)
+				}
+				slices.Reverse(wantScan)
+				if got := seq(m.ScanBackward(lo, hi)); !slices.Equal(got, wantScan) {
+					t.Fatalf("ScanBackward(%d, %d) = %v, want %v", lo, hi, got, wantScan)
+				}
+			}
+
+			for _, k := range want {
+				m.Delete(k)
+			}
+			m.t.check(t)
+			if m.Len() != 0 || m.t.root != nil {
+				t.Fatalf("after deleting all keys, Len() = %d, root = %p", m.Len(), m.t.root)
+			}
+		})
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	m := NewMap[string, int]()
+	if k, v, ok := m.Min(); ok {
+		t.Errorf("Min() on empty map = %q, %d, true", k, v)
+	}
+	if k, v, ok := m.Max(); ok {
+		t.Errorf("Max() on empty map = %q, %d, true", k, v)
+	}
+	for i, k := range []string{"m", "c", "x", "a", "q"} {
+		m.Set(k, i)
+	}
+	if k, v, ok := m.Min(); k != "a" || v != 3 || !ok {
+		t.Errorf("Min() = %q, %d, %v, want %q, 3, true", k, v, ok, "a")
+	}
+	if k, v, ok := m.Max(); k != "x" || v != 2 || !ok {
+		t.Errorf("Max() = %q, %d, %v, want %q, 2, true", k, v, ok, "x")
+	}
+	if got, want := slices.Collect(m.Values()), []int{3, 1, 0, 4, 2}; !slices.Equal(got, want) {
+		t.Errorf("Values() = %v, want %v", got, want)
+	}
+	if got, want := slices.Collect(m.Keys()), []string{"a", "c", "m", "q", "x"}; !slices.Equal(got, want) {
+		t.Errorf("Keys() = %v, want %v", got, want)
+	}
+	m.Clear()
+	if m.Len() != 0 || len(seq(m.All())) != 0 {
+		t.Errorf("after Clear, Len() = %d, All() = %v", m.Len(), seq(m.All()))
+	}
+}
+
+func TestModifyDuringAll(t *testing.T) {
+	const n = 1000
+	m := NewMap[int, int]()
+	for i := range n {
+		m.Set(2*i, i)
+	}
+	var got []int
+	for k := range m.All() {
+		got = append(got, k)
+		switch {
+		case k%6 == 0:
+			// Delete the next key and add a key just after the current one.
+			m.Delete(k + 2)
+			m.Set(k+1, 0)
+		case k%2 == 1:
+			// Delete keys already produced.
+			m.Delete(k - 1)
+		}
+	}
+	var want []int
+	for k := 0; k < 2*n; k += 2 {
+		switch {
+		case k%6 == 0:
+			want = append(want, k, k+1)
+		case k%6 == 4:
+			want = append(want, k)
+		}
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("All() with modifications = %v, want %v", got, want)
+	}
+	m.t.check(t)
+
+	m.Clear()
+	for i := range n {
+		m.Set(2*i, i)
+	}
+	got = nil
+	for k := range m.Backward() {
+		got = append(got, k)
+		if k%2 == 0 {
+			// Delete the next key, add a key just before the current one,
+			// and add one after it, which is never produced.
+			m.Delete(k - 2)
+			m.Set(k-1, 0)
+			m.Set(k+1, 0)
+		}
+	}
+	want = nil
+	for k := 2*n - 2; k > 0; k -= 4 {
+		want = append(want, k, k-1)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Backward() with modifications = %v, want %v", got, want)
+	}
+	m.t.check(t)
+}
+
+func TestSet(t *testing.T) {
+	// Order strings by length, then contents.
+	s := NewSetFunc(func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(a), len(b)), cmp.Compare(a, b))
+	})
+	for _, k := range []string{"ccc", "a", "bb", "b", "dddd", "a"} {
+		s.Add(k)
+	}
+	if got, want := slices.Collect(s.All()), []string{"a", "b", "bb", "ccc", "dddd"}; !slices.Equal(got, want) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+	if got, want := slices.Collect(s.Backward()), []string{"dddd", "ccc", "bb", "b", "a"}; !slices.Equal(got, want) {
+		t.Errorf("Backward() = %v, want %v", got, want)
+	}
+	if got, want := slices.Collect(s.Scan("aa", "zzz")), []string{"bb", "ccc"}; !slices.Equal(got, want) {
+		t.Errorf(`Scan("aa", "zzz") = %v, want %v`, got, want)
+	}
+	if got, want := slices.Collect(s.ScanBackward("aa", "zzz")), []string{"ccc", "bb"}; !slices.Equal(got, want) {
+		t.Errorf(`ScanBackward("aa", "zzz") = %v, want %v`, got, want)
+	}
+	if !s.Contains("bb") || s.Contains("zz") {
+		t.Errorf(`Contains("bb"), Contains("zz") = %v, %v, want true, false`, s.Contains("bb"), s.Contains("zz"))
+	}
+	if k, ok := s.Ceil("zz"); k != "ccc" || !ok {
+		t.Errorf(`Ceil("zz") = %q, %v, want "ccc", true`, k, ok)
+	}
+	if k, ok := s.Floor("zz"); k != "bb" || !ok {
+		t.Errorf(`Floor("zz") = %q, %v, want "bb", true`, k, ok)
+	}
+	if k, ok := s.Floor(""); ok {
+		t.Errorf(`Floor("") = %q, true, want false`, k)
+	}
+	if k, ok := s.Min(); k != "a" || !ok {
+		t.Errorf(`Min() = %q, %v, want "a", true`, k, ok)
+	}
+	if k, ok := s.Max(); k != "dddd" || !ok {
+		t.Errorf(`Max() = %q, %v, want "dddd", true`, k, ok)
+	}
+	s.Delete("bb")
+	s.Delete("zz")
+	if s.Len() != 4 || s.Contains("bb") {
+		t.Errorf(`after Delete("bb"), Len() = %d, Contains("bb") = %v`, s.Len(), s.Contains("bb"))
+	}
+	s.Clear()
+	if _, ok := s.Min(); s.Len() != 0 || ok {
+		t.Errorf("after Clear, Len() = %d, Min() ok = %v", s.Len(), ok)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package btree_test
+
+import (
+	"container/btree"
+	"fmt"
+)
+
+func Example() {
+	m := btree.NewMap[int, string]()
+	m.Set(30, "thirty")
+	m.Set(10, "ten")
+	m.Set(20, "twenty")
+	m.Set(40, "forty")
+
+	for k, v := range m.Scan(15, 35) {
+		fmt.Println(k, v)
+	}
+
+	if k, v, ok := m.Floor(25); ok {
+		fmt.Println("floor of 25:", k, v)
+	}
+	if k, v, ok := m.Ceil(25); ok {
+		fmt.Println("ceil of 25:", k, v)
+	}
+
+	// Output:
+	// 20 twenty
+	// 30 thirty
+	// floor of 25: 20 twenty
+	// ceil of 25: 30 thirty
+}
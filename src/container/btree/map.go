@@ -0,0 +1,131 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package btree
+
+import (
+	"cmp"
+	"iter"
+)
+
+// A Map is a map from keys of type K to values of type V
+// that keeps its keys in sorted order.
+//
+// A Map must be created with [NewMap] or [NewMapFunc].
+type Map[K, V any] struct {
+	t tree[K, V]
+}
+
+// NewMap returns a new, empty map ordered by [cmp.Compare].
+func NewMap[K cmp.Ordered, V any]() *Map[K, V] {
+	return NewMapFunc[K, V](cmp.Compare[K])
+}
+
+// NewMapFunc returns a new, empty map ordered by cmp,
+// which must define a strict weak ordering as for [slices.SortFunc].
+// Keys for which cmp returns 0 are considered the same key.
+func NewMapFunc[K, V any](cmp func(a, b K) int) *Map[K, V] {
+	return &Map[K, V]{t: tree[K, V]{cmp: cmp}}
+}
+
+// Len returns the number of keys in m.
+func (m *Map[K, V]) Len() int { return m.t.len }
+
+// Get returns the value stored for key,
+// and whether key is present in m.
+func (m *Map[K, V]) Get(key K) (value V, ok bool) {
+	if e := m.t.get(key); e != nil {
+		return e.value, true
+	}
+	return value, false
+}
+
+// Set sets the value for key.
+func (m *Map[K, V]) Set(key K, value V) { m.t.set(key, value) }
+
+// Delete removes the value for key, if any.
+func (m *Map[K, V]) Delete(key K) { m.t.delete(key) }
+
+// Clear removes all keys from m.
+func (m *Map[K, V]) Clear() { m.t.clear() }
+
+// result unpacks an item returned by a tree lookup.
+func result[K, V any](e *item[K, V]) (key K, value V, ok bool) {
+	if e == nil {
+		return key, value, false
+	}
+	return e.key, e.value, true
+}
+
+// Min returns the smallest key in m and its value.
+// If m is empty, ok is false.
+func (m *Map[K, V]) Min() (key K, value V, ok bool) { return result(m.t.min()) }
+
+// Max returns the largest key in m and its value.
+// If m is empty, ok is false.
+func (m *Map[K, V]) Max() (key K, value V, ok bool) { return result(m.t.max()) }
+
+// Ceil returns the smallest key in m that is greater than or equal
+// to key, and its value. If there is no such key, ok is false.
+func (m *Map[K, V]) Ceil(key K) (k K, value V, ok bool) { return result(m.t.ceil(key)) }
+
+// Floor returns the largest key in m that is less than or equal
+// to key, and its value. If there is no such key, ok is false.
+func (m *Map[K, V]) Floor(key K) (k K, value V, ok bool) { return result(m.t.floor(key)) }
+
+// All returns an iterator over the keys and values in m,
+// in increasing order of key.
+//
+// If m is modified during iteration, the iteration continues with the
+// smallest key greater than the last one produced, so a key added or
+// deleted during iteration is produced if and only if it is present
+// when the iteration reaches it.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.t.ascend(bound[K]{}, bound[K]{}, yield)
+	}
+}
+
+// Backward returns an iterator over the keys and values in m,
+// in decreasing order of key.
+// See [Map.All] for the behavior when m is modified during iteration.
+func (m *Map[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.t.descend(bound[K]{}, bound[K]{}, yield)
+	}
+}
+
+// Keys returns an iterator over the keys in m, in increasing order.
+// See [Map.All] for the behavior when m is modified during iteration.
+func (m *Map[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		m.t.ascend(bound[K]{}, bound[K]{}, func(k K, _ V) bool { return yield(k) })
+	}
+}
+
+// Values returns an iterator over the values in m,
+// in increasing order of key.
+// See [Map.All] for the behavior when m is modified during iteration.
+func (m *Map[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		m.t.ascend(bound[K]{}, bound[K]{}, func(_ K, v V) bool { return yield(v) })
+	}
+}
+
+// Scan returns an iterator over the keys and values in m with keys
+// between lo and hi, inclusive, in increasing order of key.
+// See [Map.All] for the behavior when m is modified during iteration.
+func (m *Map[K, V]) Scan(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.t.ascend(bound[K]{lo, true}, bound[K]{hi, true}, yield)
+	}
+}
+
+// ScanBackward is like [Map.Scan] but produces the keys
+// in decreasing order.
+func (m *Map[K, V]) ScanBackward(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.t.descend(bound[K]{lo, true}, bound[K]{hi, true}, yield)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package btree
+
+import (
+	"cmp"
+	"iter"
+)
+
+// A Set is a set of keys of type K kept in sorted order.
+//
+// A Set must be created with [NewSet] or [NewSetFunc].
+type Set[K any] struct {
+	t tree[K, struct{}]
+}
+
+// NewSet returns a new, empty set ordered by [cmp.Compare].
+func NewSet[K cmp.Ordered]() *Set[K] {
+	return NewSetFunc(cmp.Compare[K])
+}
+
+// NewSetFunc returns a new, empty set ordered by cmp,
+// which must define a strict weak ordering as for [slices.SortFunc].
+// Keys for which cmp returns 0 are considered the same key.
+func NewSetFunc[K any](cmp func(a, b K) int) *Set[K] {
+	return &Set[K]{t: tree[K, struct{}]{cmp: cmp}}
+}
+
+// Len returns the number of keys in s.
+func (s *Set[K]) Len() int { return s.t.len }
+
+// Contains reports whether key is in s.
+func (s *Set[K]) Contains(key K) bool { return s.t.get(key) != nil }
+
+// Add adds key to s.
+func (s *Set[K]) Add(key K) { s.t.set(key, struct{}{}) }
+
+// Delete removes key from s, if present.
+func (s *Set[K]) Delete(key K) { s.t.delete(key) }
+
+// Clear removes all keys from s.
+func (s *Set[K]) Clear() { s.t.clear() }
+
+// Min returns the smallest key in s.
+// If s is empty, ok is false.
+func (s *Set[K]) Min() (key K, ok bool) {
+	key, _, ok = result(s.t.min())
+	return key, ok
+}
+
+// Max returns the largest key in s.
+// If s is empty, ok is false.
+func (s *Set[K]) Max() (key K, ok bool) {
+	key, _, ok = result(s.t.max())
+	return key, ok
+}
+
+// Ceil returns the smallest key in s that is greater than or equal
+// to key. If there is no such key, ok is false.
+func (s *Set[K]) Ceil(key K) (k K, ok bool) {
+	k, _, ok = result(s.t.ceil(key))
+	return k, ok
+}
+
+// Floor returns the largest key in s that is less than or equal
+// to key. If there is no such key, ok is false.
+func (s *Set[K]) Floor(key K) (k K, ok bool) {
+	k, _, ok = result(s.t.floor(key))
+	return k, ok
+}
+
+// keys adapts yield to the iteration functions of a tree.
+func keys[K any](yield func(K) bool) func(K, struct{}) bool {
+	return func(k K, _ struct{}) bool { return yield(k) }
+}
+
+// All returns an iterator over the keys in s, in increasing order.
+// See [Map.All] for the behavior when s is modified during iteration.
+func (s *Set[K]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		s.t.ascend(bound[K]{}, bound[K]{}, keys(yield))
+	}
+}
+
+// Backward returns an iterator over the keys in s, in decreasing order.
+// See [Map.All] for the behavior when s is modified during iteration.
+func (s *Set[K]) Backward() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		s.t.descend(bound[K]{}, bound[K]{}, keys(yield))
+	}
+}
+
+// Scan returns an iterator over the keys in s between lo and hi,
+// inclusive, in increasing order.
+// See [Map.All] for the behavior when s is modified during iteration.
+func (s *Set[K]) Scan(lo, hi K) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		s.t.ascend(bound[K]{lo, true}, bound[K]{hi, true}, keys(yield))
+	}
+}
+
+// ScanBackward is like [Set.Scan] but produces the keys
+// in decreasing order.
+func (s *Set[K]) ScanBackward(lo, hi K) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		s.t.descend(bound[K]{lo, true}, bound[K]{hi, true}, keys(yield))
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intmap_test
+
+import (
+	"container/intmap"
+	"fmt"
+)
+
+func Example() {
+	// Count the uses of each register in a small program.
+	var uses intmap.Map[uint16, int]
+	for _, reg := range []uint16{3, 1, 3, 0, 1, 3} {
+		n, _ := uses.Get(reg)
+		uses.Set(reg, n+1)
+	}
+
+	// While the keys are dense, they are produced in increasing order.
+	for reg, n := range uses.All() {
+		fmt.Printf("r%d: %d\n", reg, n)
+	}
+
+	// Output:
+	// r0: 1
+	// r1: 2
+	// r3: 3
+}
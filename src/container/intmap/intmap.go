@@ -0,0 +1,246 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package intmap implements a map with integer keys that is stored as
+// an array while its keys are small and dense.
+//
+// Many programs use maps keyed by small integers that are mostly
+// consecutive, such as instruction indexes, node numbers in a graph or
+// interned symbol IDs. A [Map] stores such keys in an array indexed by
+// the key, with a bitmap recording which keys are present. This makes
+// lookups and insertions a few instructions each and needs no memory for
+// the keys themselves or for hash buckets.
+//
+// When a key is negative, or so large that the array would be mostly
+// empty, the Map moves its entries into a builtin map and behaves like
+// one from then on. It goes back to the array representation only once
+// it is empty again.
+package intmap
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// Integer is a constraint that permits any integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+const (
+	// minDense is the length up to which the array is always used,
+	// however few keys it holds.
+	minDense = 64
+
+	// density is the factor by which the array may be longer than
+	// the number of keys in the map. If deleting keys makes the array
+	// more than twice that sparse, the map switches to hashing.
+	density = 4
+)
+
+// Map is a map from integers to values.
+// The zero value for Map is an empty map ready to use.
+// A Map must not be copied after first use.
+type Map[K Integer, V any] struct {
+	// While m is nil, the map is dense: the value for key k is vals[k],
+	// and present records which elements of vals hold a value.
+	m       map[K]V
+	vals    []V
+	present []uint64
+	n       int // number of keys in the dense representation
+}
+
+// maxDense returns the longest array allowed for n keys.
+func maxDense(n int) int {
+	return max(minDense, density*n)
+}
+
+// isPresent reports whether vals[i] holds a value.
+func (m *Map[K, V]) isPresent(i int) bool {
+	return m.present[i/64]&(1<<(i%64)) != 0
+}
+
+// index returns the array index for key and whether key is small
+// enough to be stored in an array of length limit.
+func index[K Integer](key K, limit int) (int, bool) {
+	if key < 0 || uint64(key) >= uint64(limit) {
+		return 0, false
+	}
+	return int(key), true
+}
+
+// Len returns the number of keys in m.
+func (m *Map[K, V]) Len() int {
+	if m.m != nil {
+		return len(m.m)
+	}
+	return m.n
+}
+
+// Get returns the value stored for key,
+// and whether key is present in m.
+func (m *Map[K, V]) Get(key K) (value V, ok bool) {
+	if m.m != nil {
+		value, ok = m.m[key]
+		return value, ok
+	}
+	i, ok := index(key, len(m.vals))
+	if !ok || !m.isPresent(i) {
+		return value, false
+	}
+	return m.vals[i], true
+}
+
+// Set sets the value for key.
+func (m *Map[K, V]) Set(key K, value V) {
+	if m.m != nil {
+		m.m[key] = value
+		return
+	}
+	i, ok := index(key, len(m.vals))
+	if !ok {
+		i, ok = index(key, maxDense(m.n+1))
+		if !ok {
+			m.toHashed()
+			m.m[key] = value
+			return
+		}
+		m.grow(i + 1)
+	}
+	if !m.isPresent(i) {
+		m.present[i/64] |= 1 << (i % 64)
+		m.n++
+	}
+	m.vals[i] = value
+}
+
+// grow grows the array so that it has at least n elements.
+func (m *Map[K, V]) grow(n int) {
+	newLen := min(max(n, 2*len(m.vals), minDense), maxDense(m.n+1))
+	if k := K(newLen - 1); k < 0 || uint64(k) != uint64(newLen-1) {
+		// Do not grow past the largest value of K.
+		newLen = n
+	}
+	vals := make([]V, newLen)
+	copy(vals, m.vals)
+	present := make([]uint64, (newLen+63)/64)
+	copy(present, m.present)
+	m.vals, m.present = vals, present
+}
+
+// toHashed moves the entries of m into a builtin map.
+func (m *Map[K, V]) toHashed() {
+	h := make(map[K]V, m.n+1)
+	for i, w := range m.present {
+		for w != 0 {
+			j := i*64 + bits.TrailingZeros64(w)
+			h[K(j)] = m.vals[j]
+			w &= w - 1
+		}
+	}
+	m.m = h
+	m.vals, m.present, m.n = nil, nil, 0
+}
+
+// Delete removes the value for key, if any.
+func (m *Map[K, V]) Delete(key K) {
+	if m.m != nil {
+		delete(m.m, key)
+		if len(m.m) == 0 {
+			m.m = nil
+		}
+		return
+	}
+	i, ok := index(key, len(m.vals))
+	if !ok || !m.isPresent(i) {
+		return
+	}
+	m.present[i/64] &^= 1 << (i % 64)
+	var zero V
+	m.vals[i] = zero
+	m.n--
+	if m.n == 0 {
+		m.vals, m.present = nil, nil
+	} else if len(m.vals) > 2*maxDense(m.n) {
+		m.toHashed()
+	}
+}
+
+// Clear removes all entries from m.
+func (m *Map[K, V]) Clear() {
+	if m.m != nil {
+		// Clear the map rather than just dropping it, so that a range
+		// loop over it that is in progress stops.
+		clear(m.m)
+		m.m = nil
+		return
+	}
+	clear(m.vals)
+	clear(m.present)
+	m.n = 0
+}
+
+// All returns an iterator over the keys and values in m.
+// While m is dense the keys are produced in increasing order;
+// otherwise the order is not specified, as for a builtin map.
+//
+// As with a range loop over a builtin map, an entry deleted during
+// iteration before it is reached is not produced, and an entry added
+// during iteration may or may not be produced. Each key present when
+// the iteration starts is produced at most once.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if m.m != nil {
+			for k, v := range m.m {
+				if !yield(k, v) {
+					return
+				}
+			}
+			return
+		}
+		n := len(m.vals)
+		for i := 0; i < n; i++ {
+			if m.m == nil {
+				// Still dense. Pick up any growth of the array.
+				n = len(m.vals)
+				if i < n && m.isPresent(i) && !yield(K(i), m.vals[i]) {
+					return
+				}
+				continue
+			}
+			// m switched to hashing during the iteration.
+			// Look up the rest of the keys the array had room for.
+			if v, ok := m.m[K(i)]; ok && !yield(K(i), v) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns an iterator over the keys in m.
+// See [Map.All] for the iteration order and the behavior when m is
+// modified during iteration.
+func (m *Map[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range m.All() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the values in m.
+// See [Map.All] for the iteration order and the behavior when m is
+// modified during iteration.
+func (m *Map[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range m.All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
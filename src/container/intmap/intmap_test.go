@@ -0,0 +1,206 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package intmap
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+	"testing/maptest"
+)
+
+// testMap adapts a Map to maptest.Map.
+type testMap struct {
+	Map[int, int]
+}
+
+func (m *testMap) Load(key int) (int, bool) { return m.Get(key) }
+func (m *testMap) Store(key int, value int) { m.Set(key, value) }
+
+func TestMaptest(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		key  func(int) int
+	}{
+		{"dense", func(i int) int { return i }},
+		{"sparse", func(i int) int { return i * 7919 }},
+		{"negative", func(i int) int { return -i }},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			newMap := func() maptest.Map[int, int] { return new(testMap) }
+			value := func(i int) int { return i }
+			if err := maptest.TestMap(newMap, test.key, value); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func checkMap[K Integer](t *testing.T, m *Map[K, string], hashed bool, want ...K) {
+	t.Helper()
+	if got := m.m != nil; got != hashed {
+		t.Errorf("map uses hashing: %v, want %v", got, hashed)
+	}
+	if m.Len() != len(want) {
+		t.Errorf("Len() = %d, want %d", m.Len(), len(want))
+	}
+	for _, k := range want {
+		if v, ok := m.Get(k); !ok || v != fmt.Sprint(k) {
+			t.Errorf("Get(%d) = %q, %v, want %q, true", k, v, ok, fmt.Sprint(k))
+		}
+	}
+	var keys []K
+	for k, v := range m.All() {
+		if v != fmt.Sprint(k) {
+			t.Errorf("All produced %d, %q", k, v)
+		}
+		keys = append(keys, k)
+	}
+	if !hashed && !slices.IsSorted(keys) {
+		t.Errorf("All produced keys %v out of order", keys)
+	}
+	slices.Sort(keys)
+	if !slices.Equal(keys, want) {
+		t.Errorf("All produced keys %v, want %v", keys, want)
+	}
+	if got := slices.Sorted(m.Keys()); !slices.Equal(got, want) {
+		t.Errorf("Keys produced %v, want %v", got, want)
+	}
+	if got := slices.Collect(m.Values()); len(got) != len(want) {
+		t.Errorf("Values produced %d values, want %d", len(got), len(want))
+	}
+}
+
+func TestRepresentation(t *testing.T) {
+	var m Map[int, string]
+	var want []int
+	for i := range 1000 {
+		m.Set(i, fmt.Sprint(i))
+		want = append(want, i)
+	}
+	checkMap(t, &m, false, want...)
+
+	// A key far beyond the others switches to hashing.
+	m.Set(1e6, "1000000")
+	checkMap(t, &m, true, append(want, 1e6)...)
+	if _, ok := m.Get(1e6 + 1); ok {
+		t.Errorf("Get(1e6+1) found a key that was never set")
+	}
+
+	// Emptying the map switches back to the array.
+	m.Delete(1e6)
+	for _, k := range want {
+		m.Delete(k)
+	}
+	checkMap(t, &m, false)
+	m.Set(5, "5")
+	checkMap(t, &m, false, 5)
+
+	// So does a negative key.
+	m.Set(-1, "-1")
+	checkMap(t, &m, true, -1, 5)
+	m.Clear()
+	checkMap(t, &m, false)
+
+	// Deleting most of the keys makes the map sparse.
+	want = want[:0]
+	for i := range 1000 {
+		m.Set(i, fmt.Sprint(i))
+		if i%10 == 0 {
+			want = append(want, i)
+		}
+	}
+	for i := range 1000 {
+		if i%10 != 0 {
+			m.Delete(i)
+		}
+	}
+	checkMap(t, &m, true, want...)
+}
+
+func TestSmallKeyType(t *testing.T) {
+	var m Map[int8, string]
+	var want []int8
+	for i := range math.MaxInt8 + 1 {
+		m.Set(int8(i), fmt.Sprint(i))
+		want = append(want, int8(i))
+	}
+	checkMap(t, &m, false, want...)
+	if len(m.vals) > math.MaxInt8+1 {
+		t.Errorf("array has %d elements, more than there are keys", len(m.vals))
+	}
+
+	var u Map[uint64, string]
+	u.Set(math.MaxUint64, fmt.Sprint(uint64(math.MaxUint64)))
+	u.Set(1, "1")
+	checkMap(t, &u, true, 1, math.MaxUint64)
+}
+
+func TestModifyDuringAll(t *testing.T) {
+	var m Map[int, string]
+	for i := range 10 {
+		m.Set(i, fmt.Sprint(i))
+	}
+	var got []int
+	for k := range m.All() {
+		got = append(got, k)
+		switch k {
+		case 2:
+			m.Delete(3)
+			m.Set(12, "12")
+		case 5:
+			// Switch to hashing halfway through.
+			m.Set(-1, "-1")
+			m.Delete(7)
+		}
+	}
+	// The keys from 0 through 9 are produced in order, skipping 3 and 7.
+	// 12 is produced because it went into the array, -1 is not because
+	// it is not in the range of the array.
+	want := []int{0, 1, 2, 4, 5, 6, 8, 9, 12}
+	if !slices.Equal(got, want) {
+		t.Errorf("All() with modifications produced %v, want %v", got, want)
+	}
+
+	got = got[:0]
+	for k := range m.All() {
+		got = append(got, k)
+		m.Clear()
+	}
+	if len(got) != 1 {
+		t.Errorf("All() with Clear produced %v, want one key", got)
+	}
+	checkMap(t, &m, false)
+}
+
+var sink int
+
+func BenchmarkSetGet(b *testing.B) {
+	const n = 1000
+	b.Run("intmap", func(b *testing.B) {
+		for range b.N {
+			var m Map[int32, int]
+			for i := range int32(n) {
+				m.Set(i, int(i))
+			}
+			for i := range int32(n) {
+				v, _ := m.Get(i)
+				sink += v
+			}
+		}
+	})
+	b.Run("map", func(b *testing.B) {
+		for range b.N {
+			m := make(map[int32]int)
+			for i := range int32(n) {
+				m[i] = int(i)
+			}
+			for i := range int32(n) {
+				sink += m[i]
+			}
+		}
+	})
+}
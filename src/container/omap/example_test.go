@@ -0,0 +1,33 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package omap_test
+
+import (
+	"container/omap"
+	"fmt"
+)
+
+func Example() {
+	var m omap.Map[string, int]
+	m.Set("one", 1)
+	m.Set("two", 2)
+	m.Set("three", 3)
+
+	// Replacing a value keeps its position.
+	m.Set("one", 100)
+
+	// Deleting and setting again moves the key to the end.
+	m.Delete("two")
+	m.Set("two", 2)
+
+	for k, v := range m.All() {
+		fmt.Println(k, v)
+	}
+
+	// Output:
+	// one 100
+	// three 3
+	// two 2
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package omap implements a map that remembers the order in which
+// keys were inserted.
+//
+// A [Map] is a builtin map whose entries are also linked into a
+// doubly linked list in insertion order, so lookups, insertions and
+// deletions take constant time, and iteration produces the entries
+// from the oldest to the newest:
+//
+//	var m omap.Map[string, int]
+//	m.Set("b", 2)
+//	m.Set("a", 1)
+//	for k, v := range m.All() {
+//		fmt.Println(k, v) // prints b 2, then a 1
+//	}
+package omap
+
+import "iter"
+
+// An entry is an entry in a Map.
+type entry[K comparable, V any] struct {
+	// Next and previous pointers in the doubly linked list of entries.
+	// As in container/list, the list is a ring through Map.root.
+	// A deleted entry keeps its pointers so that an iteration
+	// positioned at it can move on to the entries that are still
+	// in the map.
+	next, prev *entry[K, V]
+
+	// deleted reports whether the entry has been removed from the map.
+	deleted bool
+
+	key   K
+	value V
+}
+
+// Map is a map that remembers the order in which keys were inserted.
+// The zero value for Map is an empty map ready to use.
+// A Map must not be copied after first use.
+type Map[K comparable, V any] struct {
+	m    map[K]*entry[K, V]
+	root entry[K, V] // sentinel entry; root.next is the oldest entry and root.prev the newest
+}
+
+// init initializes m on first insertion.
+func (m *Map[K, V]) init() {
+	m.m = make(map[K]*entry[K, V])
+	m.root.next = &m.root
+	m.root.prev = &m.root
+}
+
+// Len returns the number of entries in m.
+func (m *Map[K, V]) Len() int { return len(m.m) }
+
+// Get returns the value stored for key,
+// and whether key is present in m.
+func (m *Map[K, V]) Get(key K) (value V, ok bool) {
+	e, ok := m.m[key]
+	if !ok {
+		return value, false
+	}
+	return e.value, true
+}
+
+// Set sets the value for key. If key is already present, its value is
+// replaced and its position in the insertion order is unchanged.
+// Otherwise key becomes the newest entry in m.
+func (m *Map[K, V]) Set(key K, value V) {
+	if e, ok := m.m[key]; ok {
+		e.value = value
+		return
+	}
+	if m.m == nil {
+		m.init()
+	}
+	e := &entry[K, V]{key: key, value: value, prev: m.root.prev, next: &m.root}
+	e.prev.next = e
+	m.root.prev = e
+	m.m[key] = e
+}
+
+// Delete removes the entry for key, if any.
+// If key is later set again, it becomes the newest entry in m.
+func (m *Map[K, V]) Delete(key K) {
+	e, ok := m.m[key]
+	if !ok {
+		return
+	}
+	delete(m.m, key)
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.deleted = true
+}
+
+// Clear removes all entries from m.
+func (m *Map[K, V]) Clear() {
+	if m.m == nil {
+		return
+	}
+	for e := m.root.next; e != &m.root; e = e.next {
+		e.deleted = true
+	}
+	clear(m.m)
+	m.root.next = &m.root
+	m.root.prev = &m.root
+}
+
+// All returns an iterator over the keys and values in m,
+// from the oldest entry to the newest.
+//
+// As with a range loop over a builtin map, an entry deleted during
+// iteration before it is reached is not produced, and an entry added
+// during iteration may or may not be produced. Each entry is produced
+// at most once.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := m.root.next; e != nil && e != &m.root; e = e.next {
+			if !e.deleted && !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the keys and values in m,
+// from the newest entry to the oldest.
+//
+// An entry deleted during iteration before it is reached is not
+// produced, and an entry added during iteration is not produced.
+func (m *Map[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := m.root.prev; e != nil && e != &m.root; e = e.prev {
+			if !e.deleted && !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns an iterator over the keys in m,
+// from the oldest entry to the newest.
+// See [Map.All] for the behavior when m is modified during iteration.
+func (m *Map[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range m.All() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the values in m,
+// from the oldest entry to the newest.
+// See [Map.All] for the behavior when m is modified during iteration.
+func (m *Map[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range m.All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
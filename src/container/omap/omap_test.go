@@ -0,0 +1,156 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package omap_test
+
+import (
+	"container/omap"
+	"iter"
+	"slices"
+	"strconv"
+	"testing"
+	"testing/maptest"
+)
+
+// testMap adapts a Map to maptest.Map.
+type testMap struct {
+	omap.Map[string, int]
+}
+
+func (m *testMap) Load(key string) (int, bool) { return m.Get(key) }
+func (m *testMap) Store(key string, value int) { m.Set(key, value) }
+
+func TestMaptest(t *testing.T) {
+	newMap := func() maptest.Map[string, int] { return new(testMap) }
+	value := func(i int) int { return i }
+	if err := maptest.TestMap(newMap, strconv.Itoa, value); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func collect(seq iter.Seq2[string, int]) []string {
+	var s []string
+	for k, v := range seq {
+		s = append(s, k+"="+strconv.Itoa(v))
+	}
+	return s
+}
+
+func checkOrder(t *testing.T, m *omap.Map[string, int], want ...string) {
+	t.Helper()
+	if got := collect(m.All()); !slices.Equal(got, want) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+	backward := slices.Clone(want)
+	slices.Reverse(backward)
+	if got := collect(m.Backward()); !slices.Equal(got, backward) {
+		t.Errorf("Backward() = %v, want %v", got, backward)
+	}
+	var keys []string
+	for k := range m.Keys() {
+		keys = append(keys, k)
+	}
+	var values []string
+	for v := range m.Values() {
+		values = append(values, strconv.Itoa(v))
+	}
+	for i, kv := range want {
+		if i >= len(keys) || i >= len(values) || kv != keys[i]+"="+values[i] {
+			t.Errorf("Keys() = %v, Values() = %v, want %v", keys, values, want)
+			break
+		}
+	}
+	if m.Len() != len(want) {
+		t.Errorf("Len() = %d, want %d", m.Len(), len(want))
+	}
+}
+
+func TestOrder(t *testing.T) {
+	var m omap.Map[string, int]
+	checkOrder(t, &m)
+	m.Delete("a")
+	m.Clear()
+	checkOrder(t, &m)
+
+	m.Set("c", 3)
+	m.Set("a", 1)
+	m.Set("b", 2)
+	checkOrder(t, &m, "c=3", "a=1", "b=2")
+
+	m.Set("a", 10)
+	checkOrder(t, &m, "c=3", "a=10", "b=2")
+	if v, ok := m.Get("a"); v != 10 || !ok {
+		t.Errorf(`Get("a") = %d, %v, want 10, true`, v, ok)
+	}
+
+	m.Delete("c")
+	checkOrder(t, &m, "a=10", "b=2")
+	if v, ok := m.Get("c"); ok {
+		t.Errorf(`Get("c") = %d, true after Delete`, v)
+	}
+	m.Set("c", 30)
+	checkOrder(t, &m, "a=10", "b=2", "c=30")
+	m.Delete("c")
+	checkOrder(t, &m, "a=10", "b=2")
+
+	m.Clear()
+	checkOrder(t, &m)
+	m.Set("d", 4)
+	checkOrder(t, &m, "d=4")
+}
+
+func TestModifyDuringAll(t *testing.T) {
+	var m omap.Map[string, int]
+	for i := range 6 {
+		m.Set(strconv.Itoa(i), i)
+	}
+	var got []string
+	for k, v := range m.All() {
+		got = append(got, k+"="+strconv.Itoa(v))
+		switch k {
+		case "0":
+			// Delete the current entry and one not yet reached.
+			m.Delete("0")
+			m.Delete("2")
+		case "1":
+			// Delete the current entry and its successor.
+			m.Delete("1")
+			m.Delete("3")
+			m.Set("6", 6)
+		case "4":
+			m.Set("5", 50)
+		}
+	}
+	want := []string{"0=0", "1=1", "4=4", "5=50", "6=6"}
+	if !slices.Equal(got, want) {
+		t.Errorf("All() with modifications = %v, want %v", got, want)
+	}
+	checkOrder(t, &m, "4=4", "5=50", "6=6")
+
+	got = nil
+	for k, v := range m.Backward() {
+		got = append(got, k+"="+strconv.Itoa(v))
+		if k == "6" {
+			m.Delete("6")
+			m.Delete("5")
+			m.Set("7", 7)
+		}
+	}
+	want = []string{"6=6", "4=4"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Backward() with modifications = %v, want %v", got, want)
+	}
+	checkOrder(t, &m, "4=4", "7=7")
+
+	got = nil
+	for k, v := range m.All() {
+		got = append(got, k+"="+strconv.Itoa(v))
+		m.Clear()
+	}
+	want = []string{"4=4"}
+	if !slices.Equal(got, want) {
+		t.Errorf("All() with Clear = %v, want %v", got, want)
+	}
+	checkOrder(t, &m)
+}
@@ -417,6 +417,39 @@ func BenchmarkUnmarshalMap(b *testing.B) {
 	})
 }
 
+func BenchmarkUnmarshalLargeMap(b *testing.B) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i := range 10000 {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, `"key%d":%d`, i, i)
+	}
+	buf.WriteByte('}')
+	data := buf.Bytes()
+	b.Run("Map", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+		for range b.N {
+			var m map[string]int
+			if err := Unmarshal(data, &m); err != nil {
+				b.Fatalf("Unmarshal error: %v", err)
+			}
+		}
+	})
+	b.Run("Interface", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+		for range b.N {
+			var m any
+			if err := Unmarshal(data, &m); err != nil {
+				b.Fatalf("Unmarshal error: %v", err)
+			}
+		}
+	})
+}
+
 func BenchmarkIssue10335(b *testing.B) {
 	b.ReportAllocs()
 	j := []byte(`{"a":{ }}`)
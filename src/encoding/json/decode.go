@@ -217,6 +217,7 @@ type decodeState struct {
 	savedError            error
 	useNumber             bool
 	disallowUnknownFields bool
+	lenBudget             int // bytes objectLen may still examine
 }
 
 // readIndex returns the position of the last byte read.
@@ -233,6 +234,7 @@ func (d *decodeState) init(data []byte) *decodeState {
 	d.data = data
 	d.off = 0
 	d.savedError = nil
+	d.lenBudget = 2 * len(data)
 	if d.errorContext != nil {
 		d.errorContext.Struct = nil
 		// Reuse the allocated space for the FieldStack slice.
@@ -276,6 +278,54 @@ func (d *decodeState) skip() {
 	}
 }
 
+// objectLen returns the number of members of the object whose opening
+// brace has just been read, so that maps can be allocated at their final
+// size. It relies on d.data having been validated already, and is much
+// cheaper than skipping the object with the scanner.
+//
+// Counting the members of nested objects examines the same bytes again,
+// so all calls together examine at most d.lenBudget bytes, to keep
+// decoding deeply nested objects linear in the size of the input.
+// Once the budget is spent, objectLen returns a lower bound.
+func (d *decodeState) objectLen() int {
+	if d.lenBudget <= 0 {
+		return 0
+	}
+	data := d.data
+	if end := d.off + d.lenBudget; end < len(data) {
+		data = data[:end]
+	}
+	n, depth := 0, 0
+	i := d.off
+scan:
+	for ; i < len(data); i++ {
+		switch data[i] {
+		case '"':
+			if depth == 0 && n == 0 {
+				n = 1
+			}
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' {
+					i++
+				}
+			}
+		case '{', '[':
+			depth++
+		case '}', ']':
+			if depth == 0 {
+				break scan
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				n++
+			}
+		}
+	}
+	d.lenBudget -= i - d.off
+	return n
+}
+
 // scanNext processes the byte at d.data[d.off].
 func (d *decodeState) scanNext() {
 	if d.off < len(d.data) {
@@ -640,7 +690,7 @@ func (d *decodeState) object(v reflect.Value) error {
 			}
 		}
 		if v.IsNil() {
-			v.Set(reflect.MakeMap(t))
+			v.Set(reflect.MakeMapWithSize(t, d.objectLen()))
 		}
 	case reflect.Struct:
 		fields = cachedTypeFields(t)
@@ -1064,7 +1114,7 @@ func (d *decodeState) arrayInterface() []any {
 
 // objectInterface is like object but returns map[string]interface{}.
 func (d *decodeState) objectInterface() map[string]any {
-	m := make(map[string]any)
+	m := make(map[string]any, d.objectLen())
 	for {
 		// Read opening " of string key or closing }.
 		d.scanWhile(scanSkipSpace)
@@ -2619,3 +2619,50 @@ func TestUnmarshalMaxDepth(t *testing.T) {
 		}
 	}
 }
+
+func TestObjectLen(t *testing.T) {
+	tests := []struct {
+		CaseName
+		data string
+		want int
+	}{
+		{Name(""), `{}`, 0},
+		{Name(""), `{ }`, 0},
+		{Name(""), `{"a":1}`, 1},
+		{Name(""), `{ "a" : 1 , "b" : 2 }`, 2},
+		{Name(""), `{"a":[1,2,{"x":3,"y":4}],"b":{"c":",","d":"}"},"e":null}`, 3},
+		{Name(""), `{"a\"}":"}","b,":"\\","c{":"\\\"]"}`, 3},
+		{Name(""), `{"a":{}} , {"b":1,"c":2}`, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			d := new(decodeState).init([]byte(tt.data))
+			d.off = 1
+			if got := d.objectLen(); got != tt.want {
+				t.Errorf("%s: objectLen(%s) = %d, want %d", tt.Where, tt.data, got, tt.want)
+			}
+		})
+	}
+
+	// Counting the members of each of many nested objects must not
+	// examine more than twice the input.
+	const depth = 10000
+	data := []byte(strings.Repeat(`{"a":`, depth) + `0` + strings.Repeat(`}`, depth))
+	d := new(decodeState).init(data)
+	for i := range depth {
+		d.off = 5*i + 1
+		d.objectLen()
+	}
+	if d.lenBudget < 0 {
+		t.Errorf("objectLen examined %d bytes of a %d-byte input", 2*len(data)-d.lenBudget, len(data))
+	}
+
+	var m map[string]map[string]int
+	if err := Unmarshal([]byte(`{"x":{"a":1,"b":2},"y,}":{},"z":{"c\"":3}}`), &m); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	want := map[string]map[string]int{"x": {"a": 1, "b": 2}, "y,}": {}, "z": {`c"`: 3}}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("Unmarshal = %v, want %v", m, want)
+	}
+}
@@ -93,8 +93,8 @@ var depsRules = `
 	< internal/race
 	< internal/msan
 	< internal/asan
-	< internal/weak
 	< sync
+	< internal/weak
 	< internal/bisect
 	< internal/godebug
 	< internal/reflectlite
@@ -112,6 +112,15 @@ var depsRules = `
 	< sort
 	< container/heap;
 
+	iter
+	< container/omap;
+
+	cmp, iter, slices
+	< container/btree;
+
+	iter, math/bits
+	< container/intmap;
+
 	RUNTIME
 	< io;
 
@@ -160,9 +169,6 @@ var depsRules = `
 	MATH
 	< runtime/metrics;
 
-	RUNTIME, math/rand/v2
-	< internal/concurrent;
-
 	MATH, unicode/utf8
 	< strconv;
 
@@ -176,7 +182,7 @@ var depsRules = `
 	bufio, path, strconv
 	< STR;
 
-	RUNTIME, internal/concurrent
+	RUNTIME
 	< unique;
 
 	# OS is basic OS access, including helpers (path/filepath, os/exec, etc).
@@ -258,7 +264,7 @@ var depsRules = `
 	# hashes
 	io
 	< hash
-	< hash/adler32, hash/crc32, hash/crc64, hash/fnv;
+	< hash/adler32, hash/blake3, hash/crc32, hash/crc64, hash/fletcher, hash/fnv, hash/rolling;
 
 	# math/big
 	FMT, math/rand
@@ -608,6 +614,9 @@ var depsRules = `
 	< testing/iotest
 	< testing/fstest;
 
+	FMT, iter, math/rand/v2
+	< testing/maptest;
+
 	FMT, flag, math/rand
 	< testing/quick;
 
@@ -48,6 +48,10 @@ func New() hash.Hash32 {
 	return d
 }
 
+func init() {
+	hash.Register("adler32", func() hash.Hash { return New() })
+}
+
 func (d *digest) Size() int { return Size }
 
 func (d *digest) BlockSize() int { return 4 }
@@ -75,6 +79,22 @@ func (d *digest) UnmarshalBinary(b []byte) error {
 	return nil
 }
 
+// This file makes use of functions implemented in architecture-specific files.
+// The interface that they implement is as follows:
+//
+//	// archAvailable reports whether an architecture-specific Adler-32
+//	// algorithm is available.
+//	archAvailable() bool
+//
+//	// archUpdate adds p to the unreduced sums s1 and s2. len(p) is a
+//	// positive multiple of archBlockSize and at most nmax.
+//	archUpdate(s1, s2 uint32, p []byte) (uint32, uint32)
+
+// archBlockSize is the granularity of the data passed to archUpdate.
+const archBlockSize = 32
+
+var haveArch = archAvailable()
+
 // Add p to the running checksum d.
 func update(d digest, p []byte) digest {
 	s1, s2 := uint32(d&0xffff), uint32(d>>16)
@@ -83,6 +103,11 @@ func update(d digest, p []byte) digest {
 		if len(p) > nmax {
 			p, q = p[:nmax], p[nmax:]
 		}
+		if haveArch && len(p) >= archBlockSize {
+			n := len(p) &^ (archBlockSize - 1)
+			s1, s2 = archUpdate(s1, s2, p[:n])
+			p = p[n:]
+		}
 		for len(p) >= 4 {
 			s1 += uint32(p[0])
 			s2 += s1
@@ -0,0 +1,24 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// AMD64-specific vectorized Adler-32 algorithm. See adler32.go for a
+// description of the interface that each architecture-specific file
+// implements.
+
+package adler32
+
+import "internal/cpu"
+
+// updateAVX2 is defined in adler32_amd64.s and uses AVX2 instructions.
+//
+//go:noescape
+func updateAVX2(s1, s2 uint32, p []byte) (uint32, uint32)
+
+func archAvailable() bool {
+	return cpu.X86.HasAVX2
+}
+
+func archUpdate(s1, s2 uint32, p []byte) (uint32, uint32) {
+	return updateAVX2(s1, s2, p)
+}
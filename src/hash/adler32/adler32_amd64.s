@@ -0,0 +1,90 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+#include "textflag.h"
+
+// updateAVX2 adds p to the unreduced Adler-32 sums s1 and s2.
+// len(p) must be a positive multiple of 32 and small enough for the
+// sums not to overflow (see nmax in adler32.go).
+//
+// For each 32-byte block b, s2 grows by 32*s1 plus the sum of
+// (32-i)*b[i], and s1 grows by the sum of b[i]. The loop keeps
+// per-lane partial sums in vector registers and accumulates the
+// 32*s1 terms in Y2, so that no reduction is needed inside it.
+//
+// func updateAVX2(s1, s2 uint32, p []byte) (uint32, uint32)
+TEXT ·updateAVX2(SB),NOSPLIT,$0-40
+	MOVL s1+0(FP), AX
+	MOVL s2+4(FP), BX
+	MOVQ p_base+8(FP), SI
+	MOVQ p_len+16(FP), CX
+	SHRQ $5, CX // number of blocks
+
+	// s2 += 32 * blocks * s1 for the initial value of s1.
+	MOVL CX, DX
+	SHLL $5, DX
+	IMULL AX, DX
+	ADDL DX, BX
+
+	VPXOR Y0, Y0, Y0 // s1 partial sums
+	VPXOR Y1, Y1, Y1 // s2 partial sums
+	VPXOR Y2, Y2, Y2 // sum of s1 partial sums at the start of each block
+	VPXOR Y3, Y3, Y3 // zero
+	VMOVDQU weights<>(SB), Y4
+	VMOVDQU ones<>(SB), Y5
+
+loop:
+	VMOVDQU (SI), Y6
+	VPADDD Y0, Y2, Y2
+	// Y7 = sum of the bytes, in four 64-bit lanes.
+	VPSADBW Y3, Y6, Y7
+	VPADDD Y7, Y0, Y0
+	// Y7 = weighted sum of the bytes, in eight 32-bit lanes.
+	VPMADDUBSW Y4, Y6, Y7
+	VPMADDWD Y5, Y7, Y7
+	VPADDD Y7, Y1, Y1
+	ADDQ $32, SI
+	DECQ CX
+	JNZ loop
+
+	VPSLLD $5, Y2, Y2
+	VPADDD Y2, Y1, Y1
+
+	// Add the lanes of Y0 to s1.
+	VEXTRACTI128 $1, Y0, X7
+	VPADDD X7, X0, X0
+	VPSHUFD $0x4e, X0, X7
+	VPADDD X7, X0, X0
+	VPSHUFD $0xb1, X0, X7
+	VPADDD X7, X0, X0
+	VMOVD X0, DX
+	ADDL DX, AX
+
+	// Add the lanes of Y1 to s2.
+	VEXTRACTI128 $1, Y1, X7
+	VPADDD X7, X1, X1
+	VPSHUFD $0x4e, X1, X7
+	VPADDD X7, X1, X1
+	VPSHUFD $0xb1, X1, X7
+	VPADDD X7, X1, X1
+	VMOVD X1, DX
+	ADDL DX, BX
+
+	VZEROUPPER
+	MOVL AX, ret+32(FP)
+	MOVL BX, ret1+36(FP)
+	RET
+
+// Weights 32, 31, ..., 1 for the bytes of a block.
+DATA weights<>+0x00(SB)/8, $0x191a1b1c1d1e1f20
+DATA weights<>+0x08(SB)/8, $0x1112131415161718
+DATA weights<>+0x10(SB)/8, $0x090a0b0c0d0e0f10
+DATA weights<>+0x18(SB)/8, $0x0102030405060708
+GLOBL weights<>(SB), RODATA, $32
+
+DATA ones<>+0x00(SB)/8, $0x0001000100010001
+DATA ones<>+0x08(SB)/8, $0x0001000100010001
+DATA ones<>+0x10(SB)/8, $0x0001000100010001
+DATA ones<>+0x18(SB)/8, $0x0001000100010001
+GLOBL ones<>(SB), RODATA, $32
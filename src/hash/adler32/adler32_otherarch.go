@@ -0,0 +1,11 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !amd64
+
+package adler32
+
+func archAvailable() bool { return false }
+
+func archUpdate(s1, s2 uint32, p []byte) (uint32, uint32) { panic("not available") }
@@ -90,6 +90,41 @@ func TestGolden(t *testing.T) {
 	}
 }
 
+func TestArch(t *testing.T) {
+	if !haveArch {
+		t.Skip("no architecture-specific implementation")
+	}
+	// All-0xff input maximizes the sums, checking that the chunking
+	// by nmax does not overflow.
+	for _, fill := range []byte{0x00, 0x5a, 0xff} {
+		buf := make([]byte, 3*nmax+100)
+		for i := range buf {
+			if fill == 0x5a {
+				buf[i] = byte(i*31 + i>>8)
+			} else {
+				buf[i] = fill
+			}
+		}
+		for _, n := range []int{0, 1, 31, 32, 33, 63, 64, 65, 1000, nmax - 1, nmax, nmax + 1, 2*nmax + 33, 3 * nmax} {
+			for _, off := range []int{0, 1, 7} {
+				p := buf[off : off+n]
+				want := checksum(p)
+				if got := Checksum(p); got != want {
+					t.Errorf("Checksum(%d bytes of %#x at offset %d) = %#x, want %#x", n, fill, off, got, want)
+				}
+				// Starting from a non-trivial state.
+				d := digest(0xfff0fff0)
+				haveArch = false
+				want = uint32(update(d, p))
+				haveArch = true
+				if got := uint32(update(d, p)); got != want {
+					t.Errorf("update(%#x, %d bytes of %#x at offset %d) = %#x, want %#x", d, n, fill, off, got, want)
+				}
+			}
+		}
+	}
+}
+
 func TestGoldenMarshal(t *testing.T) {
 	for _, g := range golden {
 		h := New()
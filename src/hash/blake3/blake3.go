@@ -0,0 +1,414 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package blake3 implements the BLAKE3 hash function.
+//
+// BLAKE3 is a fast cryptographic hash with a Merkle tree structure.
+// Besides regular hashing, it supports keyed hashing (a MAC or PRF),
+// key derivation, and an extendable output mode (XOF) that produces
+// output of any length. See https://github.com/BLAKE3-team/BLAKE3-specs
+// for the specification.
+package blake3
+
+import (
+	"errors"
+	"hash"
+	"internal/byteorder"
+	"io"
+	"math/bits"
+)
+
+const (
+	// Size is the size, in bytes, of a default BLAKE3 checksum.
+	Size = 32
+
+	// KeySize is the size, in bytes, of a BLAKE3 key.
+	KeySize = 32
+
+	// BlockSize is the block size, in bytes, of the BLAKE3 hash function.
+	BlockSize = 64
+
+	// chunkLen is the size of the leaves of the hash tree.
+	chunkLen = 1024
+
+	// maxDepth is the maximum depth of the hash tree, enough for
+	// 2**64 bytes of input.
+	maxDepth = 54
+)
+
+// Domain separation flags.
+const (
+	flagChunkStart = 1 << iota
+	flagChunkEnd
+	flagParent
+	flagRoot
+	flagKeyedHash
+	flagDeriveKeyContext
+	flagDeriveKeyMaterial
+)
+
+var iv = [8]uint32{
+	0x6A09E667, 0xBB67AE85, 0x3C6EF372, 0xA54FF53A,
+	0x510E527F, 0x9B05688C, 0x1F83D9AB, 0x5BE0CD19,
+}
+
+// Digest represents the partial evaluation of a BLAKE3 hash.
+// It implements [hash.Hash], [encoding.BinaryMarshaler] and
+// [encoding.BinaryUnmarshaler].
+type Digest struct {
+	key   [8]uint32
+	flags uint32
+
+	// State of the current chunk.
+	cv               [8]uint32
+	chunkCounter     uint64
+	block            [BlockSize]byte
+	blockLen         int
+	blocksCompressed int
+
+	// Chaining values of completed subtrees, one per set bit of
+	// chunkCounter, from the largest subtree to the smallest.
+	stack    [maxDepth][8]uint32
+	stackLen int
+}
+
+var _ hash.Hash = (*Digest)(nil)
+
+// New returns a new [Digest] computing the BLAKE3 hash.
+func New() *Digest {
+	d := &Digest{key: iv}
+	d.Reset()
+	return d
+}
+
+func init() {
+	hash.Register("blake3", func() hash.Hash { return New() })
+}
+
+// NewKeyed returns a new [Digest] computing the BLAKE3 keyed hash,
+// which can be used as a MAC or PRF. The key must be [KeySize] bytes
+// long and should be uniformly random.
+func NewKeyed(key []byte) (*Digest, error) {
+	if len(key) != KeySize {
+		return nil, errors.New("blake3: invalid key size")
+	}
+	d := &Digest{flags: flagKeyedHash}
+	for i := range d.key {
+		d.key[i] = byteorder.LeUint32(key[4*i:])
+	}
+	d.Reset()
+	return d, nil
+}
+
+// NewDeriveKey returns a new [Digest] in key derivation mode. The key
+// material is written to the returned Digest, and the derived key is
+// read from its sum or [Digest.XOF].
+//
+// The context string should be hardcoded, globally unique and
+// application specific, for example
+// "example.com 2026-01-01 12:00:00 session tokens v1". It must not
+// contain variable data such as salts, nonces or identifiers.
+func NewDeriveKey(context string) *Digest {
+	c := &Digest{key: iv, flags: flagDeriveKeyContext}
+	c.Reset()
+	io.WriteString(c, context)
+	var key [KeySize]byte
+	o := c.rootOutput()
+	o.readAt(key[:], 0)
+
+	d := &Digest{flags: flagDeriveKeyMaterial}
+	for i := range d.key {
+		d.key[i] = byteorder.LeUint32(key[4*i:])
+	}
+	d.Reset()
+	return d
+}
+
+// Sum256 returns the default-length BLAKE3 hash of data.
+func Sum256(data []byte) [Size]byte {
+	var d Digest
+	d.key = iv
+	d.Reset()
+	d.Write(data)
+	var sum [Size]byte
+	o := d.rootOutput()
+	o.readAt(sum[:], 0)
+	return sum
+}
+
+// DeriveKey fills out with a key derived from the key material
+// using the given context string. See [NewDeriveKey] for the
+// requirements on the context string.
+func DeriveKey(out []byte, context string, material []byte) {
+	d := NewDeriveKey(context)
+	d.Write(material)
+	o := d.rootOutput()
+	o.readAt(out, 0)
+}
+
+// Size returns the length of the default output, [Size].
+func (d *Digest) Size() int { return Size }
+
+// BlockSize returns [BlockSize].
+func (d *Digest) BlockSize() int { return BlockSize }
+
+// Reset resets the Digest to its initial state.
+// The key, if any, is retained.
+func (d *Digest) Reset() {
+	d.cv = d.key
+	d.chunkCounter = 0
+	d.block = [BlockSize]byte{}
+	d.blockLen = 0
+	d.blocksCompressed = 0
+	d.stackLen = 0
+}
+
+// currentChunkLen returns the number of input bytes in the current chunk.
+func (d *Digest) currentChunkLen() int {
+	return d.blocksCompressed*BlockSize + d.blockLen
+}
+
+func (d *Digest) startFlag() uint32 {
+	if d.blocksCompressed == 0 {
+		return flagChunkStart
+	}
+	return 0
+}
+
+// Write adds more data to the running hash. It never returns an error.
+func (d *Digest) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		// A full chunk is only finalized once more input arrives,
+		// because the last chunk is compressed with the root flag.
+		if d.currentChunkLen() == chunkLen {
+			out := d.chunkOutput()
+			d.pushChunk(out.chainingValue(), d.chunkCounter+1)
+			d.cv = d.key
+			d.chunkCounter++
+			d.block = [BlockSize]byte{}
+			d.blockLen = 0
+			d.blocksCompressed = 0
+		}
+		// Likewise, a full block is only compressed once more
+		// input arrives.
+		if d.blockLen == BlockSize {
+			var m [16]uint32
+			wordsFromBlock(&m, &d.block)
+			out := compress(&d.cv, &m, d.chunkCounter, BlockSize, d.flags|d.startFlag())
+			copy(d.cv[:], out[:8])
+			d.blocksCompressed++
+			d.block = [BlockSize]byte{}
+			d.blockLen = 0
+		}
+		k := copy(d.block[d.blockLen:], p)
+		d.blockLen += k
+		p = p[k:]
+	}
+	return n, nil
+}
+
+// pushChunk adds the chaining value of a completed chunk to the stack,
+// merging completed subtrees. total is the number of chunks so far.
+func (d *Digest) pushChunk(cv [8]uint32, total uint64) {
+	// Each trailing zero bit of total is a subtree that is now complete.
+	for total&1 == 0 {
+		d.stackLen--
+		o := parentOutput(&d.stack[d.stackLen], &cv, &d.key, d.flags)
+		cv = o.chainingValue()
+		total >>= 1
+	}
+	d.stack[d.stackLen] = cv
+	d.stackLen++
+}
+
+// chunkOutput returns the output of the current chunk.
+func (d *Digest) chunkOutput() output {
+	o := output{
+		cv:       d.cv,
+		counter:  d.chunkCounter,
+		blockLen: uint32(d.blockLen),
+		flags:    d.flags | d.startFlag() | flagChunkEnd,
+	}
+	wordsFromBlock(&o.block, &d.block)
+	return o
+}
+
+// rootOutput returns the output of the root node of the hash tree
+// over the data written so far. It does not change the state of d.
+func (d *Digest) rootOutput() output {
+	o := d.chunkOutput()
+	for i := d.stackLen - 1; i >= 0; i-- {
+		cv := o.chainingValue()
+		o = parentOutput(&d.stack[i], &cv, &d.key, d.flags)
+	}
+	return o
+}
+
+// Sum appends the default-length hash of the data written so far to b
+// and returns the resulting slice. It does not change the underlying
+// hash state.
+func (d *Digest) Sum(b []byte) []byte {
+	var sum [Size]byte
+	o := d.rootOutput()
+	o.readAt(sum[:], 0)
+	return append(b, sum[:]...)
+}
+
+// XOF returns a reader for the extended output of the hash of the data
+// written so far. The first [Size] bytes read are the same as the
+// value returned by Sum. Later writes to d do not affect the reader.
+func (d *Digest) XOF() *XOF {
+	return &XOF{out: d.rootOutput()}
+}
+
+// XOF is a reader for the extended output of a BLAKE3 hash.
+// It implements [io.Reader] and [io.ReaderAt], producing up to
+// 2**64 bytes of output.
+type XOF struct {
+	out output
+	off uint64
+}
+
+// Read fills p with the next len(p) bytes of output.
+// It always returns len(p), nil.
+func (x *XOF) Read(p []byte) (int, error) {
+	x.out.readAt(p, x.off)
+	x.off += uint64(len(p))
+	return len(p), nil
+}
+
+// ReadAt fills p with len(p) bytes of output starting at offset off.
+// It does not change the offset used by Read.
+func (x *XOF) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("blake3: negative offset")
+	}
+	x.out.readAt(p, uint64(off))
+	return len(p), nil
+}
+
+// output is the input to the final compression of a node, from which
+// its chaining value or the root output can be computed.
+type output struct {
+	cv       [8]uint32
+	block    [16]uint32
+	counter  uint64
+	blockLen uint32
+	flags    uint32
+}
+
+func parentOutput(left, right, key *[8]uint32, flags uint32) output {
+	o := output{
+		cv:       *key,
+		blockLen: BlockSize,
+		flags:    flags | flagParent,
+	}
+	copy(o.block[:8], left[:])
+	copy(o.block[8:], right[:])
+	return o
+}
+
+func (o *output) chainingValue() [8]uint32 {
+	out := compress(&o.cv, &o.block, o.counter, o.blockLen, o.flags)
+	return [8]uint32(out[:8])
+}
+
+// readAt fills p with root output bytes starting at offset off.
+func (o *output) readAt(p []byte, off uint64) {
+	counter := off / BlockSize
+	skip := int(off % BlockSize)
+	var buf [BlockSize]byte
+	for len(p) > 0 {
+		words := compress(&o.cv, &o.block, counter, o.blockLen, o.flags|flagRoot)
+		for i, w := range words {
+			byteorder.LePutUint32(buf[4*i:], w)
+		}
+		n := copy(p, buf[skip:])
+		p = p[n:]
+		skip = 0
+		counter++
+	}
+}
+
+func wordsFromBlock(m *[16]uint32, b *[BlockSize]byte) {
+	for i := range m {
+		m[i] = byteorder.LeUint32(b[4*i:])
+	}
+}
+
+const (
+	magic         = "b3\x01"
+	marshaledSize = len(magic) + 8*4 + 4 + 8*4 + 8 + BlockSize + 1 + 1 + 1 + maxDepth*8*4
+)
+
+// MarshalBinary implements [encoding.BinaryMarshaler].
+func (d *Digest) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, marshaledSize)
+	b = append(b, magic...)
+	for _, w := range d.key {
+		b = byteorder.BeAppendUint32(b, w)
+	}
+	b = byteorder.BeAppendUint32(b, d.flags)
+	for _, w := range d.cv {
+		b = byteorder.BeAppendUint32(b, w)
+	}
+	b = byteorder.BeAppendUint64(b, d.chunkCounter)
+	b = append(b, d.block[:]...)
+	b = append(b, byte(d.blockLen), byte(d.blocksCompressed), byte(d.stackLen))
+	for i := range d.stack {
+		for _, w := range d.stack[i] {
+			b = byteorder.BeAppendUint32(b, w)
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler].
+// The state must have been produced by a Digest with the same
+// mode and key or context.
+func (d *Digest) UnmarshalBinary(b []byte) error {
+	if len(b) < len(magic) || string(b[:len(magic)]) != magic {
+		return errors.New("blake3: invalid hash state identifier")
+	}
+	if len(b) != marshaledSize {
+		return errors.New("blake3: invalid hash state size")
+	}
+	b = b[len(magic):]
+	for _, w := range d.key {
+		if byteorder.BeUint32(b) != w {
+			return errors.New("blake3: keys do not match")
+		}
+		b = b[4:]
+	}
+	if byteorder.BeUint32(b) != d.flags {
+		return errors.New("blake3: modes do not match")
+	}
+	b = b[4:]
+	var s Digest
+	s.key, s.flags = d.key, d.flags
+	for i := range s.cv {
+		s.cv[i] = byteorder.BeUint32(b)
+		b = b[4:]
+	}
+	s.chunkCounter = byteorder.BeUint64(b)
+	b = b[8:]
+	b = b[copy(s.block[:], b):]
+	s.blockLen, s.blocksCompressed, s.stackLen = int(b[0]), int(b[1]), int(b[2])
+	b = b[3:]
+	// The stack holds one chaining value per bit set in the number
+	// of completed chunks.
+	if s.blockLen > BlockSize || s.blocksCompressed >= chunkLen/BlockSize ||
+		s.chunkCounter >= 1<<maxDepth || s.stackLen != bits.OnesCount64(s.chunkCounter) {
+		return errors.New("blake3: invalid hash state")
+	}
+	for i := range s.stack {
+		for j := range s.stack[i] {
+			s.stack[i][j] = byteorder.BeUint32(b)
+			b = b[4:]
+		}
+	}
+	*d = s
+	return nil
+}
@@ -0,0 +1,217 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package blake3
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"internal/byteorder"
+	"io"
+	"os"
+	"strconv"
+	"testing"
+)
+
+type vectors struct {
+	Key           string
+	ContextString string `json:"context_string"`
+	Cases         []struct {
+		InputLen  int    `json:"input_len"`
+		Hash      string `json:"hash"`
+		KeyedHash string `json:"keyed_hash"`
+		DeriveKey string `json:"derive_key"`
+	}
+}
+
+// loadVectors reads the official BLAKE3 test vectors.
+func loadVectors(t *testing.T) *vectors {
+	data, err := os.ReadFile("testdata/vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := new(vectors)
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func vectorInput(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i % 251)
+	}
+	return b
+}
+
+func fromHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// checkDigest checks the sum, extended output, and incremental writes
+// of the digest returned by newDigest against want.
+func checkDigest(t *testing.T, name string, newDigest func() *Digest, input, want []byte) {
+	t.Helper()
+	d := newDigest()
+	d.Write(input)
+	if got := d.Sum(nil); !bytes.Equal(got, want[:Size]) {
+		t.Errorf("%s: Sum = %x, want %x", name, got, want[:Size])
+	}
+	got := make([]byte, len(want))
+	io.ReadFull(d.XOF(), got)
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s: XOF = %x, want %x", name, got, want)
+	}
+	// Read the extended output at an odd offset and in odd pieces.
+	x := d.XOF()
+	x.ReadAt(got[:len(want)-7], 7)
+	if !bytes.Equal(got[:len(want)-7], want[7:]) {
+		t.Errorf("%s: XOF.ReadAt(7) = %x, want %x", name, got[:len(want)-7], want[7:])
+	}
+	for i := 0; i < len(want); i += 13 {
+		x.Read(got[i:min(i+13, len(want))])
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s: XOF read in pieces = %x, want %x", name, got, want)
+	}
+
+	for _, step := range []int{1, 63, 64, 65, 1023, 1024, 1025} {
+		d := newDigest()
+		for p := input; len(p) > 0; {
+			n := min(step, len(p))
+			d.Write(p[:n])
+			p = p[n:]
+		}
+		if got := d.Sum(nil); !bytes.Equal(got, want[:Size]) {
+			t.Errorf("%s: Sum with writes of %d bytes = %x, want %x", name, step, got, want[:Size])
+		}
+	}
+}
+
+func TestVectors(t *testing.T) {
+	v := loadVectors(t)
+	for _, c := range v.Cases {
+		input := vectorInput(c.InputLen)
+		checkDigest(t, "hash", New, input, fromHex(t, c.Hash))
+		checkDigest(t, "keyed", func() *Digest {
+			d, err := NewKeyed([]byte(v.Key))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return d
+		}, input, fromHex(t, c.KeyedHash))
+		checkDigest(t, "derive", func() *Digest { return NewDeriveKey(v.ContextString) }, input, fromHex(t, c.DeriveKey))
+
+		want := fromHex(t, c.Hash)
+		if got := Sum256(input); !bytes.Equal(got[:], want[:Size]) {
+			t.Errorf("Sum256(len %d) = %x, want %x", c.InputLen, got, want[:Size])
+		}
+		want = fromHex(t, c.DeriveKey)
+		got := make([]byte, len(want))
+		DeriveKey(got, v.ContextString, input)
+		if !bytes.Equal(got, want) {
+			t.Errorf("DeriveKey(len %d) = %x, want %x", c.InputLen, got, want)
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	d, err := NewKeyed([]byte("whats the Elvish word for friend"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := d.Sum(nil)
+	d.Write(vectorInput(5000))
+	d.Reset()
+	if got := d.Sum(nil); !bytes.Equal(got, want) {
+		t.Errorf("Sum after Reset = %x, want %x", got, want)
+	}
+}
+
+func TestNewKeyedInvalid(t *testing.T) {
+	if _, err := NewKeyed(make([]byte, KeySize-1)); err == nil {
+		t.Errorf("NewKeyed with short key succeeded")
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	input := vectorInput(10000)
+	for _, split := range []int{0, 1, 64, 1024, 1025, 4097, 9999} {
+		d := New()
+		d.Write(input[:split])
+		state, err := d.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		d2 := New()
+		if err := d2.UnmarshalBinary(state); err != nil {
+			t.Fatal(err)
+		}
+		d2.Write(input[split:])
+		if got, want := d2.Sum(nil), Sum256(input); !bytes.Equal(got, want[:]) {
+			t.Errorf("split %d: Sum after UnmarshalBinary = %x, want %x", split, got, want)
+		}
+	}
+
+	state, err := New().MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyed, _ := NewKeyed(make([]byte, KeySize))
+	if err := keyed.UnmarshalBinary(state); err == nil {
+		t.Errorf("UnmarshalBinary into keyed Digest succeeded")
+	}
+}
+
+func TestUnmarshalCorrupt(t *testing.T) {
+	d := New()
+	d.Write(vectorInput(2048)) // one completed chunk on the stack
+	state, err := d.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	counterOff := len(magic) + 8*4 + 4 + 8*4
+	stackLenOff := counterOff + 8 + BlockSize + 2
+	for _, tt := range []struct {
+		name     string
+		counter  uint64
+		stackLen byte
+	}{
+		{"empty stack", 1, 0},
+		{"stack too long", 1, 2},
+		{"full stack", 1<<maxDepth - 2, maxDepth},
+		{"counter too large", 1 << maxDepth, 1},
+	} {
+		b := bytes.Clone(state)
+		byteorder.BePutUint64(b[counterOff:], tt.counter)
+		b[stackLenOff] = tt.stackLen
+		d := New()
+		if err := d.UnmarshalBinary(b); err == nil {
+			t.Errorf("%s: UnmarshalBinary succeeded", tt.name)
+			// Writing must not panic either way.
+			d.Write(vectorInput(4096))
+		}
+	}
+}
+
+func BenchmarkWrite(b *testing.B) {
+	for _, size := range []int{64, 1024, 8192} {
+		data := make([]byte, size)
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			d := New()
+			b.SetBytes(int64(size))
+			for range b.N {
+				d.Reset()
+				d.Write(data)
+				d.Sum(nil)
+			}
+		})
+	}
+}
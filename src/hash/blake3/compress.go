@@ -0,0 +1,58 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package blake3
+
+import "math/bits"
+
+// compress is the BLAKE3 compression function. It returns the full
+// 16-word state; the first 8 words are the new chaining value.
+func compress(cv *[8]uint32, m *[16]uint32, counter uint64, blockLen, flags uint32) [16]uint32 {
+	v0, v1, v2, v3 := cv[0], cv[1], cv[2], cv[3]
+	v4, v5, v6, v7 := cv[4], cv[5], cv[6], cv[7]
+	v8, v9, v10, v11 := iv[0], iv[1], iv[2], iv[3]
+	v12, v13, v14, v15 := uint32(counter), uint32(counter>>32), blockLen, flags
+
+	m0, m1, m2, m3 := m[0], m[1], m[2], m[3]
+	m4, m5, m6, m7 := m[4], m[5], m[6], m[7]
+	m8, m9, m10, m11 := m[8], m[9], m[10], m[11]
+	m12, m13, m14, m15 := m[12], m[13], m[14], m[15]
+
+	for i := 0; i < 7; i++ {
+		// Mix the columns.
+		v0, v4, v8, v12 = g(v0, v4, v8, v12, m0, m1)
+		v1, v5, v9, v13 = g(v1, v5, v9, v13, m2, m3)
+		v2, v6, v10, v14 = g(v2, v6, v10, v14, m4, m5)
+		v3, v7, v11, v15 = g(v3, v7, v11, v15, m6, m7)
+		// Mix the diagonals.
+		v0, v5, v10, v15 = g(v0, v5, v10, v15, m8, m9)
+		v1, v6, v11, v12 = g(v1, v6, v11, v12, m10, m11)
+		v2, v7, v8, v13 = g(v2, v7, v8, v13, m12, m13)
+		v3, v4, v9, v14 = g(v3, v4, v9, v14, m14, m15)
+
+		// Permute the message words.
+		m0, m1, m2, m3, m4, m5, m6, m7, m8, m9, m10, m11, m12, m13, m14, m15 =
+			m2, m6, m3, m10, m7, m0, m4, m13, m1, m11, m12, m5, m9, m14, m15, m8
+	}
+
+	return [16]uint32{
+		v0 ^ v8, v1 ^ v9, v2 ^ v10, v3 ^ v11,
+		v4 ^ v12, v5 ^ v13, v6 ^ v14, v7 ^ v15,
+		v8 ^ cv[0], v9 ^ cv[1], v10 ^ cv[2], v11 ^ cv[3],
+		v12 ^ cv[4], v13 ^ cv[5], v14 ^ cv[6], v15 ^ cv[7],
+	}
+}
+
+// g is the BLAKE3 quarter-round function.
+func g(a, b, c, d, mx, my uint32) (uint32, uint32, uint32, uint32) {
+	a += b + mx
+	d = bits.RotateLeft32(d^a, -16)
+	c += d
+	b = bits.RotateLeft32(b^c, -12)
+	a += b + my
+	d = bits.RotateLeft32(d^a, -8)
+	c += d
+	b = bits.RotateLeft32(b^c, -7)
+	return a, b, c, d
+}
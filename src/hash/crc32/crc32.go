@@ -16,6 +16,8 @@ import (
 	"errors"
 	"hash"
 	"internal/byteorder"
+	"io"
+	"runtime"
 	"sync"
 	"sync/atomic"
 )
@@ -134,6 +136,21 @@ func MakeTable(poly uint32) *Table {
 	}
 }
 
+// Hardware reports whether checksums using tab are computed with
+// architecture-specific instructions on the current CPU. Only the tables
+// returned by [MakeTable] for the [IEEE] and [Castagnoli] polynomials can
+// use such instructions, and only on some CPUs; checksums using any other
+// table are computed in software.
+func Hardware(tab *Table) bool {
+	switch {
+	case tab == IEEETable:
+		return archAvailableIEEE()
+	case haveCastagnoli.Load() && tab == castagnoliTable:
+		return archAvailableCastagnoli()
+	}
+	return false
+}
+
 // digest represents the partial evaluation of a checksum.
 type digest struct {
 	crc uint32
@@ -159,6 +176,11 @@ func New(tab *Table) hash.Hash32 {
 // and unmarshal the internal state of the hash.
 func NewIEEE() hash.Hash32 { return New(IEEETable) }
 
+func init() {
+	hash.Register("crc32", func() hash.Hash { return NewIEEE() })
+	hash.Register("crc32c", func() hash.Hash { return New(MakeTable(Castagnoli)) })
+}
+
 func (d *digest) Size() int { return Size }
 
 func (d *digest) BlockSize() int { return 1 }
@@ -238,6 +260,138 @@ func ChecksumIEEE(data []byte) uint32 {
 	return updateIEEE(0, data)
 }
 
+// ChecksumReader returns the CRC-32 checksum of the data read from r
+// until EOF, using the polynomial represented by the [Table], together
+// with the number of bytes read. A successful call returns err == nil,
+// not err == EOF. If a read fails, ChecksumReader returns the checksum
+// and count of the bytes read so far along with the error.
+func ChecksumReader(tab *Table, r io.Reader) (crc uint32, n int64, err error) {
+	buf := make([]byte, 32*1024)
+	for {
+		nr, er := r.Read(buf)
+		if nr > 0 {
+			crc = Update(crc, tab, buf[:nr])
+			n += int64(nr)
+		}
+		if er != nil {
+			if er != io.EOF {
+				err = er
+			}
+			return crc, n, err
+		}
+	}
+}
+
+// Combine returns the CRC-32 checksum of the concatenation of two byte
+// sequences A and B, given the checksum crcA of A, the checksum crcB of
+// B and the length of B, all using the polynomial represented by the
+// [Table]. It takes time logarithmic in lenB.
+func Combine(tab *Table, crcA, crcB uint32, lenB int64) uint32 {
+	if lenB <= 0 {
+		return crcA
+	}
+	// Appending n zero bytes to a message is a linear operation on its
+	// CRC, represented as a 32x32 matrix over GF(2). Compute the operator
+	// for lenB zero bytes by repeated squaring, apply it to crcA and add
+	// crcB. The inversions of the register before and after the update
+	// cancel out. See zlib's crc32_combine.
+	var even, odd gf2Matrix
+	// odd is the operator for one zero bit. tab[0x80] is the polynomial.
+	odd[0] = tab[0x80]
+	row := uint32(1)
+	for n := 1; n < 32; n++ {
+		odd[n] = row
+		row <<= 1
+	}
+	even.square(&odd) // two zero bits
+	odd.square(&even) // four zero bits
+	for {
+		even.square(&odd) // one zero byte on the first iteration
+		if lenB&1 != 0 {
+			crcA = even.times(crcA)
+		}
+		if lenB >>= 1; lenB == 0 {
+			break
+		}
+		odd.square(&even)
+		if lenB&1 != 0 {
+			crcA = odd.times(crcA)
+		}
+		if lenB >>= 1; lenB == 0 {
+			break
+		}
+	}
+	return crcA ^ crcB
+}
+
+// gf2Matrix is a 32x32 matrix over GF(2), stored as columns.
+type gf2Matrix [32]uint32
+
+func (m *gf2Matrix) times(vec uint32) uint32 {
+	var sum uint32
+	for i := 0; vec != 0; i++ {
+		if vec&1 != 0 {
+			sum ^= m[i]
+		}
+		vec >>= 1
+	}
+	return sum
+}
+
+// square sets m to the square of a.
+func (m *gf2Matrix) square(a *gf2Matrix) {
+	for n := range m {
+		m[n] = a.times(a[n])
+	}
+}
+
+// ChecksumParallel returns the CRC-32 checksum of data using the
+// polynomial represented by the [Table]. It splits data into pieces of
+// chunkSize bytes, computes their checksums on up to GOMAXPROCS
+// goroutines and merges the results with [Combine]. The result is the
+// same as that of [Checksum]. Chunks should be large, at least several
+// hundred kilobytes, for the parallelism to pay off.
+// ChecksumParallel panics if chunkSize is not positive.
+func ChecksumParallel(tab *Table, data []byte, chunkSize int) uint32 {
+	if chunkSize <= 0 {
+		panic("hash/crc32: invalid chunk size")
+	}
+	nchunks := (len(data) + chunkSize - 1) / chunkSize
+	workers := min(runtime.GOMAXPROCS(0), nchunks)
+	if workers <= 1 {
+		return Checksum(data, tab)
+	}
+	// Make sure the table-specific initialization happens once
+	// before the workers race to do it.
+	Update(0, tab, nil)
+
+	sums := make([]uint32, nchunks)
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for range workers {
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= nchunks {
+					return
+				}
+				chunk := data[i*chunkSize : min((i+1)*chunkSize, len(data))]
+				sums[i] = update(0, tab, chunk, false)
+			}
+		}()
+	}
+	wg.Wait()
+
+	crc := sums[0]
+	for i := 1; i < nchunks; i++ {
+		n := min(chunkSize, len(data)-i*chunkSize)
+		crc = Combine(tab, crc, sums[i], int64(n))
+	}
+	return crc
+}
+
 // tableSum returns the IEEE checksum of table t.
 func tableSum(t *Table) uint32 {
 	var a [1024]byte
@@ -5,12 +5,17 @@
 package crc32
 
 import (
+	"bytes"
 	"encoding"
+	"errors"
 	"fmt"
 	"hash"
 	"io"
 	"math/rand"
+	"runtime"
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 // First test, so that it can be the one to initialize castagnoliTable.
@@ -247,6 +252,21 @@ func TestArchCastagnoli(t *testing.T) {
 	})
 }
 
+func TestHardware(t *testing.T) {
+	if got, want := Hardware(IEEETable), archAvailableIEEE(); got != want {
+		t.Errorf("Hardware(IEEETable) = %v, want %v", got, want)
+	}
+	if got, want := Hardware(MakeTable(Castagnoli)), archAvailableCastagnoli(); got != want {
+		t.Errorf("Hardware(MakeTable(Castagnoli)) = %v, want %v", got, want)
+	}
+	if Hardware(MakeTable(Koopman)) {
+		t.Errorf("Hardware(MakeTable(Koopman)) = true, want false")
+	}
+	if Hardware(simpleMakeTable(Castagnoli)) {
+		t.Errorf("Hardware of a copy of the Castagnoli table = true, want false")
+	}
+}
+
 func TestGolden(t *testing.T) {
 	testGoldenIEEE(t, ChecksumIEEE)
 
@@ -292,6 +312,78 @@ func TestGolden(t *testing.T) {
 	}
 }
 
+func TestChecksumReader(t *testing.T) {
+	castagnoliTab := MakeTable(Castagnoli)
+	for _, g := range golden {
+		crc, n, err := ChecksumReader(IEEETable, strings.NewReader(g.in))
+		if crc != g.ieee || n != int64(len(g.in)) || err != nil {
+			t.Errorf("ChecksumReader(IEEE, %q) = 0x%x, %d, %v, want 0x%x, %d, nil", g.in, crc, n, err, g.ieee, len(g.in))
+		}
+		crc, n, err = ChecksumReader(castagnoliTab, iotest.OneByteReader(strings.NewReader(g.in)))
+		if crc != g.castagnoli || n != int64(len(g.in)) || err != nil {
+			t.Errorf("ChecksumReader(Castagnoli, %q) = 0x%x, %d, %v, want 0x%x, %d, nil", g.in, crc, n, err, g.castagnoli, len(g.in))
+		}
+	}
+
+	// Longer than the internal buffer.
+	p := make([]byte, 100000)
+	_, _ = rand.Read(p)
+	want := Checksum(p, castagnoliTab)
+	if crc, n, err := ChecksumReader(castagnoliTab, bytes.NewReader(p)); crc != want || n != int64(len(p)) || err != nil {
+		t.Errorf("ChecksumReader(Castagnoli, %d bytes) = 0x%x, %d, %v, want 0x%x, %d, nil", len(p), crc, n, err, want, len(p))
+	}
+
+	errBroken := errors.New("broken")
+	r := io.MultiReader(bytes.NewReader(p[:1000]), iotest.ErrReader(errBroken))
+	want = ChecksumIEEE(p[:1000])
+	if crc, n, err := ChecksumReader(IEEETable, r); crc != want || n != 1000 || err != errBroken {
+		t.Errorf("ChecksumReader with failing reader = 0x%x, %d, %v, want 0x%x, 1000, %v", crc, n, err, want, errBroken)
+	}
+}
+
+func TestCombine(t *testing.T) {
+	p := make([]byte, 5000)
+	_, _ = rand.Read(p)
+	for _, tab := range []*Table{IEEETable, MakeTable(Castagnoli), MakeTable(Koopman)} {
+		for _, split := range []int{0, 1, 3, 4, 100, 1024, 4999, 5000} {
+			a, b := p[:split], p[split:]
+			want := Checksum(p, tab)
+			got := Combine(tab, Checksum(a, tab), Checksum(b, tab), int64(len(b)))
+			if got != want {
+				t.Errorf("Combine at split %d = 0x%x, want 0x%x", split, got, want)
+			}
+		}
+	}
+}
+
+func TestChecksumParallel(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	p := make([]byte, 100000)
+	_, _ = rand.Read(p)
+	for _, tab := range []*Table{IEEETable, MakeTable(Castagnoli), MakeTable(Koopman)} {
+		for _, n := range []int{0, 1, 999, 1000, 1001, 100000} {
+			for _, chunkSize := range []int{1, 7, 1000, 4096, 200000} {
+				if n/chunkSize > 10000 {
+					continue
+				}
+				want := Checksum(p[:n], tab)
+				if got := ChecksumParallel(tab, p[:n], chunkSize); got != want {
+					t.Errorf("ChecksumParallel(%d bytes, chunk %d) = 0x%x, want 0x%x", n, chunkSize, got, want)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkChecksumParallel(b *testing.B) {
+	data := make([]byte, 64<<20)
+	tab := MakeTable(Castagnoli)
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		ChecksumParallel(tab, data, 1<<20)
+	}
+}
+
 func BenchmarkCRC32(b *testing.B) {
 	b.Run("poly=IEEE", benchmarkAll(NewIEEE()))
 	b.Run("poly=Castagnoli", benchmarkAll(New(MakeTable(Castagnoli))))
@@ -46,6 +46,8 @@ func buildSlicing8Tables() {
 
 // MakeTable returns a [Table] constructed from the specified polynomial.
 // The contents of this [Table] must not be modified.
+// To compute CRC-64 variants with other initial values, bit orders or
+// final XOR values, use [NewParams] instead.
 func MakeTable(poly uint64) *Table {
 	buildSlicing8TablesOnce()
 	switch poly {
@@ -100,6 +102,11 @@ type digest struct {
 // marshal and unmarshal the internal state of the hash.
 func New(tab *Table) hash.Hash64 { return &digest{0, tab} }
 
+func init() {
+	hash.Register("crc64-iso", func() hash.Hash { return New(MakeTable(ISO)) })
+	hash.Register("crc64-ecma", func() hash.Hash { return New(MakeTable(ECMA)) })
+}
+
 func (d *digest) Size() int { return Size }
 
 func (d *digest) BlockSize() int { return 1 }
@@ -137,7 +144,7 @@ func update(crc uint64, tab *Table, p []byte) uint64 {
 	buildSlicing8TablesOnce()
 	crc = ^crc
 	// Table comparison is somewhat expensive, so avoid it for small sizes
-	for len(p) >= 64 {
+	if len(p) >= 64 {
 		var helperTable *[8]Table
 		if *tab == slicing8TableECMA[0] {
 			helperTable = slicing8TableECMA
@@ -148,21 +155,9 @@ func update(crc uint64, tab *Table, p []byte) uint64 {
 			// According to the tests between various x86 and arm CPUs, 2k is a reasonable
 			// threshold for now. This may change in the future.
 			helperTable = makeSlicingBy8Table(tab)
-		} else {
-			break
 		}
-		// Update using slicing-by-8
-		for len(p) > 8 {
-			crc ^= byteorder.LeUint64(p)
-			crc = helperTable[7][crc&0xff] ^
-				helperTable[6][(crc>>8)&0xff] ^
-				helperTable[5][(crc>>16)&0xff] ^
-				helperTable[4][(crc>>24)&0xff] ^
-				helperTable[3][(crc>>32)&0xff] ^
-				helperTable[2][(crc>>40)&0xff] ^
-				helperTable[1][(crc>>48)&0xff] ^
-				helperTable[0][crc>>56]
-			p = p[8:]
+		if helperTable != nil {
+			return ^slicing8Update(crc, helperTable, p)
 		}
 	}
 	// For reminders or small sizes
@@ -172,6 +167,28 @@ func update(crc uint64, tab *Table, p []byte) uint64 {
 	return ^crc
 }
 
+// slicing8Update updates the bit-reflected crc register with p using
+// slicing-by-8 tables from makeSlicingBy8Table.
+func slicing8Update(crc uint64, helperTable *[8]Table, p []byte) uint64 {
+	for len(p) > 8 {
+		crc ^= byteorder.LeUint64(p)
+		crc = helperTable[7][crc&0xff] ^
+			helperTable[6][(crc>>8)&0xff] ^
+			helperTable[5][(crc>>16)&0xff] ^
+			helperTable[4][(crc>>24)&0xff] ^
+			helperTable[3][(crc>>32)&0xff] ^
+			helperTable[2][(crc>>40)&0xff] ^
+			helperTable[1][(crc>>48)&0xff] ^
+			helperTable[0][crc>>56]
+		p = p[8:]
+	}
+	// For reminders or small sizes
+	for _, v := range p {
+		crc = helperTable[0][byte(crc)^v] ^ (crc >> 8)
+	}
+	return crc
+}
+
 // Update returns the result of adding the bytes in p to the crc.
 func Update(crc uint64, tab *Table, p []byte) uint64 {
 	return update(crc, tab, p)
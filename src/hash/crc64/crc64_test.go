@@ -191,3 +191,87 @@ func BenchmarkCrc64(b *testing.B) {
 		bench(b, 0x777, 16<<10)
 	})
 }
+
+func TestParams(t *testing.T) {
+	// Check values from the catalogue of parametrised CRC algorithms:
+	// the checksum of the ASCII string "123456789".
+	tests := []struct {
+		name  string
+		p     Params
+		check uint64
+	}{
+		{"GO-ISO", ParamsGoISO, 0xB90956C775A41001},
+		{"XZ", ParamsXZ, 0x995DC9BBDF1939FA},
+		{"ECMA-182", ParamsECMA182, 0x6C40DF5F0B497347},
+		{"WE", ParamsWE, 0x62EC59E3F1A4F00A},
+		{"NVME", ParamsNVME, 0xAE8B14860A799888},
+		{"MS", Params{Poly: 0x259C84CBA6426349, Init: ^uint64(0), ReflectIn: true, ReflectOut: true}, 0x75D4B74F024ECEEA},
+		{"REDIS", Params{Poly: 0xAD93D23594C935A9, ReflectIn: true, ReflectOut: true}, 0xE9C6D914C4B8D9CA},
+	}
+	for _, tt := range tests {
+		if got := ChecksumParams([]byte("123456789"), tt.p); got != tt.check {
+			t.Errorf("%s: check = 0x%X, want 0x%X", tt.name, got, tt.check)
+		}
+	}
+
+	// The predefined tables use the same algorithms.
+	tabISO := MakeTable(ISO)
+	tabECMA := MakeTable(ECMA)
+	for _, g := range golden {
+		if got := ChecksumParams([]byte(g.in), ParamsGoISO); got != g.outISO {
+			t.Errorf("GO-ISO(%q) = 0x%x, want 0x%x", g.in, got, g.outISO)
+		}
+		if got := ChecksumParams([]byte(g.in), ParamsXZ); got != g.outECMA {
+			t.Errorf("XZ(%q) = 0x%x, want 0x%x", g.in, got, g.outECMA)
+		}
+	}
+
+	// Slicing-by-8 must agree with byte-at-a-time updates.
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i*31 + i>>3)
+	}
+	for _, tt := range tests {
+		want := ChecksumParams(data, tt.p)
+		h := NewParams(tt.p)
+		for i := range data {
+			h.Write(data[i : i+1])
+		}
+		if got := h.Sum64(); got != want {
+			t.Errorf("%s: byte-wise sum = 0x%x, want 0x%x", tt.name, got, want)
+		}
+	}
+	if got, want := ChecksumParams(data, ParamsXZ), Checksum(data, tabECMA); got != want {
+		t.Errorf("XZ of long input = 0x%x, want 0x%x", got, want)
+	}
+	if got, want := ChecksumParams(data, ParamsGoISO), Checksum(data, tabISO); got != want {
+		t.Errorf("GO-ISO of long input = 0x%x, want 0x%x", got, want)
+	}
+}
+
+func TestParamsMarshal(t *testing.T) {
+	data := []byte("The quick brown fox jumps over the lazy dog")
+	for _, p := range []Params{ParamsECMA182, ParamsNVME} {
+		h := NewParams(p)
+		h.Write(data[:20])
+		state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		h2 := NewParams(p)
+		if err := h2.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+			t.Fatal(err)
+		}
+		h2.Write(data[20:])
+		if got, want := h2.Sum64(), ChecksumParams(data, p); got != want {
+			t.Errorf("Sum64 after UnmarshalBinary = 0x%x, want 0x%x", got, want)
+		}
+	}
+	state, err := NewParams(ParamsXZ).(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := NewParams(ParamsNVME).(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err == nil {
+		t.Errorf("UnmarshalBinary with different parameters succeeded")
+	}
+}
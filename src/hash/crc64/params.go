@@ -0,0 +1,234 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crc64
+
+import (
+	"errors"
+	"hash"
+	"internal/byteorder"
+	"math/bits"
+	"sync"
+)
+
+// Params describes a CRC-64 algorithm using the parameters of the
+// Rocksoft model, as used by catalogues of CRC algorithms.
+//
+// Unlike the polynomials passed to [MakeTable], Poly is given in normal
+// (most significant bit first) representation, without the implicit
+// x**64 term.
+type Params struct {
+	Poly       uint64 // generator polynomial, normal representation
+	Init       uint64 // initial value of the register
+	ReflectIn  bool   // process input bytes least significant bit first
+	ReflectOut bool   // reflect the register before the final XOR
+	XorOut     uint64 // value XORed into the register to produce the sum
+}
+
+// Predefined CRC-64 algorithms.
+var (
+	// ParamsGoISO is the algorithm computed by this package with the
+	// ISO polynomial.
+	ParamsGoISO = Params{Poly: 0x000000000000001B, Init: ^uint64(0), ReflectIn: true, ReflectOut: true, XorOut: ^uint64(0)}
+
+	// ParamsXZ is the algorithm used by xz. It is also the algorithm
+	// computed by this package with the ECMA polynomial.
+	ParamsXZ = Params{Poly: 0x42F0E1EBA9EA3693, Init: ^uint64(0), ReflectIn: true, ReflectOut: true, XorOut: ^uint64(0)}
+
+	// ParamsECMA182 is the algorithm specified in ECMA-182.
+	ParamsECMA182 = Params{Poly: 0x42F0E1EBA9EA3693}
+
+	// ParamsWE is the variant of ParamsECMA182 with an inverted
+	// initial value and result.
+	ParamsWE = Params{Poly: 0x42F0E1EBA9EA3693, Init: ^uint64(0), XorOut: ^uint64(0)}
+
+	// ParamsNVME is the algorithm specified in the NVM Express
+	// specification for end-to-end data protection.
+	ParamsNVME = Params{Poly: 0xAD93D23594C93659, Init: ^uint64(0), ReflectIn: true, ReflectOut: true, XorOut: ^uint64(0)}
+)
+
+// paramsTableKey identifies the slicing-by-8 tables for a polynomial
+// and bit order.
+type paramsTableKey struct {
+	poly    uint64
+	reflect bool
+}
+
+// paramsTables caches slicing-by-8 tables by paramsTableKey,
+// since callers typically use a handful of algorithms.
+var paramsTables sync.Map // map[paramsTableKey]*[8]Table
+
+func makeParamsTables(poly uint64, reflect bool) *[8]Table {
+	key := paramsTableKey{poly, reflect}
+	if t, ok := paramsTables.Load(key); ok {
+		return t.(*[8]Table)
+	}
+	var t *[8]Table
+	if reflect {
+		t = makeSlicingBy8Table(makeTable(bits.Reverse64(poly)))
+	} else {
+		t = makeSlicingBy8TableMSB(makeTableMSB(poly))
+	}
+	actual, _ := paramsTables.LoadOrStore(key, t)
+	return actual.(*[8]Table)
+}
+
+// makeTableMSB returns the table for the most significant bit first
+// form of poly.
+func makeTableMSB(poly uint64) *Table {
+	t := new(Table)
+	for i := 0; i < 256; i++ {
+		crc := uint64(i) << 56
+		for j := 0; j < 8; j++ {
+			if crc&(1<<63) != 0 {
+				crc = (crc << 1) ^ poly
+			} else {
+				crc <<= 1
+			}
+		}
+		t[i] = crc
+	}
+	return t
+}
+
+// makeSlicingBy8TableMSB is like makeSlicingBy8Table for tables
+// made by makeTableMSB.
+func makeSlicingBy8TableMSB(t *Table) *[8]Table {
+	var helperTable [8]Table
+	helperTable[0] = *t
+	for i := 0; i < 256; i++ {
+		crc := t[i]
+		for j := 1; j < 8; j++ {
+			crc = t[crc>>56] ^ (crc << 8)
+			helperTable[j][i] = crc
+		}
+	}
+	return &helperTable
+}
+
+// slicing8UpdateMSB updates the crc register with p using slicing-by-8
+// tables from makeSlicingBy8TableMSB.
+func slicing8UpdateMSB(crc uint64, helperTable *[8]Table, p []byte) uint64 {
+	for len(p) > 8 {
+		crc ^= byteorder.BeUint64(p)
+		crc = helperTable[7][crc>>56] ^
+			helperTable[6][(crc>>48)&0xff] ^
+			helperTable[5][(crc>>40)&0xff] ^
+			helperTable[4][(crc>>32)&0xff] ^
+			helperTable[3][(crc>>24)&0xff] ^
+			helperTable[2][(crc>>16)&0xff] ^
+			helperTable[1][(crc>>8)&0xff] ^
+			helperTable[0][crc&0xff]
+		p = p[8:]
+	}
+	for _, v := range p {
+		crc = helperTable[0][byte(crc>>56)^v] ^ (crc << 8)
+	}
+	return crc
+}
+
+// paramsDigest represents the partial evaluation of a checksum
+// described by Params.
+type paramsDigest struct {
+	// crc is the register. When p.ReflectIn is set, it holds the
+	// register bit-reversed, which is the form the table-driven
+	// update operates on.
+	crc uint64
+	p   Params
+	tab *[8]Table
+}
+
+// NewParams creates a new hash.Hash64 computing the CRC-64 checksum
+// described by p. Its Sum method will lay the value out in big-endian
+// byte order. The returned Hash64 also implements [encoding.BinaryMarshaler]
+// and [encoding.BinaryUnmarshaler] to marshal and unmarshal the internal
+// state of the hash.
+func NewParams(p Params) hash.Hash64 {
+	d := &paramsDigest{p: p, tab: makeParamsTables(p.Poly, p.ReflectIn)}
+	d.Reset()
+	return d
+}
+
+// ChecksumParams returns the CRC-64 checksum of data using the
+// algorithm described by p.
+func ChecksumParams(data []byte, p Params) uint64 {
+	d := paramsDigest{p: p, tab: makeParamsTables(p.Poly, p.ReflectIn)}
+	d.Reset()
+	d.Write(data)
+	return d.Sum64()
+}
+
+func (d *paramsDigest) Size() int { return Size }
+
+func (d *paramsDigest) BlockSize() int { return 1 }
+
+func (d *paramsDigest) Reset() {
+	d.crc = d.p.Init
+	if d.p.ReflectIn {
+		d.crc = bits.Reverse64(d.crc)
+	}
+}
+
+func (d *paramsDigest) Write(p []byte) (n int, err error) {
+	if d.p.ReflectIn {
+		d.crc = slicing8Update(d.crc, d.tab, p)
+	} else {
+		d.crc = slicing8UpdateMSB(d.crc, d.tab, p)
+	}
+	return len(p), nil
+}
+
+func (d *paramsDigest) Sum64() uint64 {
+	crc := d.crc
+	if d.p.ReflectIn != d.p.ReflectOut {
+		crc = bits.Reverse64(crc)
+	}
+	return crc ^ d.p.XorOut
+}
+
+func (d *paramsDigest) Sum(in []byte) []byte {
+	return byteorder.BeAppendUint64(in, d.Sum64())
+}
+
+const (
+	paramsMagic         = "crc\x03"
+	paramsMarshaledSize = len(paramsMagic) + 8 + 8 + 8 + 1 + 8
+)
+
+func (d *paramsDigest) appendParams(b []byte) []byte {
+	b = byteorder.BeAppendUint64(b, d.p.Poly)
+	b = byteorder.BeAppendUint64(b, d.p.Init)
+	b = byteorder.BeAppendUint64(b, d.p.XorOut)
+	var flags byte
+	if d.p.ReflectIn {
+		flags |= 1
+	}
+	if d.p.ReflectOut {
+		flags |= 2
+	}
+	return append(b, flags)
+}
+
+func (d *paramsDigest) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, paramsMarshaledSize)
+	b = append(b, paramsMagic...)
+	b = d.appendParams(b)
+	b = byteorder.BeAppendUint64(b, d.crc)
+	return b, nil
+}
+
+func (d *paramsDigest) UnmarshalBinary(b []byte) error {
+	if len(b) < len(paramsMagic) || string(b[:len(paramsMagic)]) != paramsMagic {
+		return errors.New("hash/crc64: invalid hash state identifier")
+	}
+	if len(b) != paramsMarshaledSize {
+		return errors.New("hash/crc64: invalid hash state size")
+	}
+	var a [paramsMarshaledSize]byte
+	if string(d.appendParams(a[:0])) != string(b[len(paramsMagic):paramsMarshaledSize-8]) {
+		return errors.New("hash/crc64: parameters do not match")
+	}
+	d.crc = byteorder.BeUint64(b[paramsMarshaledSize-8:])
+	return nil
+}
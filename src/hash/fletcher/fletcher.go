@@ -0,0 +1,458 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package fletcher implements Fletcher's checksums.
+//
+// Fletcher-16, Fletcher-32 and Fletcher-64 keep two sums: s1 is the sum
+// of all words of the input, s2 is the sum of all s1 values. Fletcher-16
+// operates on bytes and reduces its sums modulo 255, Fletcher-32 operates
+// on 16-bit words modulo 65535, and Fletcher-64 operates on 32-bit words
+// modulo 4294967295. Both sums start at zero and the checksum is s2
+// followed by s1. See https://en.wikipedia.org/wiki/Fletcher%27s_checksum.
+//
+// Fletcher-4 is the variant used by ZFS. It keeps four 64-bit sums of
+// 32-bit words, each being the running sum of the previous one, and
+// lets them wrap around instead of reducing them.
+//
+// Multi-byte words are read in little-endian byte order. If the length
+// of the input is not a multiple of the word size, the last word is
+// padded with zero bytes.
+//
+// All the hash.Hash implementations returned by this package also
+// implement encoding.BinaryMarshaler and encoding.BinaryUnmarshaler to
+// marshal and unmarshal the internal state of the hash.
+package fletcher
+
+import (
+	"errors"
+	"hash"
+	"internal/byteorder"
+)
+
+// The sizes of the checksums in bytes.
+const (
+	Size16 = 2
+	Size32 = 4
+	Size64 = 8
+	Size4  = 32
+)
+
+const (
+	mod16 = 1<<8 - 1
+	mod32 = 1<<16 - 1
+	mod64 = 1<<32 - 1
+
+	// nmax16, nmax32 and nmax64 are the number of words that can be
+	// added to reduced sums before s2 may overflow a uint64, rounded
+	// down to a power of two. For a word of at most m and n words,
+	// s2 grows by at most m + n*m + m*n*(n+1)/2.
+	nmax16 = 1 << 26
+	nmax32 = 1 << 22
+	nmax64 = 1 << 15
+)
+
+func init() {
+	hash.Register("fletcher16", New16)
+	hash.Register("fletcher32", func() hash.Hash { return New32() })
+	hash.Register("fletcher64", func() hash.Hash { return New64() })
+	hash.Register("fletcher4", New4)
+}
+
+// digest16 represents the partial evaluation of a Fletcher-16 checksum.
+type digest16 struct {
+	s1, s2 uint32
+}
+
+// digest32 represents the partial evaluation of a Fletcher-32 checksum.
+type digest32 struct {
+	s1, s2 uint32
+	buf    [2]byte // partial word
+	nbuf   int
+}
+
+// digest64 represents the partial evaluation of a Fletcher-64 checksum.
+type digest64 struct {
+	s1, s2 uint32
+	buf    [4]byte // partial word
+	nbuf   int
+}
+
+// digest4 represents the partial evaluation of a Fletcher-4 checksum.
+type digest4 struct {
+	a, b, c, d uint64
+	buf        [4]byte // partial word
+	nbuf       int
+}
+
+// New16 returns a new [hash.Hash] computing the Fletcher-16 checksum.
+// Its Sum method will lay the value out in big-endian byte order.
+func New16() hash.Hash {
+	return new(digest16)
+}
+
+// New32 returns a new [hash.Hash32] computing the Fletcher-32 checksum.
+// Its Sum method will lay the value out in big-endian byte order.
+func New32() hash.Hash32 {
+	return new(digest32)
+}
+
+// New64 returns a new [hash.Hash64] computing the Fletcher-64 checksum.
+// Its Sum method will lay the value out in big-endian byte order.
+func New64() hash.Hash64 {
+	return new(digest64)
+}
+
+// New4 returns a new [hash.Hash] computing the ZFS Fletcher-4 checksum.
+// Its Sum method will lay out the four sums in order, each in big-endian
+// byte order.
+func New4() hash.Hash {
+	return new(digest4)
+}
+
+// Checksum16 returns the Fletcher-16 checksum of data.
+func Checksum16(data []byte) uint16 {
+	s1, s2 := update16(0, 0, data)
+	return uint16(s2<<8 | s1)
+}
+
+// Checksum32 returns the Fletcher-32 checksum of data.
+func Checksum32(data []byte) uint32 {
+	var d digest32
+	d.Write(data)
+	return d.Sum32()
+}
+
+// Checksum64 returns the Fletcher-64 checksum of data.
+func Checksum64(data []byte) uint64 {
+	var d digest64
+	d.Write(data)
+	return d.Sum64()
+}
+
+// Checksum4 returns the four sums of the ZFS Fletcher-4 checksum of data.
+func Checksum4(data []byte) [4]uint64 {
+	var d digest4
+	d.Write(data)
+	return d.sums()
+}
+
+// update16 adds p to the reduced Fletcher-16 sums s1 and s2.
+func update16(s1, s2 uint32, p []byte) (uint32, uint32) {
+	a, b := uint64(s1), uint64(s2)
+	for len(p) > 0 {
+		var q []byte
+		if len(p) > nmax16 {
+			p, q = p[:nmax16], p[nmax16:]
+		}
+		for _, x := range p {
+			a += uint64(x)
+			b += a
+		}
+		a %= mod16
+		b %= mod16
+		p = q
+	}
+	return uint32(a), uint32(b)
+}
+
+// update32 adds the 16-bit words of p to the reduced Fletcher-32 sums
+// s1 and s2. len(p) must be even.
+func update32(s1, s2 uint32, p []byte) (uint32, uint32) {
+	a, b := uint64(s1), uint64(s2)
+	for len(p) > 0 {
+		var q []byte
+		if len(p) > 2*nmax32 {
+			p, q = p[:2*nmax32], p[2*nmax32:]
+		}
+		for ; len(p) >= 2; p = p[2:] {
+			a += uint64(byteorder.LeUint16(p))
+			b += a
+		}
+		a %= mod32
+		b %= mod32
+		p = q
+	}
+	return uint32(a), uint32(b)
+}
+
+// update64 adds the 32-bit words of p to the reduced Fletcher-64 sums
+// s1 and s2. len(p) must be a multiple of 4.
+func update64(s1, s2 uint32, p []byte) (uint32, uint32) {
+	a, b := uint64(s1), uint64(s2)
+	for len(p) > 0 {
+		var q []byte
+		if len(p) > 4*nmax64 {
+			p, q = p[:4*nmax64], p[4*nmax64:]
+		}
+		for ; len(p) >= 4; p = p[4:] {
+			a += uint64(byteorder.LeUint32(p))
+			b += a
+		}
+		a %= mod64
+		b %= mod64
+		p = q
+	}
+	return uint32(a), uint32(b)
+}
+
+// update adds the 32-bit words of p to the Fletcher-4 sums.
+// len(p) must be a multiple of 4.
+func (d *digest4) update(p []byte) {
+	a, b, c, e := d.a, d.b, d.c, d.d
+	for ; len(p) >= 4; p = p[4:] {
+		a += uint64(byteorder.LeUint32(p))
+		b += a
+		c += b
+		e += c
+	}
+	d.a, d.b, d.c, d.d = a, b, c, e
+}
+
+// fill adds as much of p as fits to the partial word in buf, which
+// holds n bytes, and returns the new length of the partial word and
+// the rest of p.
+func fill(buf []byte, n int, p []byte) (int, []byte) {
+	k := copy(buf[n:], p)
+	return n + k, p[k:]
+}
+
+func (d *digest16) Reset() { *d = digest16{} }
+func (d *digest32) Reset() { *d = digest32{} }
+func (d *digest64) Reset() { *d = digest64{} }
+func (d *digest4) Reset()  { *d = digest4{} }
+
+func (d *digest16) Size() int { return Size16 }
+func (d *digest32) Size() int { return Size32 }
+func (d *digest64) Size() int { return Size64 }
+func (d *digest4) Size() int  { return Size4 }
+
+func (d *digest16) BlockSize() int { return 1 }
+func (d *digest32) BlockSize() int { return 2 }
+func (d *digest64) BlockSize() int { return 4 }
+func (d *digest4) BlockSize() int  { return 4 }
+
+func (d *digest16) Write(p []byte) (int, error) {
+	d.s1, d.s2 = update16(d.s1, d.s2, p)
+	return len(p), nil
+}
+
+func (d *digest32) Write(p []byte) (int, error) {
+	n := len(p)
+	if d.nbuf > 0 {
+		d.nbuf, p = fill(d.buf[:], d.nbuf, p)
+		if d.nbuf < len(d.buf) {
+			return n, nil
+		}
+		d.s1, d.s2 = update32(d.s1, d.s2, d.buf[:])
+		d.nbuf = 0
+	}
+	m := len(p) &^ (len(d.buf) - 1)
+	d.s1, d.s2 = update32(d.s1, d.s2, p[:m])
+	d.nbuf = copy(d.buf[:], p[m:])
+	return n, nil
+}
+
+func (d *digest64) Write(p []byte) (int, error) {
+	n := len(p)
+	if d.nbuf > 0 {
+		d.nbuf, p = fill(d.buf[:], d.nbuf, p)
+		if d.nbuf < len(d.buf) {
+			return n, nil
+		}
+		d.s1, d.s2 = update64(d.s1, d.s2, d.buf[:])
+		d.nbuf = 0
+	}
+	m := len(p) &^ (len(d.buf) - 1)
+	d.s1, d.s2 = update64(d.s1, d.s2, p[:m])
+	d.nbuf = copy(d.buf[:], p[m:])
+	return n, nil
+}
+
+func (d *digest4) Write(p []byte) (int, error) {
+	n := len(p)
+	if d.nbuf > 0 {
+		d.nbuf, p = fill(d.buf[:], d.nbuf, p)
+		if d.nbuf < len(d.buf) {
+			return n, nil
+		}
+		d.update(d.buf[:])
+		d.nbuf = 0
+	}
+	m := len(p) &^ (len(d.buf) - 1)
+	d.update(p[:m])
+	d.nbuf = copy(d.buf[:], p[m:])
+	return n, nil
+}
+
+func (d *digest32) Sum32() uint32 {
+	s1, s2 := d.s1, d.s2
+	if d.nbuf > 0 {
+		var w [2]byte
+		copy(w[:], d.buf[:d.nbuf])
+		s1, s2 = update32(s1, s2, w[:])
+	}
+	return s2<<16 | s1
+}
+
+func (d *digest64) Sum64() uint64 {
+	s1, s2 := d.s1, d.s2
+	if d.nbuf > 0 {
+		var w [4]byte
+		copy(w[:], d.buf[:d.nbuf])
+		s1, s2 = update64(s1, s2, w[:])
+	}
+	return uint64(s2)<<32 | uint64(s1)
+}
+
+// sums returns the four sums, including any partial word.
+func (d *digest4) sums() [4]uint64 {
+	e := *d
+	if e.nbuf > 0 {
+		clear(e.buf[e.nbuf:])
+		e.update(e.buf[:])
+	}
+	return [4]uint64{e.a, e.b, e.c, e.d}
+}
+
+func (d *digest16) Sum(in []byte) []byte {
+	return byteorder.BeAppendUint16(in, uint16(d.s2<<8|d.s1))
+}
+
+func (d *digest32) Sum(in []byte) []byte {
+	return byteorder.BeAppendUint32(in, d.Sum32())
+}
+
+func (d *digest64) Sum(in []byte) []byte {
+	return byteorder.BeAppendUint64(in, d.Sum64())
+}
+
+func (d *digest4) Sum(in []byte) []byte {
+	for _, s := range d.sums() {
+		in = byteorder.BeAppendUint64(in, s)
+	}
+	return in
+}
+
+const (
+	magic16 = "fle\x01"
+	magic32 = "fle\x02"
+	magic64 = "fle\x03"
+	magic4  = "fle\x04"
+
+	marshaledSize16 = len(magic16) + 4 + 4
+	marshaledSize32 = len(magic32) + 4 + 4 + 1 + 2
+	marshaledSize64 = len(magic64) + 4 + 4 + 1 + 4
+	marshaledSize4  = len(magic4) + 4*8 + 1 + 4
+)
+
+func (d *digest16) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, marshaledSize16)
+	b = append(b, magic16...)
+	b = byteorder.BeAppendUint32(b, d.s1)
+	b = byteorder.BeAppendUint32(b, d.s2)
+	return b, nil
+}
+
+func (d *digest32) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, marshaledSize32)
+	b = append(b, magic32...)
+	b = byteorder.BeAppendUint32(b, d.s1)
+	b = byteorder.BeAppendUint32(b, d.s2)
+	b = append(b, byte(d.nbuf))
+	b = append(b, d.buf[:]...)
+	return b, nil
+}
+
+func (d *digest64) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, marshaledSize64)
+	b = append(b, magic64...)
+	b = byteorder.BeAppendUint32(b, d.s1)
+	b = byteorder.BeAppendUint32(b, d.s2)
+	b = append(b, byte(d.nbuf))
+	b = append(b, d.buf[:]...)
+	return b, nil
+}
+
+func (d *digest4) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, marshaledSize4)
+	b = append(b, magic4...)
+	b = byteorder.BeAppendUint64(b, d.a)
+	b = byteorder.BeAppendUint64(b, d.b)
+	b = byteorder.BeAppendUint64(b, d.c)
+	b = byteorder.BeAppendUint64(b, d.d)
+	b = append(b, byte(d.nbuf))
+	b = append(b, d.buf[:]...)
+	return b, nil
+}
+
+// checkMarshaled checks the magic and size of a marshaled state.
+func checkMarshaled(b []byte, magic string, size int) error {
+	if len(b) < len(magic) || string(b[:len(magic)]) != magic {
+		return errors.New("hash/fletcher: invalid hash state identifier")
+	}
+	if len(b) != size {
+		return errors.New("hash/fletcher: invalid hash state size")
+	}
+	return nil
+}
+
+var errInvalidState = errors.New("hash/fletcher: invalid hash state")
+
+func (d *digest16) UnmarshalBinary(b []byte) error {
+	if err := checkMarshaled(b, magic16, marshaledSize16); err != nil {
+		return err
+	}
+	b = b[len(magic16):]
+	s1, s2 := byteorder.BeUint32(b), byteorder.BeUint32(b[4:])
+	if s1 >= mod16 || s2 >= mod16 {
+		return errInvalidState
+	}
+	d.s1, d.s2 = s1, s2
+	return nil
+}
+
+func (d *digest32) UnmarshalBinary(b []byte) error {
+	if err := checkMarshaled(b, magic32, marshaledSize32); err != nil {
+		return err
+	}
+	b = b[len(magic32):]
+	s1, s2, nbuf := byteorder.BeUint32(b), byteorder.BeUint32(b[4:]), int(b[8])
+	if s1 >= mod32 || s2 >= mod32 || nbuf >= len(d.buf) {
+		return errInvalidState
+	}
+	d.s1, d.s2, d.nbuf = s1, s2, nbuf
+	copy(d.buf[:], b[9:])
+	return nil
+}
+
+func (d *digest64) UnmarshalBinary(b []byte) error {
+	if err := checkMarshaled(b, magic64, marshaledSize64); err != nil {
+		return err
+	}
+	b = b[len(magic64):]
+	s1, s2, nbuf := byteorder.BeUint32(b), byteorder.BeUint32(b[4:]), int(b[8])
+	if s1 >= mod64 || s2 >= mod64 || nbuf >= len(d.buf) {
+		return errInvalidState
+	}
+	d.s1, d.s2, d.nbuf = s1, s2, nbuf
+	copy(d.buf[:], b[9:])
+	return nil
+}
+
+func (d *digest4) UnmarshalBinary(b []byte) error {
+	if err := checkMarshaled(b, magic4, marshaledSize4); err != nil {
+		return err
+	}
+	b = b[len(magic4):]
+	if int(b[32]) >= len(d.buf) {
+		return errInvalidState
+	}
+	d.a = byteorder.BeUint64(b)
+	d.b = byteorder.BeUint64(b[8:])
+	d.c = byteorder.BeUint64(b[16:])
+	d.d = byteorder.BeUint64(b[24:])
+	d.nbuf = int(b[32])
+	copy(d.buf[:], b[33:])
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fletcher
+
+import (
+	"bytes"
+	"encoding"
+	"hash"
+	"strings"
+	"testing"
+)
+
+var golden = []struct {
+	in  string
+	f16 uint16
+	f32 uint32
+	f64 uint64
+	f4  [4]uint64
+}{
+	{"", 0, 0, 0, [4]uint64{}},
+	{"abcde", 0xc8f0, 0xf04fc729, 0xc8c6c527646362c6, [4]uint64{0x646362c6, 0xc8c6c527, 0x12d2a2788, 0x1918d89e9}},
+	{"abcdef", 0x2057, 0x56502d2a, 0xc8c72b276463c8c6, [4]uint64{0x6463c8c6, 0xc8c72b27, 0x12d2a8d88, 0x1918defe9}},
+	{"abcdefgh", 0x0627, 0xebe19591, 0x312e2b28cccac8c6, [4]uint64{0xcccac8c6, 0x1312e2b27, 0x195918d88, 0x1f9f4efe9}},
+}
+
+func TestGolden(t *testing.T) {
+	for _, g := range golden {
+		p := []byte(g.in)
+		if got := Checksum16(p); got != g.f16 {
+			t.Errorf("Checksum16(%q) = %#x, want %#x", g.in, got, g.f16)
+		}
+		if got := Checksum32(p); got != g.f32 {
+			t.Errorf("Checksum32(%q) = %#x, want %#x", g.in, got, g.f32)
+		}
+		if got := Checksum64(p); got != g.f64 {
+			t.Errorf("Checksum64(%q) = %#x, want %#x", g.in, got, g.f64)
+		}
+		if got := Checksum4(p); got != g.f4 {
+			t.Errorf("Checksum4(%q) = %#x, want %#x", g.in, got, g.f4)
+		}
+	}
+}
+
+// naive computes a Fletcher checksum of p with words of the given size,
+// reducing the sums modulo m after every word.
+func naive(p []byte, size int, m uint64) (s1, s2 uint64) {
+	for len(p) > 0 {
+		var w uint64
+		for i := 0; i < size && i < len(p); i++ {
+			w |= uint64(p[i]) << (8 * i)
+		}
+		s1 = (s1 + w) % m
+		s2 = (s2 + s1) % m
+		p = p[min(size, len(p)):]
+	}
+	return s1, s2
+}
+
+func TestNaive(t *testing.T) {
+	// The all-0xff input maximizes the sums, checking that the
+	// deferred reduction does not overflow.
+	buf := make([]byte, 4*nmax64+1000)
+	for i := range buf {
+		buf[i] = 0xff
+	}
+	for _, n := range []int{1, 2, 3, 4, 5, 1000, 4*nmax64 - 1, 4 * nmax64, 4*nmax64 + 1, len(buf)} {
+		p := buf[:n]
+		s1, s2 := naive(p, 1, mod16)
+		if got, want := Checksum16(p), uint16(s2<<8|s1); got != want {
+			t.Errorf("Checksum16(%d bytes) = %#x, want %#x", n, got, want)
+		}
+		s1, s2 = naive(p, 2, mod32)
+		if got, want := Checksum32(p), uint32(s2<<16|s1); got != want {
+			t.Errorf("Checksum32(%d bytes) = %#x, want %#x", n, got, want)
+		}
+		s1, s2 = naive(p, 4, mod64)
+		if got, want := Checksum64(p), s2<<32|s1; got != want {
+			t.Errorf("Checksum64(%d bytes) = %#x, want %#x", n, got, want)
+		}
+	}
+}
+
+func TestWritePieces(t *testing.T) {
+	data := []byte(strings.Repeat("The quick brown fox jumps over the lazy dog. ", 50))
+	for _, newHash := range []func() hash.Hash{
+		New16,
+		func() hash.Hash { return New32() },
+		func() hash.Hash { return New64() },
+		New4,
+	} {
+		h := newHash()
+		h.Write(data)
+		want := h.Sum(nil)
+		if len(want) != h.Size() {
+			t.Errorf("%T: len(Sum) = %d, want %d", h, len(want), h.Size())
+		}
+		for _, step := range []int{1, 3, 5, 7, 64} {
+			h.Reset()
+			for p := data; len(p) > 0; {
+				k := min(len(p), step)
+				h.Write(p[:k])
+				p = p[k:]
+				// Sum must not change the state.
+				h.Sum(nil)
+			}
+			if got := h.Sum(nil); !bytes.Equal(got, want) {
+				t.Errorf("%T: sum in pieces of %d = %x, want %x", h, step, got, want)
+			}
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	data := []byte("abcdefghijklmnopqrstuvwxyz0123456789")
+	for _, newHash := range []func() hash.Hash{
+		New16,
+		func() hash.Hash { return New32() },
+		func() hash.Hash { return New64() },
+		New4,
+	} {
+		for half := 0; half < 8; half++ {
+			h := newHash()
+			h.Write(data[:half])
+			state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+			if err != nil {
+				t.Fatalf("%T: could not marshal: %v", h, err)
+			}
+			h2 := newHash()
+			if err := h2.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+				t.Fatalf("%T: could not unmarshal: %v", h, err)
+			}
+			h.Write(data[half:])
+			h2.Write(data[half:])
+			if got, want := h2.Sum(nil), h.Sum(nil); !bytes.Equal(got, want) {
+				t.Errorf("%T: sum after unmarshal at %d = %x, want %x", h, half, got, want)
+			}
+		}
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	h := New32()
+	state, _ := h.(encoding.BinaryMarshaler).MarshalBinary()
+	u := h.(encoding.BinaryUnmarshaler)
+	if err := u.UnmarshalBinary(state[:len(state)-1]); err == nil {
+		t.Errorf("UnmarshalBinary of short state succeeded")
+	}
+	if err := New64().(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err == nil {
+		t.Errorf("UnmarshalBinary of Fletcher-32 state into Fletcher-64 succeeded")
+	}
+	state[len(state)-3] = 2 // length of the partial word
+	if err := u.UnmarshalBinary(state); err == nil {
+		t.Errorf("UnmarshalBinary of invalid partial word length succeeded")
+	}
+}
+
+func BenchmarkFletcher(b *testing.B) {
+	data := make([]byte, 1<<16)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	for _, bb := range []struct {
+		name string
+		h    hash.Hash
+	}{
+		{"16", New16()},
+		{"32", New32()},
+		{"64", New64()},
+		{"4", New4()},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for range b.N {
+				bb.h.Write(data)
+			}
+		})
+	}
+}
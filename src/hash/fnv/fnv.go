@@ -85,6 +85,15 @@ func New128a() hash.Hash {
 	return &s
 }
 
+func init() {
+	hash.Register("fnv1-32", func() hash.Hash { return New32() })
+	hash.Register("fnv1a-32", func() hash.Hash { return New32a() })
+	hash.Register("fnv1-64", func() hash.Hash { return New64() })
+	hash.Register("fnv1a-64", func() hash.Hash { return New64a() })
+	hash.Register("fnv1-128", New128)
+	hash.Register("fnv1a-128", New128a)
+}
+
 func (s *sum32) Reset()   { *s = offset32 }
 func (s *sum32a) Reset()  { *s = offset32 }
 func (s *sum64) Reset()   { *s = offset64 }
@@ -12,6 +12,8 @@
 // (See crypto/sha256 and crypto/sha512 for cryptographic use.)
 package maphash
 
+import "internal/byteorder"
+
 // A Seed is a random value that selects the specific hash function
 // computed by a [Hash]. If two Hashes use the same Seeds, they
 // will compute the same hash values for any given input.
@@ -36,11 +38,21 @@ type Seed struct {
 //	h.Write(b)
 //	return h.Sum64()
 func Bytes(seed Seed, b []byte) uint64 {
-	state := seed.s
-	if state == 0 {
+	if seed.s == 0 {
 		panic("maphash: use of uninitialized Seed")
 	}
+	return BytesSeeded(seed.s, b)
+}
 
+// BytesSeeded returns the hash of b with a seed given as an integer.
+//
+// Unlike [Seed], the seed may be any value chosen by the caller,
+// for example one derived from the identity of a shard or epoch of
+// a data structure. Equal seeds select the same hash function within
+// a single process. The hash values depend on process-local state and
+// must not be stored or sent to other processes.
+func BytesSeeded(seed uint64, b []byte) uint64 {
+	state := seed
 	if len(b) > bufSize {
 		b = b[:len(b):len(b)] // merge len and cap calculations when reslicing
 		for len(b) > bufSize {
@@ -60,10 +72,16 @@ func Bytes(seed Seed, b []byte) uint64 {
 //	h.WriteString(s)
 //	return h.Sum64()
 func String(seed Seed, s string) uint64 {
-	state := seed.s
-	if state == 0 {
+	if seed.s == 0 {
 		panic("maphash: use of uninitialized Seed")
 	}
+	return StringSeeded(seed.s, s)
+}
+
+// StringSeeded returns the hash of s with a seed given as an integer.
+// See [BytesSeeded] for the properties of the seed.
+func StringSeeded(seed uint64, s string) uint64 {
+	state := seed
 	for len(s) > bufSize {
 		state = rthashString(s[:bufSize], state)
 		s = s[bufSize:]
@@ -190,6 +208,47 @@ func (h *Hash) WriteString(s string) (int, error) {
 	return size, nil
 }
 
+// WriteUint64 adds the 8 bytes of x in little-endian order
+// to the sequence of bytes hashed by h.
+// It is equivalent to, but more efficient than, encoding x into a
+// scratch buffer with [encoding/binary.LittleEndian] and calling Write.
+func (h *Hash) WriteUint64(x uint64) {
+	if h.n+8 <= bufSize {
+		byteorder.LePutUint64(h.buf[h.n:], x)
+		h.n += 8
+		return
+	}
+	var b [8]byte
+	byteorder.LePutUint64(b[:], x)
+	h.Write(b[:])
+}
+
+// WriteUint32 adds the 4 bytes of x in little-endian order
+// to the sequence of bytes hashed by h.
+func (h *Hash) WriteUint32(x uint32) {
+	if h.n+4 <= bufSize {
+		byteorder.LePutUint32(h.buf[h.n:], x)
+		h.n += 4
+		return
+	}
+	var b [4]byte
+	byteorder.LePutUint32(b[:], x)
+	h.Write(b[:])
+}
+
+// WriteUint16 adds the 2 bytes of x in little-endian order
+// to the sequence of bytes hashed by h.
+func (h *Hash) WriteUint16(x uint16) {
+	if h.n+2 <= bufSize {
+		byteorder.LePutUint16(h.buf[h.n:], x)
+		h.n += 2
+		return
+	}
+	var b [2]byte
+	byteorder.LePutUint16(b[:], x)
+	h.Write(b[:])
+}
+
 // Seed returns h's seed value.
 func (h *Hash) Seed() Seed {
 	h.initSeed()
@@ -254,6 +313,25 @@ func MakeSeed() Seed {
 	return Seed{s: s}
 }
 
+// Derive returns a new seed derived deterministically from seed and n.
+// Seeds derived from the same seed with different values of n select
+// independent hash functions, so a single call to [MakeSeed] can provide
+// the seeds for a family of hash functions, such as those of a Bloom
+// filter. Derive panics if seed is uninitialized.
+func (seed Seed) Derive(n uint64) Seed {
+	if seed.s == 0 {
+		panic("maphash: use of uninitialized Seed")
+	}
+	var buf [8]byte
+	byteorder.LePutUint64(buf[:], n)
+	s := rthash(buf[:], seed.s)
+	if s == 0 {
+		// Zero indicates an uninitialized seed; see MakeSeed.
+		s = 1
+	}
+	return Seed{s: s}
+}
+
 // Sum appends the hash's current 64-bit value to b.
 // It exists for implementing [hash.Hash].
 // For direct calls, it is more efficient to use [Hash.Sum64].
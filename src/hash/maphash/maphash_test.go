@@ -117,6 +117,54 @@ func TestHashBytesVsString(t *testing.T) {
 	}
 }
 
+func TestSeededFuncs(t *testing.T) {
+	seed := MakeSeed()
+	for _, n := range []int{0, 3, bufSize, bufSize + 1, 3*bufSize + 7} {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = byte(i)
+		}
+		want := Bytes(seed, b)
+		if got := BytesSeeded(seed.s, b); got != want {
+			t.Errorf("BytesSeeded(len %d) = %#x, want %#x", n, got, want)
+		}
+		if got := StringSeeded(seed.s, string(b)); got != want {
+			t.Errorf("StringSeeded(len %d) = %#x, want %#x", n, got, want)
+		}
+	}
+
+	// Distinct numeric seeds, including zero, should give distinct hashes.
+	const N = 16
+	m := make(map[uint64]bool)
+	for i := range uint64(N) {
+		m[StringSeeded(i, "foo")] = true
+	}
+	if len(m) != N {
+		t.Errorf("from %d seeds, got %d different hashes", N, len(m))
+	}
+}
+
+func TestWriteUint(t *testing.T) {
+	seed := MakeSeed()
+	// Check every alignment relative to the internal buffer.
+	for prefix := 0; prefix < 2*bufSize+3; prefix++ {
+		var h1, h2 Hash
+		h1.SetSeed(seed)
+		h2.SetSeed(seed)
+		for i := 0; i < prefix; i++ {
+			h1.WriteByte(byte(i))
+			h2.WriteByte(byte(i))
+		}
+		h1.WriteUint64(0x0102030405060708)
+		h1.WriteUint32(0x090a0b0c)
+		h1.WriteUint16(0x0d0e)
+		h2.Write([]byte{8, 7, 6, 5, 4, 3, 2, 1, 0xc, 0xb, 0xa, 9, 0xe, 0xd})
+		if h1.Sum64() != h2.Sum64() {
+			t.Fatalf("prefix %d: hash using WriteUint* not identical to Write", prefix)
+		}
+	}
+}
+
 func TestHashHighBytes(t *testing.T) {
 	// See issue 34925.
 	const N = 10
@@ -181,6 +229,35 @@ func TestSeedFromSeed(t *testing.T) {
 	}
 }
 
+func TestSeedDerive(t *testing.T) {
+	seed := MakeSeed()
+	if seed.Derive(1) != seed.Derive(1) {
+		t.Errorf("Derive is not deterministic")
+	}
+	seen := make(map[Seed]uint64)
+	for n := uint64(0); n < 1000; n++ {
+		d := seed.Derive(n)
+		if d == seed {
+			t.Errorf("Derive(%d) returned the parent seed", n)
+		}
+		if m, dup := seen[d]; dup {
+			t.Errorf("Derive(%d) and Derive(%d) returned the same seed", m, n)
+		}
+		seen[d] = n
+		// The derived seed must be usable.
+		String(d, "foo")
+	}
+	if MakeSeed().Derive(1) == seed.Derive(1) {
+		t.Errorf("seeds derived from different seeds are equal")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Derive of uninitialized seed did not panic")
+		}
+	}()
+	Seed{}.Derive(1)
+}
+
 func TestSeedFromFlush(t *testing.T) {
 	b := make([]byte, 65)
 	h1 := new(Hash)
@@ -253,3 +330,13 @@ func BenchmarkHash(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkWriteUint64(b *testing.B) {
+	var h Hash
+	for i := 0; i < b.N; i++ {
+		h.Reset()
+		h.WriteUint64(uint64(i))
+		h.WriteUint32(uint32(i))
+		h.Sum64()
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hash
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]func() Hash)
+)
+
+// Register makes a hash function available by the provided name.
+// It is intended to be called from the init function in packages
+// that implement hash functions.
+//
+// The hash functions in the standard library register themselves when
+// their package is imported, using the following names:
+//
+//	"adler32"    hash/adler32
+//	"blake3"     hash/blake3
+//	"crc32"      hash/crc32, IEEE polynomial
+//	"crc32c"     hash/crc32, Castagnoli polynomial
+//	"crc64-iso"  hash/crc64, ISO polynomial
+//	"crc64-ecma" hash/crc64, ECMA polynomial
+//	"fletcher4"  hash/fletcher, ZFS Fletcher-4
+//	"fletcher16" hash/fletcher, Fletcher-16
+//	"fletcher32" hash/fletcher, Fletcher-32
+//	"fletcher64" hash/fletcher, Fletcher-64
+//	"fnv1-32"    hash/fnv, 32-bit FNV-1
+//	"fnv1a-32"   hash/fnv, 32-bit FNV-1a
+//	"fnv1-64"    hash/fnv, 64-bit FNV-1
+//	"fnv1a-64"   hash/fnv, 64-bit FNV-1a
+//	"fnv1-128"   hash/fnv, 128-bit FNV-1
+//	"fnv1a-128"  hash/fnv, 128-bit FNV-1a
+//
+// If Register is called twice with the same name, if the name is empty
+// or if fn is nil, it panics.
+func Register(name string, fn func() Hash) {
+	if name == "" {
+		panic("hash: Register with empty name")
+	}
+	if fn == nil {
+		panic("hash: Register of nil constructor for " + name)
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := registry[name]; dup {
+		panic("hash: Register called twice for " + name)
+	}
+	registry[name] = fn
+}
+
+// New returns a new instance of the hash function registered
+// under the given name by [Register].
+// It returns an error if no such hash function is linked into the binary.
+func New(name string) (Hash, error) {
+	registryMu.RLock()
+	fn := registry[name]
+	registryMu.RUnlock()
+	if fn == nil {
+		return nil, errors.New("hash: unknown hash function \"" + name + "\" (forgotten import?)")
+	}
+	return fn(), nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hash_test
+
+import (
+	"bytes"
+	"hash"
+	"hash/adler32"
+	"hash/blake3"
+	"hash/crc32"
+	"hash/crc64"
+	"hash/fnv"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	tests := []struct {
+		name string
+		new  func() hash.Hash
+	}{
+		{"adler32", func() hash.Hash { return adler32.New() }},
+		{"blake3", func() hash.Hash { return blake3.New() }},
+		{"crc32", func() hash.Hash { return crc32.NewIEEE() }},
+		{"crc32c", func() hash.Hash { return crc32.New(crc32.MakeTable(crc32.Castagnoli)) }},
+		{"crc64-iso", func() hash.Hash { return crc64.New(crc64.MakeTable(crc64.ISO)) }},
+		{"crc64-ecma", func() hash.Hash { return crc64.New(crc64.MakeTable(crc64.ECMA)) }},
+		{"fnv1-32", func() hash.Hash { return fnv.New32() }},
+		{"fnv1a-32", func() hash.Hash { return fnv.New32a() }},
+		{"fnv1-64", func() hash.Hash { return fnv.New64() }},
+		{"fnv1a-64", func() hash.Hash { return fnv.New64a() }},
+		{"fnv1-128", fnv.New128},
+		{"fnv1a-128", fnv.New128a},
+	}
+	data := []byte("The quick brown fox jumps over the lazy dog")
+	for _, tt := range tests {
+		h, err := hash.New(tt.name)
+		if err != nil {
+			t.Errorf("New(%q): %v", tt.name, err)
+			continue
+		}
+		want := tt.new()
+		h.Write(data)
+		want.Write(data)
+		if got, want := h.Sum(nil), want.Sum(nil); !bytes.Equal(got, want) {
+			t.Errorf("New(%q) sum = %x, want %x", tt.name, got, want)
+		}
+	}
+}
+
+func TestRegistryUnknown(t *testing.T) {
+	h, err := hash.New("no-such-hash")
+	if err == nil {
+		t.Fatalf("New of unknown hash returned %T, want error", h)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	hash.Register("test-fnv", func() hash.Hash { return fnv.New64a() })
+	h, err := hash.New("test-fnv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Size() != 8 {
+		t.Errorf("Size() = %d, want 8", h.Size())
+	}
+
+	shouldPanic := func(name string, f func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s did not panic", name)
+			}
+		}()
+		f()
+	}
+	shouldPanic("duplicate Register", func() {
+		hash.Register("test-fnv", func() hash.Hash { return fnv.New64a() })
+	})
+	shouldPanic("Register with empty name", func() {
+		hash.Register("", func() hash.Hash { return fnv.New64a() })
+	})
+	shouldPanic("Register of nil", func() {
+		hash.Register("test-nil", nil)
+	})
+}
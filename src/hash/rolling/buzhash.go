@@ -0,0 +1,121 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rolling
+
+import (
+	"errors"
+	"internal/byteorder"
+	"math/bits"
+)
+
+// buzTable maps each byte value to a pseudo-random 64-bit word.
+// It is generated deterministically so that buzhash values are
+// stable across processes and releases.
+var buzTable = func() (t [256]uint64) {
+	// splitmix64 with a fixed seed.
+	x := uint64(0x9e3779b97f4a7c15)
+	for i := range t {
+		x += 0x9e3779b97f4a7c15
+		z := x
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		t[i] = z ^ (z >> 31)
+	}
+	return t
+}()
+
+// buzhash implements a cyclic polynomial rolling hash.
+type buzhash struct {
+	sum    uint64
+	window []byte
+	pos    int    // index of the oldest byte in window
+	zero   uint64 // hash of a window of zero bytes
+}
+
+// NewBuzhash returns a new buzhash rolling [Hash] over a window of
+// the given size. Its Sum method will lay the value out in big-endian
+// byte order. The returned Hash also implements [encoding.BinaryMarshaler]
+// and [encoding.BinaryUnmarshaler] to marshal and unmarshal the internal
+// state of the hash. NewBuzhash panics if window is not positive.
+func NewBuzhash(window int) Hash {
+	if window <= 0 {
+		panic("rolling: invalid window size")
+	}
+	d := &buzhash{window: make([]byte, window)}
+	for range window {
+		d.zero = bits.RotateLeft64(d.zero, 1) ^ buzTable[0]
+	}
+	d.sum = d.zero
+	return d
+}
+
+func (d *buzhash) Size() int { return 8 }
+
+func (d *buzhash) BlockSize() int { return 1 }
+
+func (d *buzhash) WindowSize() int { return len(d.window) }
+
+func (d *buzhash) Reset() {
+	clear(d.window)
+	d.pos = 0
+	d.sum = d.zero
+}
+
+func (d *buzhash) Roll(b byte) {
+	out := d.window[d.pos]
+	d.window[d.pos] = b
+	if d.pos++; d.pos == len(d.window) {
+		d.pos = 0
+	}
+	d.sum = bits.RotateLeft64(d.sum, 1) ^ bits.RotateLeft64(buzTable[out], len(d.window)) ^ buzTable[b]
+}
+
+func (d *buzhash) Write(p []byte) (int, error) {
+	for _, b := range p {
+		d.Roll(b)
+	}
+	return len(p), nil
+}
+
+func (d *buzhash) Sum64() uint64 { return d.sum }
+
+func (d *buzhash) Sum(in []byte) []byte {
+	return byteorder.BeAppendUint64(in, d.sum)
+}
+
+const (
+	buzMagic         = "buz\x01"
+	buzMarshaledSize = len(buzMagic) + 8 + 8 + 8
+)
+
+func (d *buzhash) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, buzMarshaledSize+len(d.window))
+	b = append(b, buzMagic...)
+	b = byteorder.BeAppendUint64(b, uint64(len(d.window)))
+	b = byteorder.BeAppendUint64(b, uint64(d.pos))
+	b = byteorder.BeAppendUint64(b, d.sum)
+	b = append(b, d.window...)
+	return b, nil
+}
+
+func (d *buzhash) UnmarshalBinary(b []byte) error {
+	if len(b) < len(buzMagic) || string(b[:len(buzMagic)]) != buzMagic {
+		return errors.New("hash/rolling: invalid hash state identifier")
+	}
+	if len(b) != buzMarshaledSize+len(d.window) {
+		return errors.New("hash/rolling: invalid hash state size")
+	}
+	if byteorder.BeUint64(b[4:]) != uint64(len(d.window)) {
+		return errors.New("hash/rolling: window sizes do not match")
+	}
+	pos := byteorder.BeUint64(b[12:])
+	if pos >= uint64(len(d.window)) {
+		return errors.New("hash/rolling: invalid hash state")
+	}
+	d.pos = int(pos)
+	d.sum = byteorder.BeUint64(b[20:])
+	copy(d.window, b[buzMarshaledSize:])
+	return nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rolling_test
+
+import (
+	"fmt"
+	"hash/rolling"
+	"io"
+	"log"
+	"strings"
+)
+
+func ExampleChunker() {
+	input := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 100)
+
+	// Cut chunks of 64 to 1024 bytes, about 192 bytes on average.
+	c := rolling.NewChunker(strings.NewReader(input), rolling.NewBuzhash(32), 64, 1024, 1<<7-1)
+	total := 0
+	for {
+		chunk, err := c.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		total += len(chunk)
+	}
+	fmt.Println(total == len(input))
+	// Output: true
+}
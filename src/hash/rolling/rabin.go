@@ -0,0 +1,171 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rolling
+
+import (
+	"errors"
+	"internal/byteorder"
+	"math/bits"
+)
+
+// DefaultPolynomial is an irreducible polynomial of degree 53 over GF(2)
+// suitable for use with [NewRabin].
+const DefaultPolynomial = 0x3DA3358B4DC173
+
+// rabin implements a Rabin fingerprint over a sliding window.
+type rabin struct {
+	sum    uint64
+	window []byte
+	pos    int // index of the oldest byte in window
+	tab    *rabinTable
+}
+
+// rabinTable holds the precomputed tables for a polynomial and window size.
+type rabinTable struct {
+	poly  uint64
+	shift uint // degree of poly minus 8
+	// out[b] is the fingerprint of b followed by window-1 zero bytes,
+	// which is what b contributes when it leaves the window.
+	out [256]uint64
+	// mod[b] reduces a fingerprint whose top byte (above the degree
+	// of poly) is b.
+	mod [256]uint64
+}
+
+// NewRabin returns a new rolling [Hash] computing the Rabin fingerprint
+// of a window of the given size with the polynomial poly. Polynomials
+// are represented with bit i holding the coefficient of x**i. For good
+// results poly should be irreducible; [DefaultPolynomial] is a suitable
+// choice. Its Sum method will lay the value out in big-endian byte order.
+// The returned Hash also implements [encoding.BinaryMarshaler] and
+// [encoding.BinaryUnmarshaler] to marshal and unmarshal the internal state
+// of the hash.
+//
+// NewRabin panics if window is not positive or if the degree of poly is
+// not between 8 and 56 inclusive.
+func NewRabin(poly uint64, window int) Hash {
+	if window <= 0 {
+		panic("rolling: invalid window size")
+	}
+	deg := bits.Len64(poly) - 1
+	if deg < 8 || deg > 56 {
+		panic("rolling: invalid polynomial degree")
+	}
+	return &rabin{
+		window: make([]byte, window),
+		tab:    makeRabinTable(poly, uint(deg), window),
+	}
+}
+
+func makeRabinTable(poly uint64, deg uint, window int) *rabinTable {
+	t := &rabinTable{poly: poly, shift: deg - 8}
+	// Each zero byte following b in the window multiplies its
+	// fingerprint by x**8, so b contributes b * x**(8*(window-1)).
+	// That is linear in b, so compute it for single bits first.
+	pow := uint64(1)
+	for range window - 1 {
+		pow = polyMod(pow<<8, poly, deg)
+	}
+	for i := range 8 {
+		t.out[1<<i] = polyMod(pow<<i, poly, deg)
+	}
+	for b := range 256 {
+		if b&(b-1) != 0 {
+			t.out[b] = t.out[b&(b-1)] ^ t.out[b&-b]
+		}
+		// Including b<<deg in the table entry clears the top byte
+		// when it is XORed into the shifted fingerprint.
+		t.mod[b] = polyMod(uint64(b)<<deg, poly, deg) | uint64(b)<<deg
+	}
+	return t
+}
+
+// polyMod returns x modulo poly, which has degree deg.
+// x must have degree less than 64.
+func polyMod(x, poly uint64, deg uint) uint64 {
+	for {
+		d := bits.Len64(x) - 1
+		if d < int(deg) {
+			return x
+		}
+		x ^= poly << (uint(d) - deg)
+	}
+}
+
+func (d *rabin) Size() int { return 8 }
+
+func (d *rabin) BlockSize() int { return 1 }
+
+func (d *rabin) WindowSize() int { return len(d.window) }
+
+func (d *rabin) Reset() {
+	clear(d.window)
+	d.pos = 0
+	d.sum = 0
+}
+
+func (d *rabin) Roll(b byte) {
+	out := d.window[d.pos]
+	d.window[d.pos] = b
+	if d.pos++; d.pos == len(d.window) {
+		d.pos = 0
+	}
+	// Remove the outgoing byte, then append b and reduce.
+	sum := d.sum ^ d.tab.out[out]
+	top := sum >> d.tab.shift
+	d.sum = (sum<<8 | uint64(b)) ^ d.tab.mod[top]
+}
+
+func (d *rabin) Write(p []byte) (int, error) {
+	for _, b := range p {
+		d.Roll(b)
+	}
+	return len(p), nil
+}
+
+func (d *rabin) Sum64() uint64 { return d.sum }
+
+func (d *rabin) Sum(in []byte) []byte {
+	return byteorder.BeAppendUint64(in, d.sum)
+}
+
+const (
+	rabinMagic         = "rab\x01"
+	rabinMarshaledSize = len(rabinMagic) + 8 + 8 + 8 + 8
+)
+
+func (d *rabin) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, rabinMarshaledSize+len(d.window))
+	b = append(b, rabinMagic...)
+	b = byteorder.BeAppendUint64(b, d.tab.poly)
+	b = byteorder.BeAppendUint64(b, uint64(len(d.window)))
+	b = byteorder.BeAppendUint64(b, uint64(d.pos))
+	b = byteorder.BeAppendUint64(b, d.sum)
+	b = append(b, d.window...)
+	return b, nil
+}
+
+func (d *rabin) UnmarshalBinary(b []byte) error {
+	if len(b) < len(rabinMagic) || string(b[:len(rabinMagic)]) != rabinMagic {
+		return errors.New("hash/rolling: invalid hash state identifier")
+	}
+	if len(b) != rabinMarshaledSize+len(d.window) {
+		return errors.New("hash/rolling: invalid hash state size")
+	}
+	if byteorder.BeUint64(b[4:]) != d.tab.poly {
+		return errors.New("hash/rolling: polynomials do not match")
+	}
+	if byteorder.BeUint64(b[12:]) != uint64(len(d.window)) {
+		return errors.New("hash/rolling: window sizes do not match")
+	}
+	pos := byteorder.BeUint64(b[20:])
+	if pos >= uint64(len(d.window)) {
+		return errors.New("hash/rolling: invalid hash state")
+	}
+	d.pos = int(pos)
+	d.sum = byteorder.BeUint64(b[28:])
+	copy(d.window, b[rabinMarshaledSize:])
+	return nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package rolling implements rolling hashes suitable for
+// content-defined chunking (CDC).
+//
+// A rolling hash computes the hash of a fixed-size window that slides
+// over a stream of bytes. Adding a byte to the window with [Hash.Roll]
+// removes the oldest byte from it in constant time, which makes it cheap
+// to evaluate the hash at every position of the stream. Deduplicating
+// storage systems use this to cut a stream into chunks at positions that
+// depend only on the local content, so that an insertion or deletion
+// only changes the chunks around the edit. [Chunker] implements such a
+// splitter on top of any [Hash].
+//
+// Two hashes are provided: [NewBuzhash] returns a cyclic polynomial
+// hash (buzhash) and [NewRabin] returns a Rabin fingerprint.
+//
+// The hashes are not cryptographically secure.
+package rolling
+
+import (
+	"errors"
+	"hash"
+	"io"
+)
+
+// Hash is the interface implemented by rolling hashes.
+//
+// The window of a newly created or reset Hash consists of zero bytes.
+// Write rolls each byte of its argument into the window in turn,
+// so the hash value only depends on the last WindowSize bytes written.
+type Hash interface {
+	hash.Hash64
+
+	// Roll adds b to the window, evicting the oldest byte.
+	Roll(b byte)
+
+	// WindowSize returns the number of bytes in the window.
+	WindowSize() int
+}
+
+// A Chunker splits the data read from an underlying [io.Reader] into
+// content-defined chunks.
+//
+// A chunk boundary is placed after the first byte at which the rolling
+// hash h satisfies h.Sum64()&mask == 0, not counting the first minSize
+// bytes of the chunk. A chunk is never longer than maxSize bytes. With a
+// uniformly distributed hash, the expected chunk size is therefore about
+// minSize + mask + 1 bytes when mask is of the form 1<<n - 1.
+//
+// The hash is reset at the start of every chunk, so the boundaries only
+// depend on the chunk contents and not on data that came before.
+type Chunker struct {
+	r                io.Reader
+	h                Hash
+	minSize, maxSize int
+	mask             uint64
+
+	buf   []byte // buffered input; buf[start:end] is unconsumed
+	start int
+	end   int
+	err   error // sticky read error
+}
+
+// NewChunker returns a [Chunker] reading from r that uses h to find
+// chunk boundaries. The arguments minSize and maxSize bound the size of
+// the chunks, and mask selects the hash bits that must be zero at a
+// boundary. NewChunker panics if minSize is negative or maxSize is less
+// than minSize or not positive.
+func NewChunker(r io.Reader, h Hash, minSize, maxSize int, mask uint64) *Chunker {
+	if minSize < 0 || maxSize <= 0 || maxSize < minSize {
+		panic("rolling: invalid chunk size limits")
+	}
+	return &Chunker{
+		r:       r,
+		h:       h,
+		minSize: minSize,
+		maxSize: maxSize,
+		mask:    mask,
+		buf:     make([]byte, 2*maxSize),
+	}
+}
+
+// Next returns the next chunk of the input.
+// The returned slice is only valid until the next call to Next.
+// At the end of the input, Next returns a nil slice and [io.EOF].
+func (c *Chunker) Next() ([]byte, error) {
+	if c.end-c.start < c.maxSize {
+		c.fill()
+	}
+	data := c.buf[c.start:c.end]
+	if len(data) == 0 {
+		if c.err == nil || c.err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, c.err
+	}
+	n := Boundary(c.h, data, c.minSize, c.maxSize, c.mask)
+	c.start += n
+	return data[:n:n], nil
+}
+
+// maxConsecutiveEmptyReads is the number of reads returning no data and
+// no error after which fill gives up with [io.ErrNoProgress].
+const maxConsecutiveEmptyReads = 100
+
+// fill reads from the underlying reader until at least maxSize bytes
+// are buffered or a read error occurs.
+func (c *Chunker) fill() {
+	if c.start > 0 {
+		c.end = copy(c.buf, c.buf[c.start:c.end])
+		c.start = 0
+	}
+	empty := 0
+	for c.end < c.maxSize && c.err == nil {
+		n, err := c.r.Read(c.buf[c.end:])
+		if n < 0 {
+			panic(errNegativeRead)
+		}
+		c.end += n
+		c.err = err
+		if n > 0 {
+			empty = 0
+		} else if empty++; empty == maxConsecutiveEmptyReads && err == nil {
+			c.err = io.ErrNoProgress
+		}
+	}
+}
+
+var errNegativeRead = errors.New("rolling: reader returned negative count from Read")
+
+// Boundary returns the length of the first content-defined chunk of p,
+// using the same rules as [Chunker]. It resets h before use.
+// If p holds no boundary, Boundary returns min(len(p), maxSize).
+func Boundary(h Hash, p []byte, minSize, maxSize int, mask uint64) int {
+	p = p[:min(len(p), maxSize)]
+	if len(p) <= minSize {
+		return len(p)
+	}
+	h.Reset()
+	// Only the last WindowSize bytes before the first candidate
+	// position influence the hash there, so skip the rest.
+	i := max(minSize-h.WindowSize(), 0)
+	for ; i < minSize; i++ {
+		h.Roll(p[i])
+	}
+	for ; i < len(p); i++ {
+		h.Roll(p[i])
+		if h.Sum64()&mask == 0 {
+			return i + 1
+		}
+	}
+	return len(p)
+}
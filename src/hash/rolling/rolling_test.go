@@ -0,0 +1,265 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rolling
+
+import (
+	"bytes"
+	"encoding"
+	"io"
+	"math/bits"
+	"math/rand/v2"
+	"testing"
+	"testing/iotest"
+)
+
+var hashes = []struct {
+	name string
+	new  func(window int) Hash
+}{
+	{"buzhash", NewBuzhash},
+	{"rabin", func(window int) Hash { return NewRabin(DefaultPolynomial, window) }},
+}
+
+func randBytes(seed uint64, n int) []byte {
+	r := rand.New(rand.NewPCG(seed, seed))
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(r.Uint32())
+	}
+	return b
+}
+
+func TestRoll(t *testing.T) {
+	data := randBytes(1, 1024)
+	for _, tt := range hashes {
+		for _, window := range []int{1, 7, 48, 64, 65, 200} {
+			h := tt.new(window)
+			for i, b := range data {
+				h.Roll(b)
+				fresh := tt.new(window)
+				fresh.Write(data[max(0, i+1-window) : i+1])
+				if h.Sum64() != fresh.Sum64() {
+					t.Fatalf("%s(%d): rolled sum at %d = %#x, want %#x", tt.name, window, i, h.Sum64(), fresh.Sum64())
+				}
+			}
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	for _, tt := range hashes {
+		h := tt.new(16)
+		want := h.Sum64()
+		h.Write(randBytes(2, 100))
+		h.Reset()
+		if got := h.Sum64(); got != want {
+			t.Errorf("%s: Sum64 after Reset = %#x, want %#x", tt.name, got, want)
+		}
+	}
+}
+
+// naiveRabin computes the fingerprint of p modulo poly one bit at a time.
+func naiveRabin(p []byte, poly uint64) uint64 {
+	deg := bits.Len64(poly) - 1
+	var f uint64
+	for _, b := range p {
+		for i := 7; i >= 0; i-- {
+			f = f<<1 | uint64(b>>i&1)
+			if f>>deg&1 != 0 {
+				f ^= poly
+			}
+		}
+	}
+	return f
+}
+
+func TestRabinFingerprint(t *testing.T) {
+	data := randBytes(3, 1000)
+	for _, poly := range []uint64{DefaultPolynomial, 0x11d, 1<<56 | 0x95} {
+		const window = 32
+		h := NewRabin(poly, window)
+		for i, b := range data {
+			h.Roll(b)
+			want := naiveRabin(data[max(0, i+1-window):i+1], poly)
+			if got := h.Sum64(); got != want {
+				t.Fatalf("poly %#x: fingerprint at %d = %#x, want %#x", poly, i, got, want)
+			}
+		}
+	}
+}
+
+func TestNewPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{"buzhash window 0", func() { NewBuzhash(0) }},
+		{"rabin window 0", func() { NewRabin(DefaultPolynomial, 0) }},
+		{"rabin degree 7", func() { NewRabin(0x83, 16) }},
+		{"rabin degree 57", func() { NewRabin(1<<57|1, 16) }},
+		{"chunker sizes", func() { NewChunker(nil, NewBuzhash(16), 10, 5, 0xff) }},
+	}
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: did not panic", tt.name)
+				}
+			}()
+			tt.f()
+		}()
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	data := randBytes(4, 300)
+	for _, tt := range hashes {
+		h := tt.new(48)
+		h.Write(data[:123])
+		enc, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			t.Fatalf("%s: MarshalBinary: %v", tt.name, err)
+		}
+		h2 := tt.new(48)
+		if err := h2.(encoding.BinaryUnmarshaler).UnmarshalBinary(enc); err != nil {
+			t.Fatalf("%s: UnmarshalBinary: %v", tt.name, err)
+		}
+		h.Write(data[123:])
+		h2.Write(data[123:])
+		if h.Sum64() != h2.Sum64() {
+			t.Errorf("%s: Sum64 after UnmarshalBinary = %#x, want %#x", tt.name, h2.Sum64(), h.Sum64())
+		}
+		if err := tt.new(47).(encoding.BinaryUnmarshaler).UnmarshalBinary(enc); err == nil {
+			t.Errorf("%s: UnmarshalBinary with different window succeeded", tt.name)
+		}
+	}
+}
+
+func chunks(t *testing.T, r io.Reader, h Hash, minSize, maxSize int, mask uint64) [][]byte {
+	t.Helper()
+	c := NewChunker(r, h, minSize, maxSize, mask)
+	var out [][]byte
+	for {
+		chunk, err := c.Next()
+		if err == io.EOF {
+			return out
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(chunk) == 0 || len(chunk) > maxSize {
+			t.Fatalf("chunk of length %d", len(chunk))
+		}
+		out = append(out, bytes.Clone(chunk))
+	}
+}
+
+func TestChunker(t *testing.T) {
+	data := randBytes(5, 1<<18)
+	for _, tt := range hashes {
+		const minSize, maxSize, mask = 512, 8192, 1<<10 - 1
+		got := chunks(t, bytes.NewReader(data), tt.new(48), minSize, maxSize, mask)
+		if !bytes.Equal(bytes.Join(got, nil), data) {
+			t.Fatalf("%s: chunks do not concatenate to the input", tt.name)
+		}
+		for i, c := range got[:len(got)-1] {
+			if len(c) < minSize {
+				t.Errorf("%s: chunk %d has length %d < %d", tt.name, i, len(c), minSize)
+			}
+		}
+		// Reading one byte at a time must not change the boundaries.
+		got2 := chunks(t, iotest.OneByteReader(bytes.NewReader(data)), tt.new(48), minSize, maxSize, mask)
+		if len(got2) != len(got) {
+			t.Fatalf("%s: got %d chunks with one-byte reads, want %d", tt.name, len(got2), len(got))
+		}
+		for i := range got {
+			if !bytes.Equal(got[i], got2[i]) {
+				t.Fatalf("%s: chunk %d differs with one-byte reads", tt.name, i)
+			}
+		}
+	}
+}
+
+func TestChunkerResync(t *testing.T) {
+	data := randBytes(6, 1<<18)
+	edited := append(bytes.Clone(data[:1000]), "inserted bytes"...)
+	edited = append(edited, data[1000:]...)
+	for _, tt := range hashes {
+		orig := chunks(t, bytes.NewReader(data), tt.new(48), 256, 16384, 1<<11-1)
+		after := chunks(t, bytes.NewReader(edited), tt.new(48), 256, 16384, 1<<11-1)
+		seen := make(map[string]bool)
+		for _, c := range orig {
+			seen[string(c)] = true
+		}
+		shared := 0
+		for _, c := range after {
+			if seen[string(c)] {
+				shared++
+			}
+		}
+		// Only the chunks around the edit may change.
+		if shared < len(orig)-2 {
+			t.Errorf("%s: %d of %d chunks survived a small insertion", tt.name, shared, len(orig))
+		}
+	}
+}
+
+func TestChunkerError(t *testing.T) {
+	data := randBytes(7, 1000)
+	r := iotest.TimeoutReader(bytes.NewReader(data))
+	c := NewChunker(r, NewBuzhash(16), 0, 2000, 1<<20-1)
+	var n int
+	for {
+		chunk, err := c.Next()
+		if err == iotest.ErrTimeout {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next: %v, want %v", err, iotest.ErrTimeout)
+		}
+		n += len(chunk)
+	}
+	if n == 0 {
+		t.Errorf("no data returned before the read error")
+	}
+}
+
+// emptyReader returns no data and no error from every Read.
+type emptyReader struct{}
+
+func (emptyReader) Read([]byte) (int, error) { return 0, nil }
+
+func TestChunkerNoProgress(t *testing.T) {
+	data := randBytes(9, 1000)
+	r := io.MultiReader(bytes.NewReader(data), emptyReader{})
+	c := NewChunker(r, NewBuzhash(16), 0, 2000, 1<<20-1)
+	var got []byte
+	for {
+		chunk, err := c.Next()
+		if err == io.ErrNoProgress {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next: %v, want %v", err, io.ErrNoProgress)
+		}
+		got = append(got, chunk...)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("got %d bytes before the error, want %d", len(got), len(data))
+	}
+}
+
+func BenchmarkRoll(b *testing.B) {
+	data := randBytes(8, 1<<16)
+	for _, tt := range hashes {
+		b.Run(tt.name, func(b *testing.B) {
+			h := tt.new(64)
+			b.SetBytes(int64(len(data)))
+			for range b.N {
+				h.Write(data)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hash
+
+import (
+	"runtime"
+	"sync"
+)
+
+// NewTree returns a new [Hash] that splits its input into leaves of
+// leafSize bytes and hashes the leaves concurrently on multiple
+// goroutines.
+//
+// Each leaf is hashed by a fresh hash returned by newLeaf, after a single
+// zero byte. The final leaf may be shorter than leafSize, and empty input
+// has no leaves. The tree's sum is the sum of another fresh hash returned
+// by newLeaf, after a single one byte followed by the leaf sums in order.
+// The leading bytes separate leaf hashes from the root hash. The result
+// depends only on newLeaf, leafSize and the sequence of bytes written,
+// not on the way the bytes are provided or the scheduling of goroutines.
+// It differs from the sum of a single newLeaf hash over the same input.
+//
+// The returned Hash does not implement [encoding.BinaryMarshaler].
+// Like other hashes, it is not safe for concurrent use by multiple
+// goroutines. NewTree panics if leafSize is not positive.
+func NewTree(newLeaf func() Hash, leafSize int) Hash {
+	if leafSize <= 0 {
+		panic("hash: invalid tree leaf size")
+	}
+	h := newLeaf()
+	workers := runtime.GOMAXPROCS(0)
+	t := &tree{
+		newLeaf:  newLeaf,
+		leafSize: leafSize,
+		size:     h.Size(),
+		free:     make(chan []byte, workers),
+	}
+	for range workers {
+		t.free <- nil
+	}
+	return t
+}
+
+// tree implements the Hash returned by NewTree.
+type tree struct {
+	newLeaf  func() Hash
+	leafSize int
+	size     int
+
+	buf  []byte    // current partial leaf, nil if none is in progress
+	sums []*[]byte // leaf sums, filled in by the leaf goroutines
+	wg   sync.WaitGroup
+
+	// free holds leaf buffers that are not in use. Taking a buffer
+	// before starting a leaf bounds the number of leaves in flight.
+	free chan []byte
+}
+
+const (
+	treeLeafPrefix = 0
+	treeRootPrefix = 1
+)
+
+func (t *tree) Size() int { return t.size }
+
+func (t *tree) BlockSize() int { return t.leafSize }
+
+func (t *tree) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if t.buf == nil {
+			t.buf = <-t.free
+			if t.buf == nil {
+				t.buf = make([]byte, 0, t.leafSize)
+			}
+		}
+		k := min(len(p), t.leafSize-len(t.buf))
+		t.buf = append(t.buf, p[:k]...)
+		p = p[k:]
+		if len(t.buf) == t.leafSize {
+			t.startLeaf()
+		}
+	}
+	return n, nil
+}
+
+// startLeaf hashes the full leaf in t.buf on a new goroutine.
+func (t *tree) startLeaf() {
+	sum := new([]byte)
+	t.sums = append(t.sums, sum)
+	buf := t.buf
+	t.buf = nil
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		*sum = t.leafSum(nil, buf)
+		t.free <- buf[:0]
+	}()
+}
+
+func (t *tree) leafSum(b, leaf []byte) []byte {
+	h := t.newLeaf()
+	h.Write([]byte{treeLeafPrefix})
+	h.Write(leaf)
+	return h.Sum(b)
+}
+
+func (t *tree) Sum(b []byte) []byte {
+	t.wg.Wait()
+	root := t.newLeaf()
+	root.Write([]byte{treeRootPrefix})
+	for _, sum := range t.sums {
+		root.Write(*sum)
+	}
+	if len(t.buf) > 0 {
+		root.Write(t.leafSum(nil, t.buf))
+	}
+	return root.Sum(b)
+}
+
+func (t *tree) Reset() {
+	t.wg.Wait()
+	t.sums = nil
+	if t.buf != nil {
+		t.free <- t.buf[:0]
+		t.buf = nil
+	}
+}
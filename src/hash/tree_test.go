@@ -0,0 +1,106 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hash_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"hash"
+	"hash/crc32"
+	"hash/fnv"
+	"testing"
+)
+
+// naiveTree computes the tree hash sequentially.
+func naiveTree(newLeaf func() hash.Hash, leafSize int, data []byte) []byte {
+	root := newLeaf()
+	root.Write([]byte{1})
+	for len(data) > 0 {
+		n := min(len(data), leafSize)
+		leaf := newLeaf()
+		leaf.Write([]byte{0})
+		leaf.Write(data[:n])
+		root.Write(leaf.Sum(nil))
+		data = data[n:]
+	}
+	return root.Sum(nil)
+}
+
+func TestTree(t *testing.T) {
+	data := make([]byte, 10000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	leaves := []struct {
+		name string
+		new  func() hash.Hash
+	}{
+		{"crc32", func() hash.Hash { return crc32.NewIEEE() }},
+		{"fnv64a", func() hash.Hash { return fnv.New64a() }},
+		{"sha256", sha256.New},
+	}
+	for _, leaf := range leaves {
+		for _, leafSize := range []int{1, 100, 1024, 10000, 20000} {
+			for _, n := range []int{0, 1, 999, 1024, 10000} {
+				want := naiveTree(leaf.new, leafSize, data[:n])
+
+				h := hash.NewTree(leaf.new, leafSize)
+				if h.Size() != leaf.new().Size() {
+					t.Fatalf("%s: Size() = %d, want %d", leaf.name, h.Size(), leaf.new().Size())
+				}
+				h.Write(data[:n])
+				if got := h.Sum(nil); !bytes.Equal(got, want) {
+					t.Errorf("%s(%d): sum of %d bytes = %x, want %x", leaf.name, leafSize, n, got, want)
+				}
+				// Sum must not change the state.
+				if got := h.Sum(nil); !bytes.Equal(got, want) {
+					t.Errorf("%s(%d): second sum of %d bytes = %x, want %x", leaf.name, leafSize, n, got, want)
+				}
+
+				// Writes of irregular sizes must give the same result.
+				h.Reset()
+				for i, p := 0, data[:n]; len(p) > 0; i++ {
+					k := min(len(p), i%37)
+					h.Write(p[:k])
+					p = p[k:]
+				}
+				if got := h.Sum(nil); !bytes.Equal(got, want) {
+					t.Errorf("%s(%d): sum of %d bytes in pieces = %x, want %x", leaf.name, leafSize, n, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestTreeInvalidLeafSize(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewTree with leaf size 0 did not panic")
+		}
+	}()
+	hash.NewTree(sha256.New, 0)
+}
+
+func BenchmarkTree(b *testing.B) {
+	data := make([]byte, 1<<24)
+	b.Run("sha256", func(b *testing.B) {
+		h := sha256.New()
+		b.SetBytes(int64(len(data)))
+		for range b.N {
+			h.Reset()
+			h.Write(data)
+			h.Sum(nil)
+		}
+	})
+	b.Run("tree/sha256", func(b *testing.B) {
+		h := hash.NewTree(sha256.New, 1<<20)
+		b.SetBytes(int64(len(data)))
+		for range b.N {
+			h.Reset()
+			h.Write(data)
+			h.Sum(nil)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hash
+
+import (
+	"errors"
+	"io"
+)
+
+// NewVerifier returns a writer that adds the data written to it to h and
+// checks the result against expected when it is closed. The Close method
+// of the returned writer returns a [*MismatchError] if the sum of h does
+// not equal expected. The comparison takes time independent of the
+// contents of the sums.
+//
+// NewVerifier does not reset h, so any data already written to h is
+// part of the verified sum. Writes after Close return an error.
+func NewVerifier(h Hash, expected []byte) io.WriteCloser {
+	return &verifier{h: h, expected: expected}
+}
+
+type verifier struct {
+	h        Hash
+	expected []byte
+	closed   bool
+	err      error // result of the first Close
+}
+
+var errVerifierClosed = errors.New("hash: write to closed verifier")
+
+func (v *verifier) Write(p []byte) (int, error) {
+	if v.closed {
+		return 0, errVerifierClosed
+	}
+	return v.h.Write(p)
+}
+
+func (v *verifier) Close() error {
+	if v.closed {
+		return v.err
+	}
+	v.closed = true
+	sum := v.h.Sum(nil)
+	if !constantTimeEqual(sum, v.expected) {
+		v.err = &MismatchError{Expected: v.expected, Actual: sum}
+	}
+	return v.err
+}
+
+// constantTimeEqual reports whether a and b are equal, taking time
+// that depends only on their lengths.
+func constantTimeEqual(a, b []byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	var v byte
+	for i := range a {
+		v |= a[i] ^ b[i]
+	}
+	return v == 0
+}
+
+// A MismatchError reports that data written to a verifier
+// returned by [NewVerifier] does not have the expected sum.
+type MismatchError struct {
+	Expected []byte // the sum passed to NewVerifier
+	Actual   []byte // the sum of the data written
+}
+
+func (e *MismatchError) Error() string {
+	return "hash: checksum mismatch: expected " + hexString(e.Expected) + ", got " + hexString(e.Actual)
+}
+
+func hexString(b []byte) string {
+	const digits = "0123456789abcdef"
+	s := make([]byte, 2*len(b))
+	for i, c := range b {
+		s[2*i] = digits[c>>4]
+		s[2*i+1] = digits[c&0xf]
+	}
+	return string(s)
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hash_test
+
+import (
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"hash/crc32"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestVerifier(t *testing.T) {
+	const data = "The quick brown fox jumps over the lazy dog"
+	sum := sha256.Sum256([]byte(data))
+
+	v := hash.NewVerifier(sha256.New(), sum[:])
+	if _, err := io.Copy(v, strings.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Close(); err != nil {
+		t.Errorf("Close of matching data: %v", err)
+	}
+	if _, err := v.Write([]byte("more")); err == nil {
+		t.Errorf("Write after Close succeeded")
+	}
+
+	v = hash.NewVerifier(sha256.New(), sum[:])
+	io.WriteString(v, data[1:])
+	err := v.Close()
+	var mismatch *hash.MismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("Close of mismatching data = %v, want *MismatchError", err)
+	}
+	if string(mismatch.Expected) != string(sum[:]) {
+		t.Errorf("MismatchError.Expected = %x, want %x", mismatch.Expected, sum)
+	}
+	if got := sha256.Sum256([]byte(data[1:])); string(mismatch.Actual) != string(got[:]) {
+		t.Errorf("MismatchError.Actual = %x, want %x", mismatch.Actual, got)
+	}
+	if err2 := v.Close(); err2 != err {
+		t.Errorf("second Close = %v, want %v", err2, err)
+	}
+
+	// An expected sum of the wrong length never matches.
+	v = hash.NewVerifier(crc32.NewIEEE(), []byte{1, 2, 3})
+	if err := v.Close(); err == nil {
+		t.Errorf("Close with short expected sum succeeded")
+	}
+}
+
+func TestMismatchError(t *testing.T) {
+	err := &hash.MismatchError{Expected: []byte{0x01, 0xab}, Actual: []byte{0xff, 0x00}}
+	const want = "hash: checksum mismatch: expected 01ab, got ff00"
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package weak
+
+import "runtime"
+
+// DrainMaps runs a garbage collection and waits for the cleanup of weak
+// maps that it triggers. At least one Map must have been used.
+func DrainMaps() {
+	wait := make(chan struct{}, 1)
+
+	// Set up a one-time notification for the next time the cleanup runs.
+	mapCleanupMu.Lock()
+	mapCleanupNotify = append(mapCleanupNotify, func() {
+		select {
+		case wait <- struct{}{}:
+		default:
+		}
+	})
+
+	runtime.GC()
+	mapCleanupMu.Unlock()
+
+	// Wait until cleanup runs.
+	<-wait
+}
+
+// MapCleanups returns the number of maps registered for cleanup.
+func MapCleanups() int {
+	mapCleanupsMu.Lock()
+	defer mapCleanupsMu.Unlock()
+	return len(mapCleanups)
+}
+
+// Len returns the number of entries in m, including those with dead keys
+// that have not been cleaned up yet.
+func (m *Map[K, V]) Len() int {
+	n := 0
+	m.table().All()(func(Pointer[K], V) bool {
+		n++
+		return true
+	})
+	return n
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package weak
+
+import (
+	"sync"
+	_ "unsafe"
+)
+
+// Map is a concurrent map whose keys are held weakly. An entry does not
+// keep its key reachable, and it is removed from the map some time after
+// the garbage collector finds the key unreachable.
+//
+// Keys are compared by pointer identity, not by the values they point to,
+// and must point into the heap. Values are held strongly, so a value that
+// refers to its own key, directly or indirectly, keeps that entry in the
+// map for as long as the map itself is reachable.
+//
+// The zero Map is empty and ready for use. A Map must not be copied after
+// first use.
+type Map[K any, V any] struct {
+	once sync.Once
+	m    *sync.HashTrieMap[Pointer[K], V]
+}
+
+// table returns the underlying map, creating it and registering it for
+// cleanup on first use.
+func (m *Map[K, V]) table() *sync.HashTrieMap[Pointer[K], V] {
+	m.once.Do(func() {
+		t := new(sync.HashTrieMap[Pointer[K], V])
+		m.m = t
+
+		// Hold the table weakly from the cleanup, so that a Map that
+		// becomes unreachable is freed along with its entries.
+		wt := Make(t)
+		addMapCleanup(func() bool {
+			t := wt.Strong()
+			if t == nil {
+				return false
+			}
+			t.All()(func(key Pointer[K], _ V) bool {
+				// A key, once dead, can never be stored again,
+				// so deleting it cannot race with a new entry.
+				if key.Strong() == nil {
+					t.Delete(key)
+				}
+				return true
+			})
+			return true
+		})
+	})
+	return m.m
+}
+
+func makeKey[K any](key *K) Pointer[K] {
+	if key == nil {
+		panic("weak: Map key is nil")
+	}
+	return Make(key)
+}
+
+// Load returns the value stored in the map for a key, or the zero value
+// if no value is present. The ok result indicates whether a value was
+// found in the map.
+func (m *Map[K, V]) Load(key *K) (value V, ok bool) {
+	return m.table().Load(makeKey(key))
+}
+
+// Store sets the value for a key.
+func (m *Map[K, V]) Store(key *K, value V) {
+	m.table().Store(makeKey(key), value)
+}
+
+// LoadOrStore returns the existing value for the key if present.
+// Otherwise, it stores and returns the given value.
+// The loaded result is true if the value was loaded, false if stored.
+func (m *Map[K, V]) LoadOrStore(key *K, value V) (result V, loaded bool) {
+	return m.table().LoadOrStore(makeKey(key), value)
+}
+
+// Delete deletes the value for a key.
+func (m *Map[K, V]) Delete(key *K) {
+	m.table().Delete(makeKey(key))
+}
+
+// All returns an iterator over each key and value present in the map
+// whose key is still reachable.
+//
+// The iterator does not necessarily correspond to any consistent snapshot
+// of the Map's contents: no key will be visited more than once, but if the
+// value for any key is stored or deleted concurrently (including by yield),
+// the iterator may reflect any mapping for that key from any point during
+// iteration.
+func (m *Map[K, V]) All() func(yield func(*K, V) bool) {
+	t := m.table()
+	return func(yield func(key *K, value V) bool) {
+		t.All()(func(wk Pointer[K], value V) bool {
+			key := wk.Strong()
+			if key == nil {
+				return true
+			}
+			return yield(key, value)
+		})
+	}
+}
+
+var (
+	// mapCleanups are functions that remove dead keys from each live Map.
+	// A cleanup returns false once its Map has been collected, and is then
+	// dropped from the list.
+	//
+	// mapCleanupMu is held across the entire cleanup and protects
+	// mapCleanupNotify, a test-only mechanism that allows tests to wait
+	// for the cleanup to run.
+	mapCleanupOnce   sync.Once
+	mapCleanupMu     sync.Mutex
+	mapCleanupsMu    sync.Mutex
+	mapCleanups      []func() bool
+	mapCleanupNotify []func() // One-time notifications when cleanups finish.
+)
+
+func addMapCleanup(f func() bool) {
+	mapCleanupOnce.Do(func() {
+		runtime_registerMapCleanup(cleanupMaps)
+	})
+	mapCleanupsMu.Lock()
+	mapCleanups = append(mapCleanups, f)
+	mapCleanupsMu.Unlock()
+}
+
+// cleanupMaps is called by the runtime after each garbage collection.
+func cleanupMaps() {
+	mapCleanupMu.Lock()
+
+	mapCleanupsMu.Lock()
+	cf := mapCleanups
+	mapCleanupsMu.Unlock()
+
+	live := make([]bool, len(cf))
+	for i, f := range cf {
+		live[i] = f()
+	}
+
+	// Drop the cleanups of collected maps. Cleanups added since
+	// cf was taken are kept.
+	mapCleanupsMu.Lock()
+	n := 0
+	for i, f := range mapCleanups {
+		if i < len(live) && !live[i] {
+			continue
+		}
+		mapCleanups[n] = f
+		n++
+	}
+	clear(mapCleanups[n:])
+	mapCleanups = mapCleanups[:n]
+	mapCleanupsMu.Unlock()
+
+	for _, f := range mapCleanupNotify {
+		f()
+	}
+	mapCleanupNotify = nil
+
+	mapCleanupMu.Unlock()
+}
+
+// Implemented in runtime.
+
+//go:linkname runtime_registerMapCleanup
+func runtime_registerMapCleanup(cleanup func())
@@ -0,0 +1,96 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package weak_test
+
+import (
+	"internal/weak"
+	"runtime"
+	"testing"
+)
+
+// drain runs enough cleanups that keys which became unreachable before
+// the call are removed. The cleanup is triggered at the start of a cycle,
+// so it only observes keys found dead by the previous one.
+func drain() {
+	weak.DrainMaps()
+	weak.DrainMaps()
+}
+
+func TestMap(t *testing.T) {
+	var m weak.Map[T, int]
+	keys := make([]*T, 10)
+	for i := range keys {
+		keys[i] = &T{a: i}
+		m.Store(keys[i], i)
+	}
+	for i, k := range keys {
+		if v, ok := m.Load(k); !ok || v != i {
+			t.Errorf("Load(keys[%d]) = %d, %v, want %d, true", i, v, ok, i)
+		}
+	}
+	if _, ok := m.Load(&T{}); ok {
+		t.Errorf("Load of missing key succeeded")
+	}
+	if v, loaded := m.LoadOrStore(keys[0], 100); !loaded || v != 0 {
+		t.Errorf("LoadOrStore(keys[0]) = %d, %v, want 0, true", v, loaded)
+	}
+	m.Delete(keys[1])
+	if _, ok := m.Load(keys[1]); ok {
+		t.Errorf("Load after Delete succeeded")
+	}
+
+	seen := make(map[*T]int)
+	m.All()(func(k *T, v int) bool {
+		seen[k] = v
+		return true
+	})
+	if len(seen) != len(keys)-1 {
+		t.Errorf("All visited %d entries, want %d", len(seen), len(keys)-1)
+	}
+
+	// Drop the odd keys.
+	for i := 1; i < len(keys); i += 2 {
+		keys[i] = nil
+	}
+	drain()
+	if n := m.Len(); n != len(keys)/2 {
+		t.Errorf("got %d entries after cleanup, want %d", n, len(keys)/2)
+	}
+	for i := 0; i < len(keys); i += 2 {
+		if v, ok := m.Load(keys[i]); !ok || v != i {
+			t.Errorf("Load(keys[%d]) = %d, %v after cleanup, want %d, true", i, v, ok, i)
+		}
+	}
+	runtime.KeepAlive(keys)
+}
+
+func TestMapNilKey(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Store with nil key did not panic")
+		}
+	}()
+	var m weak.Map[T, int]
+	m.Store(nil, 1)
+}
+
+func TestMapCollected(t *testing.T) {
+	keep := new(weak.Map[T, int])
+	keep.Store(new(T), 0)
+	drain()
+	n := weak.MapCleanups()
+
+	m := new(weak.Map[T, int])
+	m.Store(new(T), 1)
+	if got := weak.MapCleanups(); got != n+1 {
+		t.Fatalf("got %d registered maps, want %d", got, n+1)
+	}
+	m = nil
+	drain()
+	if got := weak.MapCleanups(); got != n {
+		t.Errorf("got %d registered maps after collecting a map, want %d", got, n)
+	}
+	runtime.KeepAlive(keep)
+}
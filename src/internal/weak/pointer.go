@@ -3,7 +3,8 @@
 // license that can be found in the LICENSE file.
 
 /*
-The weak package is a package for managing weak pointers.
+The weak package is a package for managing weak pointers and maps with
+weakly held keys.
 
 Weak pointers are pointers that explicitly do not keep a value live and
 must be queried for a regular Go pointer.
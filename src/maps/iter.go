@@ -45,6 +45,57 @@ func Values[Map ~map[K]V, K comparable, V any](m Map) iter.Seq[V] {
 	}
 }
 
+// Filtered returns an iterator over the key-value pairs from m for which
+// keep returns true. The iteration order is not specified and is not
+// guaranteed to be the same from one call to the next.
+func Filtered[Map ~map[K]V, K comparable, V any](m Map, keep func(K, V) bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range m {
+			if keep(k, v) && !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// Diff returns iterators over the keys that differ between old and new:
+// added produces the keys present in new but not in old, removed produces
+// the keys present in old but not in new, and changed produces the keys
+// present in both whose values differ. Values are compared using ==.
+// The maps are read each time an iterator is used, not when Diff is called.
+// The iteration order is not specified and is not guaranteed
+// to be the same from one call to the next.
+func Diff[M1, M2 ~map[K]V, K, V comparable](old M1, new M2) (added, removed, changed iter.Seq[K]) {
+	return DiffFunc(old, new, func(v1, v2 V) bool { return v1 == v2 })
+}
+
+// DiffFunc is like [Diff], but compares values using eq.
+// Keys are still compared with ==.
+func DiffFunc[M1 ~map[K]V1, M2 ~map[K]V2, K comparable, V1, V2 any](old M1, new M2, eq func(V1, V2) bool) (added, removed, changed iter.Seq[K]) {
+	added = func(yield func(K) bool) {
+		for k := range new {
+			if _, ok := old[k]; !ok && !yield(k) {
+				return
+			}
+		}
+	}
+	removed = func(yield func(K) bool) {
+		for k := range old {
+			if _, ok := new[k]; !ok && !yield(k) {
+				return
+			}
+		}
+	}
+	changed = func(yield func(K) bool) {
+		for k, v1 := range old {
+			if v2, ok := new[k]; ok && !eq(v1, v2) && !yield(k) {
+				return
+			}
+		}
+	}
+	return added, removed, changed
+}
+
 // Insert adds the key-value pairs from seq to m.
 // If a key in seq already exists in m, its value will be overwritten.
 func Insert[Map ~map[K]V, K comparable, V any](m Map, seq iter.Seq2[K, V]) {
@@ -60,3 +111,15 @@ func Collect[K comparable, V any](seq iter.Seq2[K, V]) map[K]V {
 	Insert(m, seq)
 	return m
 }
+
+// CollectN is like [Collect], but the new map is created with room for
+// n key-value pairs, so that collecting a sequence of about n pairs does
+// not grow it repeatedly. If n is negative, CollectN panics.
+func CollectN[K comparable, V any](seq iter.Seq2[K, V], n int) map[K]V {
+	if n < 0 {
+		panic("cannot be negative")
+	}
+	m := make(map[K]V, n)
+	Insert(m, seq)
+	return m
+}
@@ -5,7 +5,9 @@
 package maps
 
 import (
+	"fmt"
 	"slices"
+	"strings"
 	"testing"
 )
 
@@ -69,6 +71,94 @@ func TestValues(t *testing.T) {
 	}
 }
 
+func TestFiltered(t *testing.T) {
+	m := make(map[int]int)
+	for i := range 10 {
+		m[i] = i * i
+	}
+	even := func(k, _ int) bool { return k%2 == 0 }
+	got := make(map[int]int)
+	for k, v := range Filtered(m, even) {
+		got[k] = v
+	}
+	want := map[int]int{0: 0, 2: 4, 4: 16, 6: 36, 8: 64}
+	if !Equal(got, want) {
+		t.Errorf("Filtered(%v) = %v, want %v", m, got, want)
+	}
+	if len(m) != 10 {
+		t.Errorf("Filtered modified m: len(m) = %d, want 10", len(m))
+	}
+
+	cnt := 0
+	for range Filtered(m, even) {
+		cnt++
+		break
+	}
+	if cnt != 1 {
+		t.Errorf("iteration continued after break: %d pairs yielded", cnt)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}
+	new := map[string]int{"b": 2, "c": 30, "d": 40, "e": 5, "f": 6}
+	added, removed, changed := Diff(old, new)
+	for _, test := range []struct {
+		name string
+		seq  func(func(string) bool)
+		want []string
+	}{
+		{"added", added, []string{"e", "f"}},
+		{"removed", removed, []string{"a"}},
+		{"changed", changed, []string{"c", "d"}},
+	} {
+		if got := slices.Sorted(test.seq); !slices.Equal(got, test.want) {
+			t.Errorf("Diff(%v, %v): %s = %v, want %v", old, new, test.name, got, test.want)
+		}
+	}
+
+	added, removed, changed = Diff(old, old)
+	for k := range added {
+		t.Errorf("Diff(m, m): added produced %q", k)
+	}
+	for k := range removed {
+		t.Errorf("Diff(m, m): removed produced %q", k)
+	}
+	for k := range changed {
+		t.Errorf("Diff(m, m): changed produced %q", k)
+	}
+
+	cnt := 0
+	for range changed {
+		cnt++
+		break
+	}
+	_, _, changed = Diff(old, new)
+	for range changed {
+		cnt++
+		break
+	}
+	if cnt != 1 {
+		t.Errorf("iteration continued after break: %d keys yielded", cnt)
+	}
+}
+
+func TestDiffFunc(t *testing.T) {
+	old := map[int]string{1: "one", 2: "two", 3: "three"}
+	new := map[int][]byte{1: []byte("ONE"), 2: []byte("deux"), 4: []byte("four")}
+	eq := func(s string, b []byte) bool { return strings.EqualFold(s, string(b)) }
+	added, removed, changed := DiffFunc(old, new, eq)
+	if got, want := slices.Sorted(added), []int{4}; !slices.Equal(got, want) {
+		t.Errorf("DiffFunc: added = %v, want %v", got, want)
+	}
+	if got, want := slices.Sorted(removed), []int{3}; !slices.Equal(got, want) {
+		t.Errorf("DiffFunc: removed = %v, want %v", got, want)
+	}
+	if got, want := slices.Sorted(changed), []int{2}; !slices.Equal(got, want) {
+		t.Errorf("DiffFunc: changed = %v, want %v", got, want)
+	}
+}
+
 func TestInsert(t *testing.T) {
 	got := map[int]int{
 		1: 1,
@@ -114,3 +204,47 @@ func TestCollect(t *testing.T) {
 		t.Errorf("Collect got: %v, want: %v", got, m)
 	}
 }
+
+func TestCollectN(t *testing.T) {
+	m := map[int]int{0: 1, 2: 3, 4: 5, 6: 7, 8: 9}
+	for _, n := range []int{0, 2, len(m), 100} {
+		got := CollectN(All(m), n)
+		if !Equal(got, m) {
+			t.Errorf("CollectN(seq, %d) got: %v, want: %v", n, got, m)
+		}
+	}
+	got := CollectN(All(m), 1000)
+	if c := Cap(got); c < 1000 {
+		t.Errorf("Cap(CollectN(seq, 1000)) = %d, want >= 1000", c)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("CollectN with negative n did not panic")
+		}
+	}()
+	CollectN(All(m), -1)
+}
+
+func BenchmarkCollect(b *testing.B) {
+	for _, n := range []int{8, 1000, 1000000} {
+		seq := func(yield func(int, int) bool) {
+			for i := range n {
+				if !yield(i*0x9e3779b9, i) {
+					return
+				}
+			}
+		}
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				Collect(seq)
+			}
+		})
+		b.Run(fmt.Sprint("N/", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				CollectN(seq, n)
+			}
+		})
+	}
+}
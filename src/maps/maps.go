@@ -6,7 +6,7 @@
 package maps
 
 import (
-	_ "unsafe"
+	"unsafe"
 )
 
 // Equal reports whether two maps contain the same key/value pairs.
@@ -52,6 +52,182 @@ func Clone[M ~map[K]V, K comparable, V any](m M) M {
 	return clone(m).(M)
 }
 
+// cloneCap is implemented in the runtime package.
+//
+//go:linkname cloneCap maps.cloneCap
+func cloneCap(m any) any
+
+// CloneCap is like [Clone], but the copy has the same capacity as m
+// (see [Cap]) rather than just enough room for its current contents.
+// This avoids growing the copy again when it is refilled, for example
+// after m has had many of its entries deleted.
+func CloneCap[M ~map[K]V, K comparable, V any](m M) M {
+	// Preserve nil in case it matters.
+	if m == nil {
+		return nil
+	}
+	return cloneCap(m).(M)
+}
+
+// Invert returns a map from each value in m to its key. If several keys
+// have the same value, the result maps that value to one of them; which
+// one is not specified. Use [InvertMulti] to keep all of them.
+// Invert returns nil if m is nil.
+func Invert[M ~map[K]V, K, V comparable](m M) map[V]K {
+	if m == nil {
+		return nil
+	}
+	r := make(map[V]K, len(m))
+	for k, v := range m {
+		r[v] = k
+	}
+	return r
+}
+
+// InvertMulti returns a map from each value in m to all the keys that
+// have it. The order of the keys for each value is not specified.
+// InvertMulti returns nil if m is nil.
+func InvertMulti[M ~map[K]V, K, V comparable](m M) map[V][]K {
+	if m == nil {
+		return nil
+	}
+	r := make(map[V][]K)
+	for k, v := range m {
+		r[v] = append(r[v], k)
+	}
+	return r
+}
+
+// keys is implemented in the runtime package.
+//
+//go:linkname keys maps.keys
+//go:noescape
+func keys(m any, slice unsafe.Pointer)
+
+// KeysSlice returns a new slice holding the keys of m, in no particular
+// order. The slice is allocated with capacity len(m) and filled directly
+// from the map, so it is cheaper than collecting [Keys]. KeysSlice
+// returns nil if m is empty.
+func KeysSlice[M ~map[K]V, K comparable, V any](m M) []K {
+	if len(m) == 0 {
+		return nil
+	}
+	r := make([]K, 0, len(m))
+	keys(m, unsafe.Pointer(&r))
+	return r
+}
+
+// values is implemented in the runtime package.
+//
+//go:linkname values maps.values
+//go:noescape
+func values(m any, slice unsafe.Pointer)
+
+// ValuesSlice returns a new slice holding the values of m, in no
+// particular order. Like [KeysSlice], it allocates the slice with
+// capacity len(m). ValuesSlice returns nil if m is empty.
+func ValuesSlice[M ~map[K]V, K comparable, V any](m M) []V {
+	if len(m) == 0 {
+		return nil
+	}
+	r := make([]V, 0, len(m))
+	values(m, unsafe.Pointer(&r))
+	return r
+}
+
+// grow is implemented in the runtime package.
+//
+//go:linkname grow maps.grow
+func grow(m any, n int)
+
+// Grow increases the capacity of m, if necessary, to guarantee space
+// for another n key/value pairs. After Grow(m, n), at least n pairs
+// can be added to m without it growing again. If m is nil, Grow does
+// nothing. If n is negative or too large to allocate the memory,
+// Grow panics.
+func Grow[M ~map[K]V, K comparable, V any](m M, n int) {
+	if n < 0 {
+		panic("cannot be negative")
+	}
+	if m == nil {
+		return
+	}
+	grow(m, n)
+}
+
+// capacity is implemented in the runtime package.
+//
+//go:linkname capacity maps.capacity
+func capacity(m any) int
+
+// Cap returns the capacity of m: the number of key/value pairs m can
+// hold before it next grows. Adding pairs until len(m) exceeds Cap(m)
+// allocates no new buckets. The capacity of a nil map is zero.
+func Cap[M ~map[K]V, K comparable, V any](m M) int {
+	if m == nil {
+		return 0
+	}
+	return capacity(m)
+}
+
+// freeze is implemented in the runtime package.
+//
+//go:linkname freeze maps.freeze
+func freeze(m any)
+
+// Freeze makes m read-only. Any later attempt to modify m, by assignment,
+// delete, clear or a function in this package, panics. A frozen map may
+// be read and iterated over by any number of goroutines concurrently.
+// A map cannot be unfrozen, but a [Clone] of it is not frozen.
+// Freeze does nothing if m is nil.
+func Freeze[M ~map[K]V, K comparable, V any](m M) {
+	if m == nil {
+		return
+	}
+	freeze(m)
+}
+
+// clip is implemented in the runtime package.
+//
+//go:linkname clip maps.clip
+func clip(m any)
+
+// Clip removes unused capacity from m, releasing the memory that m
+// kept from when it held more key/value pairs. The contents of m are
+// unchanged.
+func Clip[M ~map[K]V, K comparable, V any](m M) {
+	if m == nil {
+		return
+	}
+	clip(m)
+}
+
+// GetOrInsert returns the value for key in m and true if key is present.
+// Otherwise, it adds key to m with the given value and returns value
+// and false. Like an assignment, GetOrInsert panics if m is nil.
+func GetOrInsert[M ~map[K]V, K comparable, V any](m M, key K, value V) (actual V, loaded bool) {
+	// A lookup costs much less than an assignment, and key is usually
+	// present, so look it up before assigning.
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+	m[key] = value
+	return value, false
+}
+
+// pop is implemented in the runtime package.
+//
+//go:linkname pop maps.pop
+//go:noescape
+func pop(m any, key, elem unsafe.Pointer) bool
+
+// Pop deletes key from m and returns its value and true if it was
+// present. Otherwise, it returns the zero value and false.
+func Pop[M ~map[K]V, K comparable, V any](m M, key K) (value V, ok bool) {
+	ok = pop(m, unsafe.Pointer(&key), unsafe.Pointer(&value))
+	return value, ok
+}
+
 // Copy copies all key/value pairs in src adding them to dst.
 // When a key in src is already present in dst,
 // the value in dst will be overwritten by the value associated
@@ -62,7 +238,39 @@ func Copy[M1 ~map[K]V, M2 ~map[K]V, K comparable, V any](dst M1, src M2) {
 	}
 }
 
+// Keep deletes any key/value pairs from m for which keep returns false.
+func Keep[M ~map[K]V, K comparable, V any](m M, keep func(K, V) bool) {
+	DeleteFunc(m, func(k K, v V) bool { return !keep(k, v) })
+}
+
+// assign is implemented in the runtime package.
+//
+//go:linkname assign maps.assign
+//go:noescape
+func assign(m any, key unsafe.Pointer) (elem unsafe.Pointer, found bool)
+
+// Merge adds all key/value pairs in src to dst. When a key in src is
+// already present in dst, the value stored in dst is the result of
+// resolve called with the key, the value in dst and the value in src.
+// If dst is empty, Merge makes room in it for all of src up front.
+func Merge[M1 ~map[K]V, M2 ~map[K]V, K comparable, V any](dst M1, src M2, resolve func(key K, old, new V) V) {
+	if len(dst) == 0 {
+		Grow(dst, len(src))
+	}
+	for k, v := range src {
+		elem, found := assign(dst, unsafe.Pointer(&k))
+		if !found {
+			*(*V)(elem) = v
+			continue
+		}
+		// resolve may modify dst, which would leave elem stale, so
+		// assign its result again.
+		dst[k] = resolve(k, *(*V)(elem), v)
+	}
+}
+
 // DeleteFunc deletes any key/value pairs from m for which del returns true.
+// m keeps its capacity; to release it after deleting most of m, call [Clip].
 func DeleteFunc[M ~map[K]V, K comparable, V any](m M, del func(K, V) bool) {
 	for k, v := range m {
 		if del(k, v) {
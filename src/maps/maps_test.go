@@ -6,6 +6,8 @@ package maps
 
 import (
 	"math"
+	"runtime"
+	"slices"
 	"strconv"
 	"testing"
 )
@@ -104,6 +106,42 @@ func TestCloneNil(t *testing.T) {
 	}
 }
 
+func TestCloneCap(t *testing.T) {
+	var nilMap map[string]int
+	if mc := CloneCap(nilMap); mc != nil {
+		t.Errorf("CloneCap(nil) = %v, want nil", mc)
+	}
+
+	m := make(map[int]int)
+	for i := range 1000 {
+		m[i] = i
+	}
+	for i := 10; i < 1000; i++ {
+		delete(m, i)
+	}
+	mc := CloneCap(m)
+	if !Equal(mc, m) {
+		t.Errorf("CloneCap(%v) = %v", m, mc)
+	}
+	if got, want := Cap(mc), Cap(m); got != want {
+		t.Errorf("Cap(CloneCap(m)) = %d, want %d", got, want)
+	}
+	if c := Cap(Clone(m)); c >= Cap(m) {
+		t.Errorf("Cap(Clone(m)) = %d, want less than %d", c, Cap(m))
+	}
+
+	// Large keys and values are stored indirectly.
+	big := make(map[[200]byte][200]byte, 100)
+	big[[200]byte{1}] = [200]byte{2}
+	bc := CloneCap(big)
+	if !Equal(bc, big) {
+		t.Errorf("CloneCap of map with indirect keys and values differs from original")
+	}
+	if got, want := Cap(bc), Cap(big); got != want {
+		t.Errorf("Cap(CloneCap(big)) = %d, want %d", got, want)
+	}
+}
+
 func TestCopy(t *testing.T) {
 	mc := Clone(m1)
 	Copy(mc, mc)
@@ -121,6 +159,134 @@ func TestCopy(t *testing.T) {
 	Copy(make(M1), make(M2))
 }
 
+func TestInvert(t *testing.T) {
+	if got, want := Invert(m1), map[int]int{2: 1, 4: 2, 8: 4, 16: 8}; !Equal(got, want) {
+		t.Errorf("Invert(%v) = %v, want %v", m1, got, want)
+	}
+	m := map[string]int{"a": 1, "b": 1, "c": 2}
+	inv := Invert(m)
+	if len(inv) != 2 || inv[2] != "c" || (inv[1] != "a" && inv[1] != "b") {
+		t.Errorf("Invert(%v) = %v", m, inv)
+	}
+	var nilMap map[string]int
+	if Invert(nilMap) != nil {
+		t.Errorf("Invert(nil) != nil")
+	}
+}
+
+func TestInvertMulti(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 1, "c": 2}
+	got := InvertMulti(m)
+	for _, ks := range got {
+		slices.Sort(ks)
+	}
+	want := map[int][]string{1: {"a", "b"}, 2: {"c"}}
+	if !EqualFunc(got, want, slices.Equal[[]string]) {
+		t.Errorf("InvertMulti(%v) = %v, want %v", m, got, want)
+	}
+	var nilMap map[string]int
+	if InvertMulti(nilMap) != nil {
+		t.Errorf("InvertMulti(nil) != nil")
+	}
+}
+
+func TestKeysValuesSlice(t *testing.T) {
+	// Sizes up to a few buckets, plus enough to be caught mid-growth.
+	for _, size := range []int{0, 1, 8, 9, 100, 1000} {
+		m := make(map[int]string)
+		var wantKeys []int
+		var wantValues []string
+		for i := range size {
+			m[i] = strconv.Itoa(i)
+			wantKeys = append(wantKeys, i)
+			wantValues = append(wantValues, strconv.Itoa(i))
+		}
+		slices.Sort(wantValues)
+
+		keys := KeysSlice(m)
+		if len(keys) != cap(keys) {
+			t.Errorf("size %d: KeysSlice has len %d, cap %d", size, len(keys), cap(keys))
+		}
+		if slices.Sort(keys); !slices.Equal(keys, wantKeys) {
+			t.Errorf("KeysSlice(%v) = %v, want %v", m, keys, wantKeys)
+		}
+		values := ValuesSlice(m)
+		if len(values) != cap(values) {
+			t.Errorf("size %d: ValuesSlice has len %d, cap %d", size, len(values), cap(values))
+		}
+		if slices.Sort(values); !slices.Equal(values, wantValues) {
+			t.Errorf("ValuesSlice(%v) = %v, want %v", m, values, wantValues)
+		}
+	}
+
+	var nilMap map[int]string
+	if KeysSlice(nilMap) != nil {
+		t.Errorf("KeysSlice(nil) != nil")
+	}
+	if ValuesSlice(nilMap) != nil {
+		t.Errorf("ValuesSlice(nil) != nil")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	dst := map[string]int{"a": 1, "b": 2}
+	src := map[string]int{"b": 10, "c": 3}
+	var calls int
+	Merge(dst, src, func(k string, old, new int) int {
+		calls++
+		if k != "b" || old != 2 || new != 10 {
+			t.Errorf("resolve(%q, %d, %d), want resolve(%q, 2, 10)", k, old, new, "b")
+		}
+		return old + new
+	})
+	if calls != 1 {
+		t.Errorf("resolve called %d times, want 1", calls)
+	}
+	if want := map[string]int{"a": 1, "b": 12, "c": 3}; !Equal(dst, want) {
+		t.Errorf("Merge result = %v, want %v", dst, want)
+	}
+
+	// Merging an empty or nil src does nothing, even into a nil dst.
+	var nilMap map[string]int
+	Merge(nilMap, map[string]int{}, nil)
+	Merge(dst, nilMap, nil)
+	if len(dst) != 3 {
+		t.Errorf("len(dst) = %d after merging nil, want 3", len(dst))
+	}
+
+	big := make(map[int]int)
+	for i := 0; i < 1000; i++ {
+		big[i] = i
+	}
+	c := make(map[int]int)
+	Merge(c, big, nil)
+	if !Equal(c, big) {
+		t.Errorf("Merge into empty map did not copy all pairs")
+	}
+
+	// Keys already in dst need no room.
+	capBefore := Cap(c)
+	Merge(c, big, func(k, old, new int) int { return old + new })
+	if got := Cap(c); got != capBefore {
+		t.Errorf("Cap(dst) = %d after merging present keys, want %d", got, capBefore)
+	}
+	if c[7] != 14 {
+		t.Errorf("c[7] = %d after merge, want 14", c[7])
+	}
+
+	// resolve may grow dst.
+	d := map[int]int{0: 1}
+	Merge(d, map[int]int{0: 2}, func(k, old, new int) int {
+		for i := 1; i < 1000; i++ {
+			d[i] = i
+		}
+		return old + new
+	})
+	if d[0] != 3 || len(d) != 1000 {
+		t.Errorf("d[0] = %d, len(d) = %d after merge, want 3, 1000", d[0], len(d))
+	}
+}
+
 func TestDeleteFunc(t *testing.T) {
 	mc := Clone(m1)
 	DeleteFunc(mc, func(int, int) bool { return false })
@@ -134,8 +300,144 @@ func TestDeleteFunc(t *testing.T) {
 	}
 }
 
+func TestGetOrInsert(t *testing.T) {
+	m := map[string]int{"a": 1}
+	if v, loaded := GetOrInsert(m, "a", 2); v != 1 || !loaded {
+		t.Errorf("GetOrInsert(m, %q, 2) = %d, %v, want 1, true", "a", v, loaded)
+	}
+	if v, loaded := GetOrInsert(m, "b", 3); v != 3 || loaded {
+		t.Errorf("GetOrInsert(m, %q, 3) = %d, %v, want 3, false", "b", v, loaded)
+	}
+	if want := map[string]int{"a": 1, "b": 3}; !Equal(m, want) {
+		t.Errorf("after GetOrInsert, m = %v, want %v", m, want)
+	}
+
+	// NaN keys are never found, so each call adds an entry.
+	mf := map[float64]int{}
+	GetOrInsert(mf, math.NaN(), 1)
+	if _, loaded := GetOrInsert(mf, math.NaN(), 2); loaded || len(mf) != 2 {
+		t.Errorf("GetOrInsert with NaN key: loaded = %v, len = %d, want false, 2", loaded, len(mf))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("GetOrInsert on nil map did not panic")
+		}
+	}()
+	var nilMap map[string]int
+	GetOrInsert(nilMap, "a", 1)
+}
+
+func TestPop(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2}
+	if v, ok := Pop(m, "a"); v != 1 || !ok {
+		t.Errorf("Pop(m, %q) = %d, %v, want 1, true", "a", v, ok)
+	}
+	if v, ok := Pop(m, "a"); v != 0 || ok {
+		t.Errorf("second Pop(m, %q) = %d, %v, want 0, false", "a", v, ok)
+	}
+	if want := map[string]int{"b": 2}; !Equal(m, want) {
+		t.Errorf("after Pop, m = %v, want %v", m, want)
+	}
+
+	var nilMap map[string]int
+	if v, ok := Pop(nilMap, "a"); v != 0 || ok {
+		t.Errorf("Pop(nil, %q) = %d, %v, want 0, false", "a", v, ok)
+	}
+
+	type K [17]float64 // > 128 bytes, stored indirectly
+	big := map[K]K{{1}: {2: 3}}
+	if v, ok := Pop(big, K{1}); v != (K{2: 3}) || !ok {
+		t.Errorf("Pop(big, K{1}) = %v, %v, want %v, true", v, ok, K{2: 3})
+	}
+	if len(big) != 0 {
+		t.Errorf("len(big) = %d after Pop, want 0", len(big))
+	}
+
+	mf := map[float64]int{math.NaN(): 1}
+	if _, ok := Pop(mf, math.NaN()); ok || len(mf) != 1 {
+		t.Errorf("Pop with NaN key: ok = %v, len = %d, want false, 1", ok, len(mf))
+	}
+}
+
+func TestKeep(t *testing.T) {
+	mc := Clone(m1)
+	Keep(mc, func(k, v int) bool { return k <= 3 })
+	want := map[int]int{1: 2, 2: 4}
+	if !Equal(mc, want) {
+		t.Errorf("Keep result = %v, want %v", mc, want)
+	}
+}
+
+func TestDeleteFuncKeepsCapacity(t *testing.T) {
+	const N = 100000
+	m := make(map[int]string)
+	for i := 0; i < N; i++ {
+		m[i] = strconv.Itoa(i)
+	}
+	before := Cap(m)
+	DeleteFunc(m, func(k int, _ string) bool { return k%10 != 0 })
+	if len(m) != N/10 {
+		t.Fatalf("len(m) = %d after DeleteFunc, want %d", len(m), N/10)
+	}
+	if c := Cap(m); c != before {
+		t.Errorf("Cap(m) = %d after DeleteFunc, want %d", c, before)
+	}
+	Clip(m)
+	if c := Cap(m); c >= before || c < len(m) {
+		t.Errorf("Cap(m) = %d after Clip, want less than %d and at least %d", c, before, len(m))
+	}
+	for i := 0; i < N; i += 10 {
+		if got, want := m[i], strconv.Itoa(i); got != want {
+			t.Errorf("m[%d] = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestDeleteFuncDuringIteration(t *testing.T) {
+	m := make(map[int]int)
+	for i := 0; i < 1000; i++ {
+		m[i] = i
+	}
+	seen := make(map[int]int)
+	for k := range m {
+		if len(seen) == 0 {
+			DeleteFunc(m, func(k, _ int) bool { return k%100 != 0 })
+		}
+		seen[k]++
+	}
+	if len(m) != 10 {
+		t.Errorf("len(m) = %d, want 10", len(m))
+	}
+	for k, n := range seen {
+		if n != 1 {
+			t.Errorf("iteration visited key %d %d times", k, n)
+		}
+	}
+	for k := range m {
+		if seen[k] != 1 {
+			t.Errorf("iteration missed key %d still in the map", k)
+		}
+	}
+}
+
 var n map[int]int
 
+func BenchmarkEqual(b *testing.B) {
+	for _, n := range []int{8, 1000, 1 << 20} {
+		m1 := make(map[int]int, n)
+		for i := 0; i < n; i++ {
+			m1[i] = i
+		}
+		m2 := Clone(m1)
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Equal(m1, m2)
+			}
+		})
+	}
+}
+
 func BenchmarkMapClone(b *testing.B) {
 	var m = make(map[int]int)
 	for i := 0; i < 1000000; i++ {
@@ -147,6 +449,25 @@ func BenchmarkMapClone(b *testing.B) {
 	}
 }
 
+func BenchmarkKeysSlice(b *testing.B) {
+	m := make(map[int]int, 1000)
+	for i := range 1000 {
+		m[i] = i
+	}
+	b.Run("Collect", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			slices.Collect(Keys(m))
+		}
+	})
+	b.Run("KeysSlice", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			KeysSlice(m)
+		}
+	})
+}
+
 func TestCloneWithDelete(t *testing.T) {
 	var m = make(map[int]int)
 	for i := 0; i < 32; i++ {
@@ -240,3 +561,250 @@ func TestCloneLarge(t *testing.T) {
 		}
 	}
 }
+
+func TestCloneOverflow(t *testing.T) {
+	// Large maps have overflow buckets, which are copied separately
+	// from the bucket array.
+	m := make(map[string]*int)
+	const N = 100000
+	for i := 0; i < N; i++ {
+		m[strconv.Itoa(i)] = new(int)
+	}
+	c := Clone(m)
+	runtime.GC()
+	if len(c) != N {
+		t.Fatalf("len(c) = %d, want %d", len(c), N)
+	}
+	for k, v := range m {
+		if c[k] != v {
+			t.Fatalf("c[%q] = %p, want %p", k, c[k], v)
+		}
+	}
+	// The maps must be independent.
+	for i := 0; i < N; i += 2 {
+		delete(m, strconv.Itoa(i))
+		c[strconv.Itoa(i+1)] = nil
+	}
+	for i := N; i < 2*N; i++ {
+		c[strconv.Itoa(i)] = nil
+	}
+	runtime.GC()
+	for i := 0; i < N; i++ {
+		k := strconv.Itoa(i)
+		if _, ok := c[k]; !ok {
+			t.Errorf("c[%q] missing after delete from m", k)
+		}
+		if _, ok := m[k]; ok == (i%2 == 0) {
+			t.Errorf("m[%q] present = %v, want %v", k, ok, i%2 != 0)
+		} else if ok && m[k] == nil {
+			t.Errorf("m[%q] = nil after assignment to c", k)
+		}
+	}
+}
+
+func TestGrow(t *testing.T) {
+	type K [17]float64 // > 128 bytes, stored indirectly
+	for _, n := range []int{0, 1, 8, 26, 1000} {
+		// 26 entries capture the map in the middle of a grow.
+		m := map[int]string{}
+		big := map[K]K{}
+		for i := 0; i < n; i++ {
+			m[i] = strconv.Itoa(i)
+			big[K{float64(i)}] = K{1: float64(i)}
+		}
+		m2 := map[float64]int{math.NaN(): 1, math.NaN(): 2, 0: 3}
+
+		Grow(m, 10000)
+		Grow(big, 10000)
+		Grow(m2, 10000)
+		runtime.GC()
+
+		if len(m) != n || len(big) != n {
+			t.Fatalf("n=%d: after Grow, len(m) = %d, len(big) = %d", n, len(m), len(big))
+		}
+		for i := 0; i < n; i++ {
+			if got, want := m[i], strconv.Itoa(i); got != want {
+				t.Errorf("n=%d: m[%d] = %q, want %q", n, i, got, want)
+			}
+			if got, want := big[K{float64(i)}], (K{1: float64(i)}); got != want {
+				t.Errorf("n=%d: big[%d] = %v, want %v", n, i, got, want)
+			}
+		}
+		sum := 0
+		for _, v := range m2 {
+			sum += v
+		}
+		if len(m2) != 3 || sum != 6 || m2[0] != 3 {
+			t.Errorf("n=%d: after Grow, m2 = %v, want NaN keys with 1 and 2 and 0:3", n, m2)
+		}
+		for i := n; i < n+10000; i++ {
+			m[i] = strconv.Itoa(i)
+		}
+		if len(m) != n+10000 {
+			t.Errorf("n=%d: len(m) = %d after inserting 10000, want %d", n, len(m), n+10000)
+		}
+	}
+}
+
+func TestGrowNil(t *testing.T) {
+	var m map[string]int
+	Grow(m, 10) // must not panic
+	if m != nil {
+		t.Errorf("Grow(nil) made a map")
+	}
+}
+
+func TestGrowNegative(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Grow with negative n did not panic")
+		}
+	}()
+	Grow(map[string]int{}, -1)
+}
+
+func TestCap(t *testing.T) {
+	var nilMap map[int]int
+	if c := Cap(nilMap); c != 0 {
+		t.Errorf("Cap(nil) = %d, want 0", c)
+	}
+	for _, hint := range []int{0, 1, 8, 9, 100, 1000} {
+		m := make(map[int]int, hint)
+		c := Cap(m)
+		if c < hint {
+			t.Errorf("Cap(make(map, %d)) = %d, want >= %d", hint, c, hint)
+		}
+		// Filling m up to its capacity must not grow it.
+		for i := 0; i < c; i++ {
+			m[i] = i
+		}
+		if got := Cap(m); got != c {
+			t.Errorf("hint=%d: Cap changed from %d to %d while filling", hint, c, got)
+		}
+		m[c] = c
+		if got := Cap(m); got <= c {
+			t.Errorf("hint=%d: Cap = %d after exceeding capacity %d", hint, got, c)
+		}
+	}
+
+	m := map[int]int{1: 1}
+	Grow(m, 1000)
+	if c := Cap(m); c < 1001 {
+		t.Errorf("after Grow(m, 1000), Cap(m) = %d, want >= 1001", c)
+	}
+	Clip(m)
+	if c := Cap(m); c < 1 || c > 8 {
+		t.Errorf("after Clip, Cap(m) = %d, want between 1 and 8", c)
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	type K [17]float64 // > 128 bytes, stored indirectly
+	mi := map[int]int{}
+	ms := map[string]int{}
+	mk := map[K]K{}
+	// 26 entries freeze the maps in the middle of a grow.
+	for i := 0; i < 26; i++ {
+		mi[i] = i
+		ms[strconv.Itoa(i)] = i
+		mk[K{float64(i)}] = K{1: float64(i)}
+	}
+	Freeze(mi)
+	Freeze(ms)
+	Freeze(mk)
+	Freeze(mi) // freezing twice is fine
+
+	mustPanic := func(name string, f func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s on frozen map did not panic", name)
+			}
+		}()
+		f()
+	}
+	mustPanic("assignment", func() { mi[100] = 1 })
+	mustPanic("assignment to existing key", func() { mi[1] = 2 })
+	mustPanic("string assignment", func() { ms["x"] = 1 })
+	mustPanic("indirect assignment", func() { mk[K{}] = K{} })
+	mustPanic("delete", func() { delete(mi, 1) })
+	mustPanic("delete of missing key", func() { delete(ms, "x") })
+	mustPanic("clear", func() { clear(mk) })
+	mustPanic("Grow", func() { Grow(mi, 1) })
+	mustPanic("Clip", func() { Clip(mi) })
+	mustPanic("DeleteFunc", func() { DeleteFunc(ms, func(string, int) bool { return true }) })
+
+	for i := 0; i < 26; i++ {
+		if mi[i] != i || ms[strconv.Itoa(i)] != i || mk[K{float64(i)}] != (K{1: float64(i)}) {
+			t.Fatalf("frozen maps lost entry %d", i)
+		}
+	}
+	if len(mi) != 26 || len(ms) != 26 || len(mk) != 26 {
+		t.Fatalf("frozen maps changed length: %d, %d, %d", len(mi), len(ms), len(mk))
+	}
+
+	// Concurrent reads and iterations are allowed.
+	done := make(chan bool)
+	for g := 0; g < 4; g++ {
+		go func() {
+			for j := 0; j < 100; j++ {
+				n := 0
+				for k, v := range mi {
+					if mi[k] != v {
+						t.Errorf("mi[%d] = %d during iteration, want %d", k, mi[k], v)
+					}
+					n++
+				}
+				if n != 26 {
+					t.Errorf("iteration visited %d entries, want 26", n)
+				}
+			}
+			done <- true
+		}()
+	}
+	for g := 0; g < 4; g++ {
+		<-done
+	}
+
+	c := Clone(mi)
+	c[100] = 100 // clones are not frozen
+	if len(c) != 27 {
+		t.Errorf("len(Clone(mi)) = %d after assignment, want 27", len(c))
+	}
+
+	var nilMap map[int]int
+	Freeze(nilMap) // must not panic
+}
+
+func TestClip(t *testing.T) {
+	type K [17]float64 // > 128 bytes, stored indirectly
+	m := map[int]string{}
+	big := map[K]K{}
+	const N = 10000
+	for i := 0; i < N; i++ {
+		m[i] = strconv.Itoa(i)
+		big[K{float64(i)}] = K{1: float64(i)}
+	}
+	keep := func(i int) bool { return i%1000 == 0 }
+	DeleteFunc(m, func(k int, _ string) bool { return !keep(k) })
+	DeleteFunc(big, func(k K, _ K) bool { return !keep(int(k[0])) })
+	Clip(m)
+	Clip(big)
+	runtime.GC()
+	if len(m) != N/1000 || len(big) != N/1000 {
+		t.Fatalf("after Clip, len(m) = %d, len(big) = %d, want %d", len(m), len(big), N/1000)
+	}
+	for i := 0; i < N; i++ {
+		v, ok := m[i]
+		if ok != keep(i) || ok && v != strconv.Itoa(i) {
+			t.Errorf("after Clip, m[%d] = %q, %v", i, v, ok)
+		}
+		bv, ok := big[K{float64(i)}]
+		if ok != keep(i) || ok && bv != (K{1: float64(i)}) {
+			t.Errorf("after Clip, big[%d] = %v, %v", i, bv, ok)
+		}
+	}
+
+	var nilMap map[int]int
+	Clip(nilMap) // must not panic
+}
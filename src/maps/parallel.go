@@ -0,0 +1,96 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package maps
+
+import "iter"
+
+// parallelBatch is the smallest number of pairs worth handing to a
+// goroutine of CollectParallel.
+const parallelBatch = 1024
+
+// A pair is a key-value pair of the sequence.
+type pair[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// A part is what a goroutine of CollectParallel reports: the map of its
+// share of the pairs, or the value it panicked with.
+type part[K comparable, V any] struct {
+	m        map[K]V
+	panicked bool
+	failure  any
+}
+
+// CollectParallel is like [Collect], but it splits the pairs of seq
+// among up to workers goroutines, each of which collects its share into
+// a map of its own. The maps are then merged into one, which is grown
+// once to hold them all.
+//
+// seq is called from the calling goroutine, and its pairs are buffered
+// until it returns. Each goroutine is given consecutive pairs, at least
+// 1024 of them, so fewer goroutines are used for a short seq. As with
+// Collect, if a key appears more than once in seq, the map holds the
+// value of its last pair. If workers is less than 2, CollectParallel is
+// the same as Collect.
+//
+// Merging the maps takes about as long as adding their pairs to a map
+// one by one, so CollectParallel is only worthwhile when seq has many
+// more pairs than distinct keys.
+func CollectParallel[K comparable, V any](seq iter.Seq2[K, V], workers int) map[K]V {
+	if workers < 2 {
+		return Collect(seq)
+	}
+	var pairs []pair[K, V]
+	for k, v := range seq {
+		pairs = append(pairs, pair[K, V]{k, v})
+	}
+	workers = min(workers, (len(pairs)+parallelBatch-1)/parallelBatch)
+	if workers < 2 {
+		m := make(map[K]V)
+		for _, p := range pairs {
+			m[p.key] = p.value
+		}
+		return m
+	}
+
+	// Goroutine w collects pairs[w*len(pairs)/workers:(w+1)*len(pairs)/workers].
+	parts := make([]part[K, V], workers)
+	done := make(chan struct{}, workers)
+	for w := range workers {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			defer func() {
+				if r := recover(); r != nil {
+					parts[w].panicked, parts[w].failure = true, r
+				}
+			}()
+			share := pairs[w*len(pairs)/workers : (w+1)*len(pairs)/workers]
+			m := make(map[K]V)
+			for _, p := range share {
+				m[p.key] = p.value
+			}
+			parts[w].m = m
+		}()
+	}
+	for range workers {
+		<-done
+	}
+
+	// Merge in the order of the pairs, so that later values win.
+	n := 0
+	for _, p := range parts {
+		if p.panicked {
+			panic(p.failure)
+		}
+		n += len(p.m)
+	}
+	m := parts[0].m
+	Grow(m, n-len(m))
+	for _, p := range parts[1:] {
+		Copy(m, p.m)
+	}
+	return m
+}
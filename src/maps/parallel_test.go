@@ -0,0 +1,118 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package maps
+
+import (
+	"fmt"
+	"iter"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func testCollectParallel[K comparable, V comparable](t *testing.T, seq iter.Seq2[K, V]) {
+	t.Helper()
+	want := Collect(seq)
+	for _, workers := range []int{0, 1, 2, 3, 8} {
+		got := CollectParallel(seq, workers)
+		if len(got) != len(want) {
+			t.Errorf("workers=%d: len = %d, want %d", workers, len(got), len(want))
+		}
+		if !Equal(got, want) {
+			t.Errorf("workers=%d: map differs from Collect", workers)
+		}
+		// The map must work normally afterwards.
+		for k := range want {
+			delete(got, k)
+		}
+		if len(got) != 0 {
+			t.Errorf("workers=%d: len = %d after deleting all keys", workers, len(got))
+		}
+	}
+}
+
+func TestCollectParallel(t *testing.T) {
+	for _, n := range []int{0, 5, 100, 5000, 100000} {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			// Keys repeat, so that later values must replace earlier ones.
+			testCollectParallel(t, func(yield func(int64, int) bool) {
+				for i := range n {
+					if !yield(int64(i%(n/3+1)), i) {
+						return
+					}
+				}
+			})
+			p := new(int)
+			testCollectParallel(t, func(yield func(string, *int) bool) {
+				for i := range n {
+					if !yield(strconv.Itoa(i), p) {
+						return
+					}
+				}
+			})
+			// Large keys and values are stored out of line.
+			type big [40]int
+			testCollectParallel(t, func(yield func(big, big) bool) {
+				for i := range n / 10 {
+					if !yield(big{i}, big{1: i}) {
+						return
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestCollectParallelNaN(t *testing.T) {
+	const n = 10000
+	m := CollectParallel(func(yield func(float64, int) bool) {
+		for i := range n {
+			if !yield(math.NaN(), i) || !yield(float64(i), i) {
+				return
+			}
+		}
+	}, 4)
+	if len(m) != 2*n {
+		t.Errorf("len = %d, want %d", len(m), 2*n)
+	}
+}
+
+func TestCollectParallelPanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("CollectParallel with an unhashable key did not panic")
+		}
+	}()
+	CollectParallel(func(yield func(any, int) bool) {
+		for i := range 5000 {
+			var k any = i
+			if i == 3000 {
+				k = []int{i}
+			}
+			if !yield(k, i) {
+				return
+			}
+		}
+	}, 4)
+}
+
+func BenchmarkCollectParallel(b *testing.B) {
+	const n = 1000000
+	seq := func(yield func(int, int) bool) {
+		for i := range n {
+			if !yield(i*0x9e3779b9, i) {
+				return
+			}
+		}
+	}
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprint(workers), func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				CollectParallel(seq, workers)
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"internal/goarch"
 	"internal/testenv"
 	"io"
+	"maps"
 	"math"
 	"math/rand"
 	"net"
@@ -8527,6 +8528,39 @@ func TestClear(t *testing.T) {
 	}
 }
 
+func TestMapGrow(t *testing.T) {
+	m := map[int]int{1: 1}
+	v := ValueOf(m)
+	v.MapGrow(1000)
+	if c := maps.Cap(m); c < 1001 {
+		t.Errorf("after MapGrow(1000), Cap = %d, want >= 1001", c)
+	}
+	c := maps.Cap(m)
+	for i := range 1000 {
+		v.SetMapIndex(ValueOf(i+2), ValueOf(i))
+	}
+	if got := maps.Cap(m); got != c {
+		t.Errorf("map grew from capacity %d to %d after MapGrow", c, got)
+	}
+	if len(m) != 1001 || m[1] != 1 {
+		t.Errorf("map contents changed by MapGrow")
+	}
+
+	var nilMap map[string]int
+	p := ValueOf(&nilMap).Elem()
+	p.MapGrow(100)
+	if nilMap == nil || len(nilMap) != 0 || maps.Cap(nilMap) < 100 {
+		t.Errorf("MapGrow(100) on nil map: got map with len %d, Cap %d, want len 0, Cap >= 100", len(nilMap), maps.Cap(nilMap))
+	}
+
+	shouldPanic("reflect.Value.MapGrow: negative size", func() { v.MapGrow(-1) })
+	shouldPanic("unaddressable value", func() { ValueOf(map[int]int(nil)).MapGrow(1) })
+	shouldPanic("reflect: call of reflect.Value.MapGrow on slice Value", func() { ValueOf([]int{}).MapGrow(1) })
+	shouldPanic("unexported field", func() {
+		ValueOf(struct{ m map[int]int }{m}).Field(0).MapGrow(1)
+	})
+}
+
 func TestValuePointerAndUnsafePointer(t *testing.T) {
 	ptr := new(int)
 	ch := make(chan int)
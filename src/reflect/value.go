@@ -2426,6 +2426,28 @@ func (v Value) SetCap(n int) {
 	s.Cap = n
 }
 
+// MapGrow increases the capacity of the map v, if necessary, to guarantee
+// space for another n key/value pairs. After MapGrow(n), at least n pairs
+// can be added with [Value.SetMapIndex] without the map growing again.
+// If v holds a nil map, MapGrow sets v to a new map with room for n pairs,
+// as made by [MakeMapWithSize], and v must be settable.
+//
+// It panics if v's Kind is not [Map] or if n is negative or too large to
+// allocate the memory.
+func (v Value) MapGrow(n int) {
+	v.mustBe(Map)
+	v.mustBeExported()
+	if n < 0 {
+		panic("reflect.Value.MapGrow: negative size")
+	}
+	if v.IsNil() {
+		v.mustBeAssignable()
+		v.Set(MakeMapWithSize(v.Type(), n))
+		return
+	}
+	mapgrow(v.typ(), v.pointer(), n)
+}
+
 // SetMapIndex sets the element associated with key in the map v to elem.
 // It panics if v's Kind is not [Map].
 // If elem is the zero Value, SetMapIndex deletes the key from the map.
@@ -3921,6 +3943,8 @@ func maplen(m unsafe.Pointer) int
 
 func mapclear(t *abi.Type, m unsafe.Pointer)
 
+func mapgrow(t *abi.Type, m unsafe.Pointer, n int)
+
 // call calls fn with "stackArgsSize" bytes of stack arguments laid out
 // at stackArgs and register arguments laid out in regArgs. frameSize is
 // the total amount of stack space that will be reserved by call, so this
@@ -6,6 +6,7 @@ package runtime
 
 import (
 	"internal/abi"
+	"internal/bytealg"
 	"internal/cpu"
 	"internal/goarch"
 	"unsafe"
@@ -465,7 +466,57 @@ var aeskeysched [hashRandomBytes]byte
 // used in hash{32,64}.go to seed the hash function
 var hashkey [4]uintptr
 
-func alginit() {
+// mapSeed is the value of the GODEBUG setting mapseed, or 0 if it is
+// not set. When it is set, the hash keys are derived from it instead of
+// being chosen at random, and every map uses it as its hash seed, so
+// that the hashes of map keys are the same in every run.
+var mapSeed uint64
+
+// hashKeyState is the state of the generator of hash keys from mapSeed.
+var hashKeyState uint64
+
+// hashKeyRand returns a value for initializing the hash keys.
+func hashKeyRand() uint64 {
+	if mapSeed == 0 {
+		return bootstrapRand()
+	}
+	// splitmix64.
+	hashKeyState += 0x9e3779b97f4a7c15
+	z := hashKeyState
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// parseMapSeed returns the value of mapseed in the GODEBUG string env,
+// or 0 if it is not set. As with other settings, the last one wins.
+//
+// It runs before maps can be used, so it cannot use parsegodebug.
+func parseMapSeed(env string) uint64 {
+	const prefix = "mapseed="
+	seed := uint64(0)
+	for env != "" {
+		field := env
+		if i := bytealg.IndexByteString(env, ','); i >= 0 {
+			field, env = env[:i], env[i+1:]
+		} else {
+			env = ""
+		}
+		if len(field) > len(prefix) && field[:len(prefix)] == prefix {
+			if n, ok := atoi64(field[len(prefix):]); ok && n >= 0 {
+				seed = uint64(n)
+			}
+		}
+	}
+	return seed
+}
+
+// alginit initializes the hash functions. godebug is the value of the
+// GODEBUG environment variable, if known this early.
+func alginit(godebug string) {
+	mapSeed = parseMapSeed(godebug)
+	hashKeyState = mapSeed
+
 	// Install AES hash algorithms if the instructions needed are present.
 	if (GOARCH == "386" || GOARCH == "amd64") &&
 		cpu.X86.HasAES && // AESENC
@@ -479,7 +530,7 @@ func alginit() {
 		return
 	}
 	for i := range hashkey {
-		hashkey[i] = uintptr(bootstrapRand())
+		hashkey[i] = uintptr(hashKeyRand())
 	}
 }
 
@@ -488,7 +539,7 @@ func initAlgAES() {
 	// Initialize with random data so hash collisions will be hard to engineer.
 	key := (*[hashRandomBytes / 8]uint64)(unsafe.Pointer(&aeskeysched))
 	for i := range key {
-		key[i] = bootstrapRand()
+		key[i] = hashKeyRand()
 	}
 }
 
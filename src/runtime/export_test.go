@@ -488,11 +488,59 @@ func MapBucketsCount(m map[int]int) int {
 	return 1 << h.B
 }
 
+// MapBucketSize returns the size of a bucket of maps of the same type as m.
+func MapBucketSize(m any) uintptr {
+	return uintptr((*maptype)(unsafe.Pointer(efaceOf(&m)._type)).BucketSize)
+}
+
+func MapHashSeed(m map[int]int) uint32 {
+	h := *(**hmap)(unsafe.Pointer(&m))
+	return h.hash0
+}
+
+func MapBuckets(m map[int]int) uintptr {
+	h := *(**hmap)(unsafe.Pointer(&m))
+	return uintptr(h.buckets)
+}
+
 func MapBucketsPointerIsNil(m map[int]int) bool {
 	h := *(**hmap)(unsafe.Pointer(&m))
 	return h.buckets == nil
 }
 
+// MapCheck verifies the internal structure of the map m.
+func MapCheck(m any) {
+	e := efaceOf(&m)
+	checkmap((*maptype)(unsafe.Pointer(e._type)), (*hmap)(e.data))
+}
+
+var SetMapOrder = testing_setMapOrder
+
+// MapHash returns the hash that the map m computes for *key.
+func MapHash(m any, key unsafe.Pointer) uintptr {
+	e := efaceOf(&m)
+	t := (*maptype)(unsafe.Pointer(e._type))
+	h := (*hmap)(e.data)
+	return t.Hasher(key, uintptr(h.hash0))
+}
+
+// MapLongestChain returns the largest number of overflow buckets
+// attached to a bucket of the map m.
+func MapLongestChain(m any) int {
+	e := efaceOf(&m)
+	t := (*maptype)(unsafe.Pointer(e._type))
+	h := (*hmap)(e.data)
+	longest := 0
+	for i := uintptr(0); i < bucketShift(h.B); i++ {
+		n := 0
+		for b := (*bmap)(add(h.buckets, i*uintptr(t.BucketSize))).overflow(t); b != nil; b = b.overflow(t) {
+			n++
+		}
+		longest = max(longest, n)
+	}
+	return longest
+}
+
 func OverLoadFactor(count int, B uint8) bool {
 	return overLoadFactor(count, B)
 }
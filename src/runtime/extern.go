@@ -138,6 +138,78 @@ It is a comma-separated list of name=val pairs setting these named variables:
 	of MADV_FREE. This is less efficient, but causes RSS numbers to drop
 	more quickly.
 
+	mapcheck: setting mapcheck=1 makes the runtime check the internal
+	structure of each map when it starts or finishes growing, and crash
+	with a description of the problem if the map is corrupt. This is
+	useful for tracking down memory corruption, for example from misuse
+	of cgo or unsafe. The checks take time proportional to the size of
+	the map, so they slow down programs that build large maps.
+
+	mapcheckiter: setting mapcheckiter=1 makes a range loop over a map
+	panic if the goroutine running it grows the map by adding keys,
+	deletes a key other than the one the loop has just produced, or
+	clears or resizes the map (for example with maps.Grow). The language
+	allows these, but the loop may or may not see the keys involved, which
+	often hides bugs. The check is made when the loop moves to its next
+	key, so a loop that stops right after modifying the map does not panic.
+	Modifications by other goroutines are not detected.
+
+	mapclearshrink: clearing a map with more than 2^N buckets, where N is
+	the value of mapclearshrink, that holds less than 1/16 of the entries
+	its buckets have room for, releases the buckets instead of zeroing
+	them. The map is given buckets for as many entries as it held when
+	cleared. A bucket holds up to 8 entries. The default is
+	mapclearshrink=10. Setting mapclearshrink=0 makes clear keep the
+	buckets of all maps.
+
+	maphugepage: setting maphugepage=1 asks the operating system to back
+	the buckets of large maps with huge pages, where it supports them (on
+	Linux, with madvise(MADV_HUGEPAGE)). This can reduce TLB misses for
+	random accesses to maps of many gigabytes, at the cost of memory that
+	the operating system may not be able to return in smaller pieces. The
+	advice stays in effect for the memory after the map is freed.
+
+	maploadfactor: setting maploadfactor=N, with N between 1 and 16, makes
+	maps grow when their buckets are on average N/16 full, instead of the
+	default 13/16. Lower values use more memory for shorter lookups, and
+	higher values use less memory at the cost of more overflow buckets.
+	The setting only takes effect at program start.
+
+	mapseed: setting mapseed=N, with N a positive integer, derives the keys of
+	the hash functions used by maps from N instead of choosing them at
+	random when the program starts, and gives every map the same hash seed.
+	The hashes of map keys are then the same in every run with the same N,
+	which helps when reproducing crashes or fuzzing a program. It does not
+	fix the order of map iteration, which stays random, except in fuzz
+	tests, where the testing package derives it from each input. This setting
+	removes the protection against hash flooding attacks, in which an
+	attacker chooses keys that collide to make map operations slow, and
+	must only be used for debugging. It also fixes the results of
+	hash/maphash functions for a given seed. It is only supported on
+	Unix-like systems and only takes effect at program start.
+
+	mapprefault: setting mapprefault=1 makes the runtime touch every page
+	of the buckets it allocates for a map, when it allocates them, so that
+	the first accesses to a large map created with a size hint or grown
+	with maps.Grow do not take page faults. This moves the cost of the
+	faults, and of the memory they commit, to the point of allocation.
+
+	mapreuse: setting mapreuse=1 makes a map keep the bucket array it
+	used before a growth, once the growth is done, and reuse it for the
+	next bucket array of the same size instead of allocating one. This
+	chiefly helps maps that are rehashed in place over and over because
+	keys are continually deleted and inserted, which then allocate no new
+	bucket arrays, at the cost of keeping a second bucket array alive.
+	An array is not kept if an iteration over the map may still use it.
+
+	maptrace: setting maptrace=1 causes the runtime to print a line to
+	standard error each time a map allocates a new bucket array or drops
+	its buckets: when it grows, is rehashed in place because it has too
+	many overflow buckets, shrinks, or is cleared. The line gives the
+	map type, its length, the old and new number of buckets and the size
+	of the new bucket array in bytes. Setting maptrace=2 also prints the
+	stack of the goroutine that caused the change.
+
 	memprofilerate: setting memprofilerate=X will update the value of runtime.MemProfileRate.
 	When set to 0 memory profiling is disabled.  Refer to the description of
 	MemProfileRate for the default value.
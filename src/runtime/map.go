@@ -68,8 +68,18 @@ const (
 	// Maximum average load of a bucket that triggers growth is bucketCnt*13/16 (about 80% full)
 	// Because of minimum alignment rules, bucketCnt is known to be at least 8.
 	// Represent as loadFactorNum/loadFactorDen, to allow integer math.
-	loadFactorDen = 2
-	loadFactorNum = loadFactorDen * abi.MapBucketCount * 13 / 16
+	loadFactorDen        = 2
+	defaultLoadFactorNum = loadFactorDen * abi.MapBucketCount * 13 / 16
+
+	// Maps with at least 1<<minShrinkB buckets are shrunk by deletions
+	// once they hold less than 1/shrinkFactor of their maximum load.
+	minShrinkB   = 4
+	shrinkFactor = 16
+
+	// A chain of at least reseedChain overflow buckets found by mapassign
+	// makes it rehash the map with a new hash seed; see reseedmap.
+	// With a good hash function, a bucket is practically never that full.
+	reseedChain = 8
 
 	// data offset should be the size of the bmap struct, but needs to be
 	// aligned correctly. For amd64p32 this means 64-bit alignment
@@ -91,15 +101,30 @@ const (
 	minTopHash     = 5 // minimum tophash for a normal filled cell.
 
 	// flags
-	iterator     = 1 // there may be an iterator using buckets
-	oldIterator  = 2 // there may be an iterator using oldbuckets
-	hashWriting  = 4 // a goroutine is writing to the map
-	sameSizeGrow = 8 // the current map growth is to a new map of the same size
+	iterator     = 1  // there may be an iterator using buckets
+	oldIterator  = 2  // there may be an iterator using oldbuckets
+	hashWriting  = 4  // a goroutine is writing to the map
+	sameSizeGrow = 8  // the current map growth is to a new map of the same size
+	frozen       = 16 // the map is read-only; see maps.Freeze
+	reseeded     = 32 // the map has been given a new hash seed by reseedmap
 
 	// sentinel bucket ID for iterator checks
 	noCheck = 1<<(8*goarch.PtrSize) - 1
 )
 
+// loadFactorNum is the numerator of the maximum average load of a bucket.
+// It can be set with GODEBUG=maploadfactor=N, in which case the maximum
+// load is bucketCnt*N/16; see setMapLoadFactor.
+var loadFactorNum uintptr = defaultLoadFactorNum
+
+// setMapLoadFactor applies the maploadfactor GODEBUG setting.
+// Values outside of [1, 16] are ignored.
+func setMapLoadFactor(n int32) {
+	if 1 <= n && n <= 16 {
+		loadFactorNum = loadFactorDen * abi.MapBucketCount * uintptr(n) / 16
+	}
+}
+
 // isEmpty reports whether the given tophash array entry represents an empty bucket entry.
 func isEmpty(x uint8) bool {
 	return x <= emptyOne
@@ -120,6 +145,8 @@ type hmap struct {
 	nevacuate  uintptr        // progress counter for evacuation (buckets less than this have been evacuated)
 
 	extra *mapextra // optional fields
+
+	writer uint64 // goid of the last goroutine to set hashWriting, for mapfatal
 }
 
 // mapextra holds fields that are not present on all maps.
@@ -137,6 +164,12 @@ type mapextra struct {
 
 	// nextOverflow holds a pointer to a free overflow bucket.
 	nextOverflow *bmap
+
+	// spare is a bucket array of 1<<spareB buckets that the map no
+	// longer uses, kept for reuse by the next bucket array the map
+	// allocates. See the mapreuse GODEBUG setting.
+	spare  unsafe.Pointer
+	spareB uint8
 }
 
 // A bucket for a Go map.
@@ -149,6 +182,9 @@ type bmap struct {
 	// NOTE: packing all the keys together and then all the elems together makes the
 	// code a bit more complicated than alternating key/elem/key/elem/... but it allows
 	// us to eliminate padding which would be needed for, e.g., map[int64]int8.
+	// It also means that elems of size zero, as in a map[K]struct{} used as
+	// a set, take no space at all: the bucket holds only tophash, keys and
+	// the overflow pointer, and no elem is ever copied.
 	// Followed by an overflow pointer.
 }
 
@@ -230,8 +266,8 @@ func (h *hmap) incrnoverflow() {
 	// as many overflow buckets as buckets.
 	mask := uint32(1)<<(h.B-15) - 1
 	// Example: if h.B == 18, then mask == 7,
-	// and rand() & 7 == 0 with probability 1/8.
-	if uint32(rand())&mask == 0 {
+	// and maprand() & 7 == 0 with probability 1/8.
+	if uint32(maprand())&mask == 0 {
 		h.noverflow++
 	}
 }
@@ -273,6 +309,42 @@ func (h *hmap) createOverflow() {
 	}
 }
 
+// newelem allocates out-of-line storage for an element of a map of type t.
+// Every caller overwrites the whole element before the map is next
+// read (the compiler evaluates the right-hand side of m[k] = v before
+// calling mapassign), so memory for pointer-free elements is not zeroed.
+// Elements with pointers must still be zeroed so that the garbage
+// collector never sees stale pointers.
+func newelem(t *maptype) unsafe.Pointer {
+	return mallocgc(t.Elem.Size_, t.Elem, t.Elem.Pointers())
+}
+
+// keepSpare records buckets, an array of 1<<b buckets that h no longer
+// uses, for reuse by the next bucket array h allocates.
+// No iterator may be walking buckets.
+func (h *hmap) keepSpare(buckets unsafe.Pointer, b uint8) {
+	if h.extra == nil {
+		h.extra = new(mapextra)
+	}
+	h.extra.spare = buckets
+	h.extra.spareB = b
+}
+
+// takeSpare returns the spare bucket array of h if it has 1<<b buckets,
+// and nil otherwise. Either way h no longer keeps a spare array, so
+// that an array of the wrong size is not kept indefinitely.
+func (h *hmap) takeSpare(b uint8) unsafe.Pointer {
+	if h.extra == nil || h.extra.spare == nil {
+		return nil
+	}
+	buckets := h.extra.spare
+	h.extra.spare = nil
+	if h.extra.spareB != b {
+		return nil
+	}
+	return buckets
+}
+
 func makemap64(t *maptype, hint int64, h *hmap) *hmap {
 	if int64(int(hint)) != hint {
 		hint = 0
@@ -280,6 +352,56 @@ func makemap64(t *maptype, hint int64, h *hmap) *hmap {
 	return makemap(t, int(hint), h)
 }
 
+// newHashSeed returns the hash seed for a new map, or for a map that
+// has been emptied. See mapSeed.
+//
+//go:nosplit
+func newHashSeed() uint32 {
+	if mapSeed != 0 {
+		return uint32(mapSeed)
+	}
+	return uint32(rand())
+}
+
+// mapOrder holds the state of the generator that maprand uses instead
+// of rand after a call to testing_setMapOrder.
+var mapOrder struct {
+	pinned atomic.Bool
+	state  atomic.Uint64
+}
+
+// maprand returns a random number for the choices that decide the order
+// in which maps are iterated over: where an iteration starts, and when
+// overflow buckets are counted for large maps. It normally returns rand(),
+// but package testing can make it a deterministic sequence; see
+// testing_setMapOrder.
+func maprand() uint64 {
+	if !mapOrder.pinned.Load() {
+		return rand()
+	}
+	x := mapOrder.state.Add(1) * 0xa0761d6478bd642f
+	hi, lo := math.Mul64(x, x^0xe7037ed1a0b428db)
+	return hi ^ lo
+}
+
+// testing_setMapOrder is for package testing, which calls it before
+// running a fuzz target on an input. If GODEBUG=mapseed is set, so that
+// maps hash keys the same way in every run, it makes maprand a sequence
+// determined by seed and reports true. Map iteration order then only
+// depends on seed and on the map operations done since the call, as long
+// as the keys do not hash pointers. Otherwise testing_setMapOrder does
+// nothing and reports false.
+//
+//go:linkname testing_setMapOrder testing.setMapOrder
+func testing_setMapOrder(seed uint64) bool {
+	if mapSeed == 0 {
+		return false
+	}
+	mapOrder.state.Store(seed)
+	mapOrder.pinned.Store(true)
+	return true
+}
+
 // makemap_small implements Go map creation for make(map[k]v) and
 // make(map[k]v, hint) when hint is known to be at most bucketCnt
 // at compile time and the map needs to be allocated on the heap.
@@ -295,7 +417,7 @@ func makemap64(t *maptype, hint int64, h *hmap) *hmap {
 //go:linkname makemap_small
 func makemap_small() *hmap {
 	h := new(hmap)
-	h.hash0 = uint32(rand())
+	h.hash0 = newHashSeed()
 	return h
 }
 
@@ -316,16 +438,11 @@ func makemap_small() *hmap {
 //
 //go:linkname makemap
 func makemap(t *maptype, hint int, h *hmap) *hmap {
-	mem, overflow := math.MulUintptr(uintptr(hint), t.Bucket.Size_)
-	if overflow || mem > maxAlloc {
-		hint = 0
-	}
-
 	// initialize Hmap
 	if h == nil {
 		h = new(hmap)
 	}
-	h.hash0 = uint32(rand())
+	h.hash0 = newHashSeed()
 
 	// Find the size parameter B which will hold the requested # of elements.
 	// For hint < 0 overLoadFactor returns false since hint < bucketCnt.
@@ -333,6 +450,14 @@ func makemap(t *maptype, hint int, h *hmap) *hmap {
 	for overLoadFactor(hint, B) {
 		B++
 	}
+	// Honor the hint whenever the bucket array it needs can be allocated,
+	// so that filling a map made with a large hint never grows it.
+	// Checking the array rather than hint*bucket size matters on 32-bit
+	// systems, where the latter overflows long before the former.
+	mem, overflow := math.MulUintptr(bucketShift(B), t.Bucket.Size_)
+	if overflow || mem > maxAlloc {
+		B = 0
+	}
 	h.B = B
 
 	// allocate initial hash table
@@ -358,23 +483,18 @@ func makemap(t *maptype, hint int, h *hmap) *hmap {
 // otherwise dirtyalloc will be cleared and reused as backing array.
 func makeBucketArray(t *maptype, b uint8, dirtyalloc unsafe.Pointer) (buckets unsafe.Pointer, nextOverflow *bmap) {
 	base := bucketShift(b)
-	nbuckets := base
-	// For small b, overflow buckets are unlikely.
-	// Avoid the overhead of the calculation.
-	if b >= 4 {
-		// Add on the estimated number of overflow buckets
-		// required to insert the median number of elements
-		// used with this value of b.
-		nbuckets += bucketShift(b - 4)
-		sz := t.Bucket.Size_ * nbuckets
-		up := roundupsize(sz, !t.Bucket.Pointers())
-		if up != sz {
-			nbuckets = up / t.Bucket.Size_
-		}
-	}
+	nbuckets := bucketArrayLen(t, b)
 
 	if dirtyalloc == nil {
 		buckets = newarray(t.Bucket, int(nbuckets))
+		if debug.maphugepage != 0 {
+			// sysHugePage only advises the huge pages that lie
+			// entirely within the buckets.
+			sysHugePage(buckets, t.Bucket.Size_*nbuckets)
+		}
+		if debug.mapprefault != 0 {
+			prefault(buckets, t.Bucket.Size_*nbuckets)
+		}
 	} else {
 		// dirtyalloc was previously generated by
 		// the above newarray(t.Bucket, int(nbuckets))
@@ -401,6 +521,20 @@ func makeBucketArray(t *maptype, b uint8, dirtyalloc unsafe.Pointer) (buckets un
 	return buckets, nextOverflow
 }
 
+// prefault writes to each page of the newly allocated, zeroed memory
+// [p, p+n), so that the OS backs it with physical memory now rather than
+// when the map first uses it. See the mapprefault GODEBUG setting.
+func prefault(p unsafe.Pointer, n uintptr) {
+	if n < physPageSize {
+		return
+	}
+	for off := uintptr(0); off < n; off += physPageSize {
+		// Storing zero leaves the memory unchanged, including for
+		// a concurrent GC that may scan it.
+		*(*uint8)(add(p, off)) = 0
+	}
+}
+
 // mapaccess1 returns a pointer to h[key].  Never returns nil, instead
 // it will return a reference to the zero object for the elem type if
 // the key is not in the map.
@@ -426,7 +560,7 @@ func mapaccess1(t *maptype, h *hmap, key unsafe.Pointer) unsafe.Pointer {
 		return unsafe.Pointer(&zeroVal[0])
 	}
 	if h.flags&hashWriting != 0 {
-		fatal("concurrent map read and map write")
+		mapfatal(t, h, "concurrent map read and map write")
 	}
 	hash := t.Hasher(key, uintptr(h.hash0))
 	m := bucketMask(h.B)
@@ -496,7 +630,7 @@ func mapaccess2(t *maptype, h *hmap, key unsafe.Pointer) (unsafe.Pointer, bool)
 		return unsafe.Pointer(&zeroVal[0]), false
 	}
 	if h.flags&hashWriting != 0 {
-		fatal("concurrent map read and map write")
+		mapfatal(t, h, "concurrent map read and map write")
 	}
 	hash := t.Hasher(key, uintptr(h.hash0))
 	m := bucketMask(h.B)
@@ -628,14 +762,22 @@ func mapassign(t *maptype, h *hmap, key unsafe.Pointer) unsafe.Pointer {
 	if asanenabled {
 		asanread(key, t.Key.Size_)
 	}
-	if h.flags&hashWriting != 0 {
-		fatal("concurrent map writes")
+	elem, _ := assignkey(t, h, key)
+	return elem
+}
+
+// assignkey implements mapassign. It also reports whether key was
+// already present.
+func assignkey(t *maptype, h *hmap, key unsafe.Pointer) (elem unsafe.Pointer, found bool) {
+	if h.flags&(hashWriting|frozen) != 0 {
+		mapwriteerror(t, h)
 	}
 	hash := t.Hasher(key, uintptr(h.hash0))
 
 	// Set hashWriting after calling t.hasher, since t.hasher may panic,
 	// in which case we have not actually done a write.
 	h.flags ^= hashWriting
+	h.writer = getg().goid
 
 	if h.buckets == nil {
 		h.buckets = newobject(t.Bucket) // newarray(t.Bucket, 1)
@@ -651,7 +793,7 @@ again:
 
 	var inserti *uint8
 	var insertk unsafe.Pointer
-	var elem unsafe.Pointer
+	chain := 0 // number of overflow buckets searched
 bucketloop:
 	for {
 		for i := uintptr(0); i < abi.MapBucketCount; i++ {
@@ -678,6 +820,7 @@ bucketloop:
 				typedmemmove(t.Key, k, key)
 			}
 			elem = add(unsafe.Pointer(b), dataOffset+abi.MapBucketCount*uintptr(t.KeySize)+i*uintptr(t.ValueSize))
+			found = true
 			goto done
 		}
 		ovf := b.overflow(t)
@@ -685,6 +828,7 @@ bucketloop:
 			break
 		}
 		b = ovf
+		chain++
 	}
 
 	// Did not find mapping for key. Allocate new cell & add entry.
@@ -696,6 +840,14 @@ bucketloop:
 		goto again // Growing the table invalidates everything, so try again
 	}
 
+	// If the keys in this bucket likely collide on purpose, rehash them
+	// with a new seed.
+	if chain >= reseedChain && canReseed(h) {
+		reseedmap(t, h)
+		hash = t.Hasher(key, uintptr(h.hash0))
+		goto again
+	}
+
 	if inserti == nil {
 		// The current bucket and all the overflow buckets connected to it are full, allocate a new one.
 		newb := h.newoverflow(t, b)
@@ -711,7 +863,7 @@ bucketloop:
 		insertk = kmem
 	}
 	if t.IndirectElem() {
-		vmem := newobject(t.Elem)
+		vmem := newelem(t)
 		*(*unsafe.Pointer)(elem) = vmem
 	}
 	typedmemmove(t.Key, insertk, key)
@@ -720,13 +872,13 @@ bucketloop:
 
 done:
 	if h.flags&hashWriting == 0 {
-		fatal("concurrent map writes")
+		mapfatal(t, h, "concurrent map writes")
 	}
 	h.flags &^= hashWriting
 	if t.IndirectElem() {
 		elem = *((*unsafe.Pointer)(elem))
 	}
-	return elem
+	return elem, found
 }
 
 // mapdelete should be an internal detail,
@@ -751,14 +903,20 @@ func mapdelete(t *maptype, h *hmap, key unsafe.Pointer) {
 	if asanenabled && h != nil {
 		asanread(key, t.Key.Size_)
 	}
+	deletekey(t, h, key, nil)
+}
+
+// deletekey implements mapdelete. If elem is not nil and key is present,
+// it copies the deleted element to elem. It reports whether key was present.
+func deletekey(t *maptype, h *hmap, key, elem unsafe.Pointer) (found bool) {
 	if h == nil || h.count == 0 {
 		if err := mapKeyError(t, key); err != nil {
 			panic(err) // see issue 23734
 		}
-		return
+		return false
 	}
-	if h.flags&hashWriting != 0 {
-		fatal("concurrent map writes")
+	if h.flags&(hashWriting|frozen) != 0 {
+		mapwriteerror(t, h)
 	}
 
 	hash := t.Hasher(key, uintptr(h.hash0))
@@ -766,6 +924,7 @@ func mapdelete(t *maptype, h *hmap, key unsafe.Pointer) {
 	// Set hashWriting after calling t.hasher, since t.hasher may panic,
 	// in which case we have not actually done a write (delete).
 	h.flags ^= hashWriting
+	h.writer = getg().goid
 
 	bucket := hash & bucketMask(h.B)
 	if h.growing() {
@@ -798,6 +957,14 @@ search:
 				memclrHasPointers(k, t.Key.Size_)
 			}
 			e := add(unsafe.Pointer(b), dataOffset+abi.MapBucketCount*uintptr(t.KeySize)+i*uintptr(t.ValueSize))
+			if elem != nil {
+				e2 := e
+				if t.IndirectElem() {
+					e2 = *((*unsafe.Pointer)(e2))
+				}
+				typedmemmove(t.Elem, elem, e2)
+			}
+			found = true
 			if t.IndirectElem() {
 				*(*unsafe.Pointer)(e) = nil
 			} else if t.Elem.Pointers() {
@@ -839,19 +1006,24 @@ search:
 			}
 		notLast:
 			h.count--
+			if debug.mapcheckiter != 0 {
+				mapcheckiterModify(h, true, hash, "a key other than the one just produced was deleted")
+			}
 			// Reset the hash seed to make it more difficult for attackers to
 			// repeatedly trigger hash collisions. See issue 25237.
 			if h.count == 0 {
-				h.hash0 = uint32(rand())
+				h.hash0 = newHashSeed()
 			}
+			shrinkIfSparse(t, h)
 			break search
 		}
 	}
 
 	if h.flags&hashWriting == 0 {
-		fatal("concurrent map writes")
+		mapfatal(t, h, "concurrent map writes")
 	}
 	h.flags &^= hashWriting
+	return found
 }
 
 // mapiterinit initializes the hiter struct used for ranging over maps.
@@ -904,7 +1076,7 @@ func mapiterinit(t *maptype, h *hmap, it *hiter) {
 	}
 
 	// decide where to start
-	r := uintptr(rand())
+	r := uintptr(maprand())
 	it.startBucket = r & bucketMask(h.B)
 	it.offset = uint8(r >> h.B & (abi.MapBucketCount - 1))
 
@@ -917,6 +1089,9 @@ func mapiterinit(t *maptype, h *hmap, it *hiter) {
 		atomic.Or8(&h.flags, iterator|oldIterator)
 	}
 
+	if debug.mapcheckiter != 0 {
+		mapcheckiterStart(it)
+	}
 	mapiternext(it)
 }
 
@@ -940,10 +1115,13 @@ func mapiternext(it *hiter) {
 		callerpc := getcallerpc()
 		racereadpc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapiternext))
 	}
+	t := it.t
 	if h.flags&hashWriting != 0 {
-		fatal("concurrent map iteration and map write")
+		mapfatal(t, h, "concurrent map iteration and map write")
+	}
+	if debug.mapcheckiter != 0 {
+		mapcheckiterNext(it)
 	}
-	t := it.t
 	bucket := it.bucket
 	b := it.bptr
 	i := it.i
@@ -957,7 +1135,7 @@ next:
 			it.elem = nil
 			return
 		}
-		if h.growing() && it.B == h.B {
+		if h.growing() && it.buckets == h.buckets {
 			// Iterator was started in the middle of a grow, and the grow isn't done yet.
 			// If the bucket we're looking at hasn't been filled in yet (i.e. the old
 			// bucket hasn't been evacuated) then we need to iterate through the old
@@ -1053,6 +1231,9 @@ next:
 		}
 		it.i = i + 1
 		it.checkBucket = checkBucket
+		if debug.mapcheckiter != 0 {
+			mapcheckiterKey(it)
+		}
 		return
 	}
 	b = b.overflow(t)
@@ -1060,6 +1241,115 @@ next:
 	goto next
 }
 
+// A mapIterCheck records, for GODEBUG=mapcheckiter, an iteration over a
+// map that a goroutine has started, and whether the map was modified
+// since the iteration last produced a key.
+//
+// Iterations are identified by their map and starting point rather than
+// by the address of their hiter, which moves when the stack grows. The
+// records hold no pointers, so that they keep nothing alive and need
+// not be updated when a stack moves.
+type mapIterCheck struct {
+	h           uintptr // the map, or 0 if the record is unused
+	buckets     uintptr // it.buckets when the iteration started
+	startBucket uintptr
+	offset      uint8
+	hasKey      bool    // whether the iteration has produced a key
+	hash        uintptr // the hash of the key the iteration last produced
+	modified    string  // if not "", describes how the map was modified
+}
+
+// mapIterChecks holds the records of a goroutine's map iterations.
+// A range loop that is left early never finishes its iteration, so
+// the records are reused in turn rather than released.
+type mapIterChecks struct {
+	iters [8]mapIterCheck
+	next  int // index of the record to use for the next iteration
+}
+
+// find returns the record of it, or nil.
+func (c *mapIterChecks) find(it *hiter) *mapIterCheck {
+	for i := range c.iters {
+		r := &c.iters[i]
+		if r.h == uintptr(unsafe.Pointer(it.h)) && r.buckets == uintptr(it.buckets) &&
+			r.startBucket == it.startBucket && r.offset == it.offset {
+			return r
+		}
+	}
+	return nil
+}
+
+// mapcheckiterStart records, for GODEBUG=mapcheckiter, that the current
+// goroutine is starting the iteration it.
+func mapcheckiterStart(it *hiter) {
+	gp := getg()
+	if gp.mapIters == nil {
+		gp.mapIters = new(mapIterChecks)
+	}
+	c := gp.mapIters
+	r := c.find(it)
+	if r == nil {
+		r = &c.iters[c.next]
+		c.next = (c.next + 1) % len(c.iters)
+	}
+	*r = mapIterCheck{
+		h:           uintptr(unsafe.Pointer(it.h)),
+		buckets:     uintptr(it.buckets),
+		startBucket: it.startBucket,
+		offset:      it.offset,
+	}
+}
+
+// mapcheckiterNext panics, for GODEBUG=mapcheckiter, if the current
+// goroutine modified the map of it since it last produced a key.
+func mapcheckiterNext(it *hiter) {
+	c := getg().mapIters
+	if c == nil {
+		return
+	}
+	if r := c.find(it); r != nil && r.modified != "" {
+		what := r.modified
+		r.modified = ""
+		panic(plainError("map modified during iteration: " + what))
+	}
+}
+
+// mapcheckiterKey records the key that it has just produced.
+func mapcheckiterKey(it *hiter) {
+	c := getg().mapIters
+	if c == nil {
+		return
+	}
+	if r := c.find(it); r != nil {
+		r.hasKey = true
+		r.hash = 0
+		if it.t.ReflexiveKey() || it.t.Key.Equal(it.key, it.key) {
+			r.hash = it.t.Hasher(it.key, uintptr(it.h.hash0))
+		}
+	}
+}
+
+// mapcheckiterModify records, for GODEBUG=mapcheckiter, that the current
+// goroutine modified h in the way described by what. If deleted is true,
+// the modification is the deletion of a key with the given hash, which is
+// allowed for the key that an iteration has just produced.
+func mapcheckiterModify(h *hmap, deleted bool, hash uintptr, what string) {
+	c := getg().mapIters
+	if c == nil {
+		return
+	}
+	for i := range c.iters {
+		r := &c.iters[i]
+		if r.h != uintptr(unsafe.Pointer(h)) || r.modified != "" {
+			continue
+		}
+		if deleted && r.hasKey && r.hash == hash {
+			continue
+		}
+		r.modified = what
+	}
+}
+
 // mapclear deletes all keys from a map.
 // It is called by the compiler.
 //
@@ -1083,26 +1373,52 @@ func mapclear(t *maptype, h *hmap) {
 		return
 	}
 
-	if h.flags&hashWriting != 0 {
-		fatal("concurrent map writes")
+	if h.flags&(hashWriting|frozen) != 0 {
+		mapwriteerror(t, h)
 	}
 
 	h.flags ^= hashWriting
+	h.writer = getg().goid
+
+	if debug.mapcheckiter != 0 {
+		mapcheckiterModify(h, false, 0, "the map was cleared")
+	}
+
+	// Rather than clearing the buckets of a large map that holds far
+	// fewer entries than it has room for, drop them, so that a map that
+	// is cleared does not keep the memory it needed at its largest. The
+	// map is given buckets for as many entries as it held, which is the
+	// best guess at how many it will hold again. A map cleared while
+	// close to full, as when it is cleared and refilled over and over,
+	// keeps its buckets. Dropping them is only safe if no iterator holds
+	// on to the buckets, as existing iterators must see them empty.
+	iterating := h.flags&(iterator|oldIterator) != 0
+	release := !iterating &&
+		debug.mapclearshrink > 0 && int32(h.B) > debug.mapclearshrink &&
+		underShrinkFactor(h.count, h.B)
+	newB := uint8(0)
+	for release && overLoadFactor(h.count, newB) {
+		newB++
+	}
 
 	// Mark buckets empty, so existing iterators can be terminated, see issue #59411.
+	// Without iterators this is not needed: the old and overflow buckets
+	// are dropped, and makeBucketArray below clears the bucket array in
+	// bulk, which for a map without pointers is the only work done.
 	markBucketsEmpty := func(bucket unsafe.Pointer, mask uintptr) {
 		for i := uintptr(0); i <= mask; i++ {
 			b := (*bmap)(add(bucket, i*uintptr(t.BucketSize)))
 			for ; b != nil; b = b.overflow(t) {
-				for i := uintptr(0); i < abi.MapBucketCount; i++ {
-					b.tophash[i] = emptyRest
-				}
+				// emptyRest is zero.
+				b.tophash = [abi.MapBucketCount]uint8{}
 			}
 		}
 	}
-	markBucketsEmpty(h.buckets, bucketMask(h.B))
-	if oldBuckets := h.oldbuckets; oldBuckets != nil {
-		markBucketsEmpty(oldBuckets, h.oldbucketmask())
+	if iterating {
+		markBucketsEmpty(h.buckets, bucketMask(h.B))
+		if oldBuckets := h.oldbuckets; oldBuckets != nil {
+			markBucketsEmpty(oldBuckets, h.oldbucketmask())
+		}
 	}
 
 	h.flags &^= sameSizeGrow
@@ -1113,30 +1429,94 @@ func mapclear(t *maptype, h *hmap) {
 
 	// Reset the hash seed to make it more difficult for attackers to
 	// repeatedly trigger hash collisions. See issue 25237.
-	h.hash0 = uint32(rand())
+	h.hash0 = newHashSeed()
 
 	// Keep the mapextra allocation but clear any extra information.
 	if h.extra != nil {
 		*h.extra = mapextra{}
 	}
 
-	// makeBucketArray clears the memory pointed to by h.buckets
-	// and recovers any overflow buckets by generating them
-	// as if h.buckets was newly alloced.
-	_, nextOverflow := makeBucketArray(t, h.B, h.buckets)
-	if nextOverflow != nil {
-		// If overflow buckets are created then h.extra
-		// will have been allocated during initial bucket creation.
-		h.extra.nextOverflow = nextOverflow
+	if release {
+		mapStats.shrinks.Add(1)
+		var nbuckets uintptr
+		if newB > 0 {
+			nbuckets = bucketShift(newB)
+		}
+		if debug.maptrace > 0 {
+			maptrace(t, h, "clear", nbuckets)
+		}
+		h.B = newB
+		h.buckets = nil
+		if newB > 0 {
+			var nextOverflow *bmap
+			h.buckets, nextOverflow = makeBucketArray(t, newB, nil)
+			if nextOverflow != nil {
+				if h.extra == nil {
+					h.extra = new(mapextra)
+				}
+				h.extra.nextOverflow = nextOverflow
+			}
+		}
+	} else {
+		// makeBucketArray clears the memory pointed to by h.buckets
+		// and recovers any overflow buckets by generating them
+		// as if h.buckets was newly alloced.
+		_, nextOverflow := makeBucketArray(t, h.B, h.buckets)
+		if nextOverflow != nil {
+			// If overflow buckets are created then h.extra
+			// will have been allocated during initial bucket creation.
+			h.extra.nextOverflow = nextOverflow
+		}
 	}
 
 	if h.flags&hashWriting == 0 {
-		fatal("concurrent map writes")
+		mapfatal(t, h, "concurrent map writes")
 	}
 	h.flags &^= hashWriting
 }
 
+// mapfatal is like fatal for misuse of a map h of type t.
+// The report includes the type of the map and the goroutine that last
+// began writing to it, since the stack of the other goroutine involved
+// is usually not available.
+func mapfatal(t *maptype, h *hmap, s string) {
+	writer := h.writer
+	self := getg().goid
+	systemstack(func() {
+		print("fatal error: ")
+		printindented(s)
+		print("\nmap type: ", toRType(&t.Type).string(), "\n")
+		if writer != 0 && writer != self {
+			print("map writer: goroutine ", writer, "\n")
+		}
+	})
+
+	fatalthrow(throwTypeUser)
+}
+
+// mapwriteerror reports an attempt to write to h, which is frozen or is
+// being written to by another goroutine.
+func mapwriteerror(t *maptype, h *hmap) {
+	if h.flags&frozen != 0 {
+		panic(plainError("write to frozen map"))
+	}
+	mapfatal(t, h, "concurrent map writes")
+}
+
+// mapStats holds counters of map events for runtime/metrics.
+var mapStats struct {
+	grows         atomic.Uint64 // growths to more buckets
+	sameSizeGrows atomic.Uint64 // growths to the same number of buckets
+	shrinks       atomic.Uint64 // bucket arrays dropped by shrinkIfSparse, mapclear or clipmap
+}
+
 func hashGrow(t *maptype, h *hmap) {
+	if debug.mapcheck != 0 {
+		checkmap(t, h)
+	}
+	if debug.mapcheckiter != 0 {
+		mapcheckiterModify(h, false, 0, "a key was added, and the map grew")
+	}
 	// If we've hit the load factor, get bigger.
 	// Otherwise, there are too many overflow buckets,
 	// so keep the same number of buckets and "grow" laterally.
@@ -1144,9 +1524,19 @@ func hashGrow(t *maptype, h *hmap) {
 	if !overLoadFactor(h.count+1, h.B) {
 		bigger = 0
 		h.flags |= sameSizeGrow
+		mapStats.sameSizeGrows.Add(1)
+	} else {
+		mapStats.grows.Add(1)
+	}
+	if debug.maptrace > 0 {
+		event := "grow"
+		if bigger == 0 {
+			event = "rehash"
+		}
+		maptrace(t, h, event, bucketShift(h.B+bigger))
 	}
 	oldbuckets := h.buckets
-	newbuckets, nextOverflow := makeBucketArray(t, h.B+bigger, nil)
+	newbuckets, nextOverflow := makeBucketArray(t, h.B+bigger, h.takeSpare(h.B+bigger))
 
 	flags := h.flags &^ (iterator | oldIterator)
 	if h.flags&iterator != 0 {
@@ -1179,6 +1569,114 @@ func hashGrow(t *maptype, h *hmap) {
 	// by growWork() and evacuate().
 }
 
+// maptrace reports for GODEBUG=maptrace that h is about to replace its
+// buckets with an array of nbuckets buckets. With maptrace=2 it also
+// prints the stack of the goroutine doing so.
+func maptrace(t *maptype, h *hmap, event string, nbuckets uintptr) {
+	print("maptrace: ", event, " ", toRType(&t.Type).string(), " len ", h.count,
+		": ", bucketShift(h.B), " -> ", nbuckets, " buckets, ",
+		nbuckets*uintptr(t.BucketSize), " bytes\n")
+	if debug.maptrace > 1 {
+		gp := getg()
+		pc := getcallerpc()
+		sp := getcallersp()
+		systemstack(func() {
+			traceback(pc, sp, 0, gp)
+		})
+	}
+}
+
+// shrinkIfSparse replaces the buckets of h with a smaller bucket array if
+// h holds far fewer items than its buckets can. Without it, a map that
+// once held many items would keep its buckets until it is freed.
+// It must be called with hashWriting set.
+//
+// Iterators keep walking the bucket array they started on, and the items
+// moved here are not marked as evacuated, so h is only shrunk if no
+// iterator has been started since the last growth. Shrinking is also not done during a
+// growth, so that evacuate need not deal with it.
+func shrinkIfSparse(t *maptype, h *hmap) {
+	if h.B < minShrinkB || !underShrinkFactor(h.count, h.B) ||
+		h.growing() || h.flags&(iterator|oldIterator) != 0 {
+		return
+	}
+	// Leave room for the map to grow back to twice its current size,
+	// so that maps whose size oscillates are not repeatedly shrunk and grown.
+	B := uint8(0)
+	for overLoadFactor(2*h.count, B) {
+		B++
+	}
+	mapStats.shrinks.Add(1)
+	if debug.maptrace > 0 {
+		maptrace(t, h, "shrink", bucketShift(B))
+	}
+	oldbuckets := h.buckets
+	oldB := h.B
+	newbuckets, nextOverflow := makeBucketArray(t, B, h.takeSpare(B))
+	h.B = B
+	h.buckets = newbuckets
+	h.noverflow = 0
+	if h.extra != nil {
+		h.extra.overflow = nil
+		h.extra.nextOverflow = nil
+	}
+	if nextOverflow != nil {
+		if h.extra == nil {
+			h.extra = new(mapextra)
+		}
+		h.extra.nextOverflow = nextOverflow
+	}
+
+	// An item in old bucket i belongs in new bucket i&bucketMask(B),
+	// so the items can be moved without rehashing their keys.
+	newbit := bucketShift(B)
+	for i := uintptr(0); i < newbit; i++ {
+		dst := evacDst{b: (*bmap)(add(newbuckets, i*uintptr(t.BucketSize)))}
+		dst.k = add(unsafe.Pointer(dst.b), dataOffset)
+		dst.e = add(dst.k, abi.MapBucketCount*uintptr(t.KeySize))
+		for j := i; j < bucketShift(oldB); j += newbit {
+			for b := (*bmap)(add(oldbuckets, j*uintptr(t.BucketSize))); b != nil; b = b.overflow(t) {
+				k := add(unsafe.Pointer(b), dataOffset)
+				e := add(k, abi.MapBucketCount*uintptr(t.KeySize))
+				for n := 0; n < abi.MapBucketCount; n, k, e = n+1, add(k, uintptr(t.KeySize)), add(e, uintptr(t.ValueSize)) {
+					top := b.tophash[n]
+					if isEmpty(top) {
+						continue
+					}
+					if dst.i == abi.MapBucketCount {
+						dst.b = h.newoverflow(t, dst.b)
+						dst.i = 0
+						dst.k = add(unsafe.Pointer(dst.b), dataOffset)
+						dst.e = add(dst.k, abi.MapBucketCount*uintptr(t.KeySize))
+					}
+					dst.b.tophash[dst.i&(abi.MapBucketCount-1)] = top // mask dst.i as an optimization, to avoid a bounds check
+					// The old buckets are garbage once we are done, so
+					// indirect keys and elems can be moved rather than copied.
+					if t.IndirectKey() {
+						*(*unsafe.Pointer)(dst.k) = *(*unsafe.Pointer)(k)
+					} else {
+						typedmemmove(t.Key, dst.k, k)
+					}
+					if t.IndirectElem() {
+						*(*unsafe.Pointer)(dst.e) = *(*unsafe.Pointer)(e)
+					} else {
+						typedmemmove(t.Elem, dst.e, e)
+					}
+					dst.i++
+					dst.k = add(dst.k, uintptr(t.KeySize))
+					dst.e = add(dst.e, uintptr(t.ValueSize))
+				}
+			}
+		}
+	}
+}
+
+// underShrinkFactor reports whether count items placed in 1<<B buckets
+// use less than 1/shrinkFactor of the load factor.
+func underShrinkFactor(count int, B uint8) bool {
+	return uintptr(count)*shrinkFactor < loadFactorNum*(bucketShift(B)/loadFactorDen)
+}
+
 // overLoadFactor reports whether count items placed in 1<<B buckets is over loadFactor.
 func overLoadFactor(count int, B uint8) bool {
 	return count > abi.MapBucketCount && uintptr(count) > loadFactorNum*(bucketShift(B)/loadFactorDen)
@@ -1373,7 +1871,25 @@ func advanceEvacuationMark(h *hmap, t *maptype, newbit uintptr) {
 		h.nevacuate++
 	}
 	if h.nevacuate == newbit { // newbit == # of oldbuckets
-		// Growing is all done. Free old main bucket array.
+		// Growing is all done. Free old main bucket array,
+		// or keep it for reuse if no iterator can still be walking it.
+		// In that case evacuate has cleared the main buckets of maps
+		// with pointers, but not the preallocated overflow buckets
+		// that follow them, so clear those here. Then the array keeps
+		// no keys, elems or overflow buckets alive.
+		if debug.mapreuse != 0 && h.flags&(iterator|oldIterator) == 0 {
+			oldB := h.B
+			if !h.sameSizeGrow() {
+				oldB--
+			}
+			if t.Bucket.Pointers() {
+				base := bucketShift(oldB)
+				if n := bucketArrayLen(t, oldB); n > base {
+					memclrHasPointers(add(h.oldbuckets, base*uintptr(t.BucketSize)), (n-base)*uintptr(t.BucketSize))
+				}
+			}
+			h.keepSpare(h.oldbuckets, oldB)
+		}
 		h.oldbuckets = nil
 		// Can discard old overflow buckets as well.
 		// If they are still referenced by an iterator,
@@ -1382,9 +1898,33 @@ func advanceEvacuationMark(h *hmap, t *maptype, newbit uintptr) {
 			h.extra.oldoverflow = nil
 		}
 		h.flags &^= sameSizeGrow
+		if debug.mapcheck != 0 {
+			checkmap(t, h)
+		}
 	}
 }
 
+// bucketArrayLen returns the number of buckets, including preallocated
+// overflow buckets, in a bucket array made by makeBucketArray for 1<<b
+// buckets.
+func bucketArrayLen(t *maptype, b uint8) uintptr {
+	nbuckets := bucketShift(b)
+	// For small b, overflow buckets are unlikely.
+	// Avoid the overhead of the calculation.
+	if b >= 4 {
+		// Add on the estimated number of overflow buckets
+		// required to insert the median number of elements
+		// used with this value of b.
+		nbuckets += bucketShift(b - 4)
+		sz := t.Bucket.Size_ * nbuckets
+		up := roundupsize(sz, !t.Bucket.Pointers())
+		if up != sz {
+			nbuckets = up / t.Bucket.Size_
+		}
+	}
+	return nbuckets
+}
+
 // Reflect stubs. Called from ../reflect/asm_*.s
 
 // reflect_makemap is for package reflect,
@@ -1586,6 +2126,11 @@ func reflect_mapclear(t *maptype, h *hmap) {
 	mapclear(t, h)
 }
 
+//go:linkname reflect_mapgrow reflect.mapgrow
+func reflect_mapgrow(t *maptype, h *hmap, n int) {
+	growmap(t, h, n)
+}
+
 //go:linkname reflectlite_maplen internal/reflectlite.maplen
 func reflectlite_maplen(h *hmap) int {
 	if h == nil {
@@ -1610,7 +2155,18 @@ func mapinitnoop()
 //go:linkname mapclone maps.clone
 func mapclone(m any) any {
 	e := efaceOf(&m)
-	e.data = unsafe.Pointer(mapclone2((*maptype)(unsafe.Pointer(e._type)), (*hmap)(e.data)))
+	src := (*hmap)(e.data)
+	e.data = unsafe.Pointer(mapclone2((*maptype)(unsafe.Pointer(e._type)), src, src.count))
+	return m
+}
+
+// mapclonecap for implementing maps.CloneCap
+//
+//go:linkname mapclonecap maps.cloneCap
+func mapclonecap(m any) any {
+	e := efaceOf(&m)
+	src := (*hmap)(e.data)
+	e.data = unsafe.Pointer(mapclone2((*maptype)(unsafe.Pointer(e._type)), src, src.capacity()))
 	return m
 }
 
@@ -1655,7 +2211,7 @@ func moveToBmap(t *maptype, h *hmap, dst *bmap, pos int, src *bmap) (*bmap, int)
 		}
 		if t.IndirectElem() {
 			srcEle = *(*unsafe.Pointer)(srcEle)
-			eStore := newobject(t.Elem)
+			eStore := newelem(t)
 			typedmemmove(t.Elem, eStore, srcEle)
 			*(*unsafe.Pointer)(dstEle) = eStore
 		} else {
@@ -1667,8 +2223,10 @@ func moveToBmap(t *maptype, h *hmap, dst *bmap, pos int, src *bmap) (*bmap, int)
 	return dst, pos
 }
 
-func mapclone2(t *maptype, src *hmap) *hmap {
-	dst := makemap(t, src.count, nil)
+// mapclone2 returns a copy of src with room for hint entries, which must
+// be at least src.count and at most src.capacity().
+func mapclone2(t *maptype, src *hmap, hint int) *hmap {
+	dst := makemap(t, hint, nil)
 	dst.hash0 = src.hash0
 	dst.nevacuate = 0
 	// flags do not need to be copied here, just like a new map has no flags.
@@ -1678,7 +2236,7 @@ func mapclone2(t *maptype, src *hmap) *hmap {
 	}
 
 	if src.flags&hashWriting != 0 {
-		fatal("concurrent map clone and map write")
+		mapfatal(t, src, "concurrent map clone and map write")
 	}
 
 	if src.B == 0 && !(t.IndirectKey() && t.NeedKeyUpdate()) && !t.IndirectElem() {
@@ -1689,6 +2247,26 @@ func mapclone2(t *maptype, src *hmap) *hmap {
 		return dst
 	}
 
+	if src.B == dst.B && !src.growing() && !(t.IndirectKey() && t.NeedKeyUpdate()) && !t.IndirectElem() {
+		// Quick copy for maps with as many buckets as the clone.
+		// Each entry stays at the same position, so the bucket array can
+		// be copied wholesale. Only the overflow chains need new buckets.
+		n := int(bucketShift(src.B))
+		typedslicecopy(t.Bucket, dst.buckets, n, src.buckets, n)
+		for i := 0; i < n; i++ {
+			srcBmap := (*bmap)(add(src.buckets, uintptr(i*int(t.BucketSize))))
+			dstBmap := (*bmap)(add(dst.buckets, uintptr(i*int(t.BucketSize))))
+			for srcBmap = srcBmap.overflow(t); srcBmap != nil; srcBmap = srcBmap.overflow(t) {
+				// newoverflow replaces the pointer to the source overflow
+				// bucket that was copied into dstBmap.
+				dstBmap = dst.newoverflow(t, dstBmap)
+				typedmemmove(t.Bucket, unsafe.Pointer(dstBmap), unsafe.Pointer(srcBmap))
+			}
+		}
+		dst.count = src.count
+		return dst
+	}
+
 	if dst.B == 0 {
 		dst.buckets = newobject(t.Bucket)
 	}
@@ -1746,7 +2324,7 @@ func mapclone2(t *maptype, src *hmap) *hmap {
 				}
 
 				if src.flags&hashWriting != 0 {
-					fatal("concurrent map clone and map write")
+					mapfatal(t, src, "concurrent map clone and map write")
 				}
 
 				srcK := add(unsafe.Pointer(srcBmap), dataOffset+i*uintptr(t.KeySize))
@@ -1767,6 +2345,369 @@ func mapclone2(t *maptype, src *hmap) *hmap {
 	return dst
 }
 
+// mappop for implementing maps.Pop
+//
+//go:linkname mappop maps.pop
+func mappop(m any, key, elem unsafe.Pointer) bool {
+	e := efaceOf(&m)
+	t := (*maptype)(unsafe.Pointer(e._type))
+	h := (*hmap)(e.data)
+	if raceenabled && h != nil {
+		callerpc := getcallerpc()
+		pc := abi.FuncPCABIInternal(mappop)
+		racewritepc(unsafe.Pointer(h), callerpc, pc)
+		raceReadObjectPC(t.Key, key, callerpc, pc)
+	}
+	if msanenabled && h != nil {
+		msanread(key, t.Key.Size_)
+	}
+	if asanenabled && h != nil {
+		asanread(key, t.Key.Size_)
+	}
+	return deletekey(t, h, key, elem)
+}
+
+// mapassign2 for implementing maps.Merge
+//
+//go:linkname mapassign2 maps.assign
+func mapassign2(m any, key unsafe.Pointer) (unsafe.Pointer, bool) {
+	e := efaceOf(&m)
+	t := (*maptype)(unsafe.Pointer(e._type))
+	h := (*hmap)(e.data)
+	if h == nil {
+		panic(plainError("assignment to entry in nil map"))
+	}
+	if raceenabled {
+		callerpc := getcallerpc()
+		pc := abi.FuncPCABIInternal(mapassign2)
+		racewritepc(unsafe.Pointer(h), callerpc, pc)
+		raceReadObjectPC(t.Key, key, callerpc, pc)
+	}
+	if msanenabled {
+		msanread(key, t.Key.Size_)
+	}
+	if asanenabled {
+		asanread(key, t.Key.Size_)
+	}
+	return assignkey(t, h, key)
+}
+
+// mapgrow for implementing maps.Grow
+//
+//go:linkname mapgrow maps.grow
+func mapgrow(m any, n int) {
+	e := efaceOf(&m)
+	growmap((*maptype)(unsafe.Pointer(e._type)), (*hmap)(e.data), n)
+}
+
+// growmap makes room in h for n more items, so that they can be
+// added without growing h. Rather than doubling the number of buckets
+// step by step, it moves all items to a bucket array of the final size
+// in a single pass.
+func growmap(t *maptype, h *hmap, n int) {
+	if raceenabled && h != nil {
+		callerpc := getcallerpc()
+		pc := abi.FuncPCABIInternal(growmap)
+		racewritepc(unsafe.Pointer(h), callerpc, pc)
+	}
+
+	if h == nil {
+		return
+	}
+	if h.flags&frozen != 0 {
+		mapwriteerror(t, h)
+	}
+	if n <= 0 {
+		return
+	}
+	count := h.count + n
+	if count < h.count {
+		panic(plainError("maps.Grow: size out of range"))
+	}
+	B := h.B
+	for overLoadFactor(count, B) {
+		B++
+	}
+	if B == h.B {
+		return
+	}
+	mem, overflow := math.MulUintptr(bucketShift(B), t.Bucket.Size_)
+	if overflow || mem > maxAlloc {
+		panic(plainError("maps.Grow: size out of range"))
+	}
+	resizemap(t, h, B)
+}
+
+// mapcap for implementing maps.Cap
+//
+//go:linkname mapcap maps.capacity
+func mapcap(m any) int {
+	h := (*hmap)(efaceOf(&m).data)
+	if h == nil {
+		return 0
+	}
+	if raceenabled {
+		callerpc := getcallerpc()
+		racereadpc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapcap))
+	}
+	return h.capacity()
+}
+
+// capacity returns the number of entries h can hold before it next grows.
+func (h *hmap) capacity() int {
+	// overLoadFactor(count, h.B) is false up to this count.
+	if h.B == 0 {
+		return abi.MapBucketCount
+	}
+	return int(loadFactorNum * (bucketShift(h.B) / loadFactorDen))
+}
+
+// mapfreeze for implementing maps.Freeze
+//
+//go:linkname mapfreeze maps.freeze
+func mapfreeze(m any) {
+	e := efaceOf(&m)
+	freezemap((*maptype)(unsafe.Pointer(e._type)), (*hmap)(e.data))
+}
+
+// freezemap makes h read-only. Every later write to h panics instead of
+// modifying it, so any number of goroutines may read h concurrently.
+func freezemap(t *maptype, h *hmap) {
+	if raceenabled && h != nil {
+		callerpc := getcallerpc()
+		pc := abi.FuncPCABIInternal(freezemap)
+		racewritepc(unsafe.Pointer(h), callerpc, pc)
+	}
+
+	if h == nil || h.flags&frozen != 0 {
+		return
+	}
+	if h.flags&hashWriting != 0 {
+		mapfatal(t, h, "concurrent map writes")
+	}
+	h.flags ^= hashWriting
+	h.writer = getg().goid
+
+	// Finish any growth in progress, since no later write will, and
+	// lookups are cheaper once all items are in h.buckets.
+	for h.growing() {
+		evacuate(t, h, h.nevacuate)
+	}
+
+	if h.flags&hashWriting == 0 {
+		mapfatal(t, h, "concurrent map writes")
+	}
+	h.flags &^= hashWriting
+	// Set the iterator flags too, so that iterators never need to
+	// update h.flags, which concurrent readers would race on.
+	h.flags |= frozen | iterator | oldIterator
+}
+
+// mapclip for implementing maps.Clip
+//
+//go:linkname mapclip maps.clip
+func mapclip(m any) {
+	e := efaceOf(&m)
+	clipmap((*maptype)(unsafe.Pointer(e._type)), (*hmap)(e.data))
+}
+
+// clipmap shrinks the buckets of h to the fewest that hold its items.
+func clipmap(t *maptype, h *hmap) {
+	if raceenabled && h != nil {
+		callerpc := getcallerpc()
+		pc := abi.FuncPCABIInternal(clipmap)
+		racewritepc(unsafe.Pointer(h), callerpc, pc)
+	}
+
+	if h == nil {
+		return
+	}
+	if h.flags&frozen != 0 {
+		mapwriteerror(t, h)
+	}
+	B := uint8(0)
+	for overLoadFactor(h.count, B) {
+		B++
+	}
+	if B >= h.B {
+		return
+	}
+	resizemap(t, h, B)
+}
+
+// resizemap moves all items of h to a new array of 1<<B buckets.
+// Unlike hashGrow, it does so at once, so it can change the number of
+// buckets by any factor.
+func resizemap(t *maptype, h *hmap, B uint8) {
+	if h.flags&(hashWriting|frozen) != 0 {
+		mapwriteerror(t, h)
+	}
+	h.flags ^= hashWriting
+	h.writer = getg().goid
+
+	// Finish any growth in progress, so that all items are in h.buckets.
+	for h.growing() {
+		evacuate(t, h, h.nevacuate)
+	}
+
+	if B > h.B {
+		mapStats.grows.Add(1)
+	} else {
+		mapStats.shrinks.Add(1)
+	}
+	if debug.mapcheckiter != 0 {
+		mapcheckiterModify(h, false, 0, "the map was resized")
+	}
+	if debug.maptrace > 0 {
+		event := "grow"
+		if B < h.B {
+			event = "shrink"
+		}
+		maptrace(t, h, event, bucketShift(B))
+	}
+	movemap(t, h, B)
+
+	if debug.mapcheck != 0 {
+		checkmap(t, h)
+	}
+	if h.flags&hashWriting == 0 {
+		mapfatal(t, h, "concurrent map writes")
+	}
+	h.flags &^= hashWriting
+}
+
+// canReseed reports whether reseedmap may be called on h.
+//
+// Each map is reseeded at most once: if its keys still collide with a
+// new seed, the collisions do not depend on the seed, and rehashing
+// again would only add to the cost of every insertion. With
+// GODEBUG=mapseed, all maps use the same seed, so reseeding cannot help.
+// As in shrinkIfSparse, maps with iterators are not reseeded, since
+// iterators started during a growth need keys to keep their hashes.
+func canReseed(h *hmap) bool {
+	return mapSeed == 0 && h.flags&(reseeded|iterator|oldIterator) == 0
+}
+
+// reseedmap gives h a new hash seed and moves all items of h to the
+// buckets that their new hashes select. mapassign calls it when it finds
+// a chain of overflow buckets so long that the keys were probably chosen
+// to collide, which is possible with keys that an attacker controls if
+// the hash function is weak for the key type.
+// It must be called with hashWriting set.
+func reseedmap(t *maptype, h *hmap) {
+	// Finish any growth in progress using the old seed.
+	for h.growing() {
+		evacuate(t, h, h.nevacuate)
+	}
+	mapStats.sameSizeGrows.Add(1)
+	if debug.maptrace > 0 {
+		maptrace(t, h, "reseed", bucketShift(h.B))
+	}
+	h.hash0 = newHashSeed()
+	h.flags |= reseeded
+	movemap(t, h, h.B)
+	if debug.mapcheck != 0 {
+		checkmap(t, h)
+	}
+}
+
+// movemap moves all items of h to a new array of 1<<B buckets,
+// placing them according to their hashes with the current seed.
+// h must not be growing, and hashWriting must be set.
+func movemap(t *maptype, h *hmap, B uint8) {
+	oldbuckets := h.buckets
+	oldB := h.B
+	newbuckets, nextOverflow := makeBucketArray(t, B, h.takeSpare(B))
+
+	// Iterators started on oldbuckets keep walking them. As after a
+	// regular growth, they find the items marked as evacuated and look
+	// them up in the new buckets.
+	markEvacuated := h.flags&iterator != 0
+	flags := h.flags &^ (iterator | oldIterator)
+	if h.flags&iterator != 0 {
+		flags |= oldIterator
+	}
+	h.B = B
+	h.flags = flags
+	h.buckets = newbuckets
+	h.nevacuate = 0
+	h.noverflow = 0
+	if h.extra != nil {
+		h.extra.overflow = nil
+		h.extra.nextOverflow = nil
+	}
+	if nextOverflow != nil {
+		if h.extra == nil {
+			h.extra = new(mapextra)
+		}
+		h.extra.nextOverflow = nextOverflow
+	}
+
+	if oldbuckets != nil {
+		for i := uintptr(0); i < bucketShift(oldB); i++ {
+			for b := (*bmap)(add(oldbuckets, i*uintptr(t.BucketSize))); b != nil; b = b.overflow(t) {
+				k := add(unsafe.Pointer(b), dataOffset)
+				e := add(k, abi.MapBucketCount*uintptr(t.KeySize))
+				for j := 0; j < abi.MapBucketCount; j, k, e = j+1, add(k, uintptr(t.KeySize)), add(e, uintptr(t.ValueSize)) {
+					top := b.tophash[j]
+					if isEmpty(top) {
+						if markEvacuated {
+							b.tophash[j] = evacuatedEmpty
+						}
+						continue
+					}
+					if top < minTopHash {
+						throw("bad map state")
+					}
+					k2 := k
+					if t.IndirectKey() {
+						k2 = *((*unsafe.Pointer)(k2))
+					}
+					hash := t.Hasher(k2, uintptr(h.hash0))
+					dst, di := h.emptySlot(t, (*bmap)(add(newbuckets, (hash&bucketMask(B))*uintptr(t.BucketSize))))
+					dst.tophash[di] = tophash(hash)
+					dk := add(unsafe.Pointer(dst), dataOffset+di*uintptr(t.KeySize))
+					de := add(unsafe.Pointer(dst), dataOffset+abi.MapBucketCount*uintptr(t.KeySize)+di*uintptr(t.ValueSize))
+					// The old buckets are garbage once we are done, except
+					// to iterators, which never write through them, so
+					// indirect keys and elems can be moved rather than copied.
+					if t.IndirectKey() {
+						*(*unsafe.Pointer)(dk) = *(*unsafe.Pointer)(k)
+					} else {
+						typedmemmove(t.Key, dk, k)
+					}
+					if t.IndirectElem() {
+						*(*unsafe.Pointer)(de) = *(*unsafe.Pointer)(e)
+					} else {
+						typedmemmove(t.Elem, de, e)
+					}
+					if markEvacuated {
+						b.tophash[j] = evacuatedX
+					}
+				}
+			}
+		}
+	}
+}
+
+// emptySlot returns the first empty slot in the chain of buckets
+// starting at b, adding an overflow bucket if they are all full.
+// The buckets must have been filled in order, without deletions.
+func (h *hmap) emptySlot(t *maptype, b *bmap) (*bmap, uintptr) {
+	for {
+		for i := uintptr(0); i < abi.MapBucketCount; i++ {
+			if isEmpty(b.tophash[i]) {
+				return b, i
+			}
+		}
+		ovf := b.overflow(t)
+		if ovf == nil {
+			return h.newoverflow(t, b), 0
+		}
+		b = ovf
+	}
+}
+
 // keys for implementing maps.keys
 //
 //go:linkname keys maps.keys
@@ -1779,7 +2720,7 @@ func keys(m any, p unsafe.Pointer) {
 		return
 	}
 	s := (*slice)(p)
-	r := int(rand())
+	r := int(maprand())
 	offset := uint8(r >> h.B & (abi.MapBucketCount - 1))
 	if h.B == 0 {
 		copyKeys(t, h, (*bmap)(h.buckets), s, offset)
@@ -1815,14 +2756,14 @@ func copyKeys(t *maptype, h *hmap, b *bmap, s *slice, offset uint8) {
 				continue
 			}
 			if h.flags&hashWriting != 0 {
-				fatal("concurrent map read and map write")
+				mapfatal(t, h, "concurrent map read and map write")
 			}
 			k := add(unsafe.Pointer(b), dataOffset+offi*uintptr(t.KeySize))
 			if t.IndirectKey() {
 				k = *((*unsafe.Pointer)(k))
 			}
 			if s.len >= s.cap {
-				fatal("concurrent map read and map write")
+				mapfatal(t, h, "concurrent map read and map write")
 			}
 			typedmemmove(t.Key, add(s.array, uintptr(s.len)*uintptr(t.Key.Size())), k)
 			s.len++
@@ -1842,7 +2783,7 @@ func values(m any, p unsafe.Pointer) {
 		return
 	}
 	s := (*slice)(p)
-	r := int(rand())
+	r := int(maprand())
 	offset := uint8(r >> h.B & (abi.MapBucketCount - 1))
 	if h.B == 0 {
 		copyValues(t, h, (*bmap)(h.buckets), s, offset)
@@ -1879,7 +2820,7 @@ func copyValues(t *maptype, h *hmap, b *bmap, s *slice, offset uint8) {
 			}
 
 			if h.flags&hashWriting != 0 {
-				fatal("concurrent map read and map write")
+				mapfatal(t, h, "concurrent map read and map write")
 			}
 
 			ele := add(unsafe.Pointer(b), dataOffset+abi.MapBucketCount*uintptr(t.KeySize)+offi*uintptr(t.ValueSize))
@@ -1887,7 +2828,7 @@ func copyValues(t *maptype, h *hmap, b *bmap, s *slice, offset uint8) {
 				ele = *((*unsafe.Pointer)(ele))
 			}
 			if s.len >= s.cap {
-				fatal("concurrent map read and map write")
+				mapfatal(t, h, "concurrent map read and map write")
 			}
 			typedmemmove(t.Elem, add(s.array, uintptr(s.len)*uintptr(t.Elem.Size())), ele)
 			s.len++
@@ -1895,3 +2836,112 @@ func copyValues(t *maptype, h *hmap, b *bmap, s *slice, offset uint8) {
 		b = b.overflow(t)
 	}
 }
+
+// checkmap verifies the structure of h and throws if it is corrupt.
+// It checks that the tophash of every cell is valid, that no filled
+// cell follows an emptyRest cell in a bucket chain, that every key is
+// in the bucket its hash selects, that no evacuated bucket still holds
+// entries and that h.count matches the number of live entries.
+//
+// checkmap takes time proportional to the size of the map. With
+// GODEBUG=mapcheck=1 it is called whenever a map starts or finishes
+// growing or is resized.
+func checkmap(t *maptype, h *hmap) {
+	if h == nil || h.buckets == nil {
+		if h != nil && h.count != 0 {
+			badmap(t, h, nil, 0, "map with entries has no buckets")
+		}
+		return
+	}
+	if h.flags&frozen != 0 && (h.flags&(iterator|oldIterator) != iterator|oldIterator || h.growing()) {
+		badmap(t, h, nil, 0, "frozen map is not read-only")
+	}
+	n := 0
+	if h.growing() {
+		if h.nevacuate > h.noldbuckets() {
+			badmap(t, h, nil, 0, "evacuation mark past end of old buckets")
+		}
+		for i := uintptr(0); i < h.noldbuckets(); i++ {
+			b := (*bmap)(add(h.oldbuckets, i*uintptr(t.BucketSize)))
+			if evacuated(b) {
+				checkevacuated(t, h, b)
+				continue
+			}
+			if i < h.nevacuate {
+				badmap(t, h, b, 0, "bucket before evacuation mark is not evacuated")
+			}
+			n += checkbucket(t, h, b, i, h.oldbucketmask())
+		}
+	}
+	for i := uintptr(0); i < bucketShift(h.B); i++ {
+		b := (*bmap)(add(h.buckets, i*uintptr(t.BucketSize)))
+		n += checkbucket(t, h, b, i, bucketMask(h.B))
+	}
+	if n != h.count {
+		print("runtime: map count ", h.count, ", found ", n, " entries\n")
+		badmap(t, h, nil, 0, "wrong map count")
+	}
+}
+
+// checkbucket checks the chain of buckets starting at b, which has
+// index i in a bucket array with the given mask, and returns the number
+// of entries in it.
+func checkbucket(t *maptype, h *hmap, b *bmap, i, mask uintptr) int {
+	n := 0
+	rest := false
+	for ; b != nil; b = b.overflow(t) {
+		for j := uintptr(0); j < abi.MapBucketCount; j++ {
+			top := b.tophash[j]
+			switch {
+			case top == emptyRest:
+				rest = true
+				continue
+			case rest:
+				badmap(t, h, b, j, "cell used after emptyRest")
+			case top == emptyOne:
+				continue
+			case top < minTopHash:
+				badmap(t, h, b, j, "evacuated cell in live bucket")
+			}
+			n++
+			k := add(unsafe.Pointer(b), dataOffset+j*uintptr(t.KeySize))
+			if t.IndirectKey() {
+				k = *((*unsafe.Pointer)(k))
+			}
+			if !t.ReflexiveKey() && !t.Key.Equal(k, k) {
+				// NaN keys hash randomly, so can be in any bucket.
+				continue
+			}
+			hash := t.Hasher(k, uintptr(h.hash0))
+			if hash&mask != i {
+				badmap(t, h, b, j, "key in wrong bucket")
+			}
+			if tophash(hash) != top {
+				badmap(t, h, b, j, "wrong tophash")
+			}
+		}
+	}
+	return n
+}
+
+// checkevacuated checks that the old bucket chain starting at b holds
+// no entries.
+func checkevacuated(t *maptype, h *hmap, b *bmap) {
+	for ; b != nil; b = b.overflow(t) {
+		for j := uintptr(0); j < abi.MapBucketCount; j++ {
+			if top := b.tophash[j]; top < evacuatedX || top > evacuatedEmpty {
+				badmap(t, h, b, j, "live cell in evacuated bucket")
+			}
+		}
+	}
+}
+
+// badmap reports a corrupt map found by checkmap.
+func badmap(t *maptype, h *hmap, b *bmap, i uintptr, s string) {
+	print("runtime: map ", h, " type ", toRType(&t.Type).string(), " count ", h.count, " B ", h.B, " flags ", h.flags, "\n")
+	if b != nil {
+		print("runtime: bucket ", b, " cell ", i, " tophash ", b.tophash[i], "\n")
+	}
+	print("runtime: ", s, "\n")
+	throw("corrupt map")
+}
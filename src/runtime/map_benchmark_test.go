@@ -5,6 +5,7 @@
 package runtime_test
 
 import (
+	"encoding/binary"
 	"fmt"
 	"math/rand"
 	"strconv"
@@ -206,6 +207,27 @@ func benchmarkMapStringKeysEight(b *testing.B, keySize int) {
 	}
 }
 
+func BenchmarkMapShortStringKeys(b *testing.B) {
+	for _, keySize := range []int{3, 6, 8, 12, 16} {
+		b.Run(fmt.Sprint("len=", keySize), func(b *testing.B) {
+			const n = 1000
+			m := make(map[string]int, n)
+			keys := make([]string, n)
+			for i := range keys {
+				k := fmt.Sprintf("%0*d", keySize, i)
+				k = k[len(k)-keySize:]
+				m[k] = i
+				// Look up copies so comparisons cannot stop at pointer equality.
+				keys[i] = string([]byte(k))
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = m[keys[i%n]]
+			}
+		})
+	}
+}
+
 func BenchmarkIntMap(b *testing.B) {
 	m := make(map[int]bool)
 	for i := 0; i < 8; i++ {
@@ -412,6 +434,18 @@ func BenchmarkMapPopulate(b *testing.B) {
 	}
 }
 
+func BenchmarkMapPopulateLargeValue(b *testing.B) {
+	var v [256]byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m := make(map[int][256]byte, 100)
+		for j := 0; j < 100; j++ {
+			v[0] = byte(j)
+			m[j] = v
+		}
+	}
+}
+
 type ComplexAlgKey struct {
 	a, b, c int64
 	_       int
@@ -456,6 +490,21 @@ func BenchmarkGoMapClear(b *testing.B) {
 	})
 }
 
+func BenchmarkGoMapClearRefill(b *testing.B) {
+	for size := 10; size < 1000000; size *= 10 {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			b.ReportAllocs()
+			m := make(map[int]int)
+			for i := 0; i < b.N; i++ {
+				for j := 0; j < size; j++ {
+					m[j] = j
+				}
+				clear(m)
+			}
+		})
+	}
+}
+
 func BenchmarkMapStringConversion(b *testing.B) {
 	for _, length := range []int{32, 64} {
 		b.Run(strconv.Itoa(length), func(b *testing.B) {
@@ -538,3 +587,41 @@ func BenchmarkNewEmptyMapHintGreaterThan8(b *testing.B) {
 		_ = make(map[int]int, hintGreaterThan8)
 	}
 }
+
+func BenchmarkMap16ByteKey(b *testing.B) {
+	const N = 1000
+	keys := make([][16]byte, N)
+	for i := range keys {
+		binary.LittleEndian.PutUint64(keys[i][:], uint64(i)*0x9e3779b97f4a7c15)
+		binary.LittleEndian.PutUint64(keys[i][8:], uint64(i))
+	}
+	b.Run("Assign", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			m := make(map[[16]byte]int)
+			for j, k := range keys {
+				m[k] = j
+			}
+		}
+	})
+	m := make(map[[16]byte]int)
+	for j, k := range keys {
+		m[k] = j
+	}
+	b.Run("Access", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, k := range keys {
+				_ = m[k]
+			}
+		}
+	})
+	b.Run("Delete", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, k := range keys {
+				delete(m, k)
+			}
+			for j, k := range keys {
+				m[k] = j
+			}
+		}
+	})
+}
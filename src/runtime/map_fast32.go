@@ -19,7 +19,7 @@ func mapaccess1_fast32(t *maptype, h *hmap, key uint32) unsafe.Pointer {
 		return unsafe.Pointer(&zeroVal[0])
 	}
 	if h.flags&hashWriting != 0 {
-		fatal("concurrent map read and map write")
+		mapfatal(t, h, "concurrent map read and map write")
 	}
 	var b *bmap
 	if h.B == 0 {
@@ -68,7 +68,7 @@ func mapaccess2_fast32(t *maptype, h *hmap, key uint32) (unsafe.Pointer, bool) {
 		return unsafe.Pointer(&zeroVal[0]), false
 	}
 	if h.flags&hashWriting != 0 {
-		fatal("concurrent map read and map write")
+		mapfatal(t, h, "concurrent map read and map write")
 	}
 	var b *bmap
 	if h.B == 0 {
@@ -118,13 +118,14 @@ func mapassign_fast32(t *maptype, h *hmap, key uint32) unsafe.Pointer {
 		callerpc := getcallerpc()
 		racewritepc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapassign_fast32))
 	}
-	if h.flags&hashWriting != 0 {
-		fatal("concurrent map writes")
+	if h.flags&(hashWriting|frozen) != 0 {
+		mapwriteerror(t, h)
 	}
 	hash := t.Hasher(noescape(unsafe.Pointer(&key)), uintptr(h.hash0))
 
 	// Set hashWriting after calling t.hasher for consistency with mapassign.
 	h.flags ^= hashWriting
+	h.writer = getg().goid
 
 	if h.buckets == nil {
 		h.buckets = newobject(t.Bucket) // newarray(t.bucket, 1)
@@ -140,6 +141,7 @@ again:
 	var insertb *bmap
 	var inserti uintptr
 	var insertk unsafe.Pointer
+	chain := 0 // number of overflow buckets searched
 
 bucketloop:
 	for {
@@ -167,6 +169,7 @@ bucketloop:
 			break
 		}
 		b = ovf
+		chain++
 	}
 
 	// Did not find mapping for key. Allocate new cell & add entry.
@@ -178,6 +181,14 @@ bucketloop:
 		goto again // Growing the table invalidates everything, so try again
 	}
 
+	// If the keys in this bucket likely collide on purpose, rehash them
+	// with a new seed.
+	if chain >= reseedChain && canReseed(h) {
+		reseedmap(t, h)
+		hash = t.Hasher(noescape(unsafe.Pointer(&key)), uintptr(h.hash0))
+		goto again
+	}
+
 	if insertb == nil {
 		// The current bucket and all the overflow buckets connected to it are full, allocate a new one.
 		insertb = h.newoverflow(t, b)
@@ -194,7 +205,7 @@ bucketloop:
 done:
 	elem := add(unsafe.Pointer(insertb), dataOffset+abi.MapBucketCount*4+inserti*uintptr(t.ValueSize))
 	if h.flags&hashWriting == 0 {
-		fatal("concurrent map writes")
+		mapfatal(t, h, "concurrent map writes")
 	}
 	h.flags &^= hashWriting
 	return elem
@@ -217,13 +228,14 @@ func mapassign_fast32ptr(t *maptype, h *hmap, key unsafe.Pointer) unsafe.Pointer
 		callerpc := getcallerpc()
 		racewritepc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapassign_fast32))
 	}
-	if h.flags&hashWriting != 0 {
-		fatal("concurrent map writes")
+	if h.flags&(hashWriting|frozen) != 0 {
+		mapwriteerror(t, h)
 	}
 	hash := t.Hasher(noescape(unsafe.Pointer(&key)), uintptr(h.hash0))
 
 	// Set hashWriting after calling t.hasher for consistency with mapassign.
 	h.flags ^= hashWriting
+	h.writer = getg().goid
 
 	if h.buckets == nil {
 		h.buckets = newobject(t.Bucket) // newarray(t.bucket, 1)
@@ -239,6 +251,7 @@ again:
 	var insertb *bmap
 	var inserti uintptr
 	var insertk unsafe.Pointer
+	chain := 0 // number of overflow buckets searched
 
 bucketloop:
 	for {
@@ -266,6 +279,7 @@ bucketloop:
 			break
 		}
 		b = ovf
+		chain++
 	}
 
 	// Did not find mapping for key. Allocate new cell & add entry.
@@ -277,6 +291,14 @@ bucketloop:
 		goto again // Growing the table invalidates everything, so try again
 	}
 
+	// If the keys in this bucket likely collide on purpose, rehash them
+	// with a new seed.
+	if chain >= reseedChain && canReseed(h) {
+		reseedmap(t, h)
+		hash = t.Hasher(noescape(unsafe.Pointer(&key)), uintptr(h.hash0))
+		goto again
+	}
+
 	if insertb == nil {
 		// The current bucket and all the overflow buckets connected to it are full, allocate a new one.
 		insertb = h.newoverflow(t, b)
@@ -293,7 +315,7 @@ bucketloop:
 done:
 	elem := add(unsafe.Pointer(insertb), dataOffset+abi.MapBucketCount*4+inserti*uintptr(t.ValueSize))
 	if h.flags&hashWriting == 0 {
-		fatal("concurrent map writes")
+		mapfatal(t, h, "concurrent map writes")
 	}
 	h.flags &^= hashWriting
 	return elem
@@ -307,14 +329,15 @@ func mapdelete_fast32(t *maptype, h *hmap, key uint32) {
 	if h == nil || h.count == 0 {
 		return
 	}
-	if h.flags&hashWriting != 0 {
-		fatal("concurrent map writes")
+	if h.flags&(hashWriting|frozen) != 0 {
+		mapwriteerror(t, h)
 	}
 
 	hash := t.Hasher(noescape(unsafe.Pointer(&key)), uintptr(h.hash0))
 
 	// Set hashWriting after calling t.hasher for consistency with mapdelete
 	h.flags ^= hashWriting
+	h.writer = getg().goid
 
 	bucket := hash & bucketMask(h.B)
 	if h.growing() {
@@ -374,17 +397,21 @@ search:
 			}
 		notLast:
 			h.count--
+			if debug.mapcheckiter != 0 {
+				mapcheckiterModify(h, true, hash, "a key other than the one just produced was deleted")
+			}
 			// Reset the hash seed to make it more difficult for attackers to
 			// repeatedly trigger hash collisions. See issue 25237.
 			if h.count == 0 {
-				h.hash0 = uint32(rand())
+				h.hash0 = newHashSeed()
 			}
+			shrinkIfSparse(t, h)
 			break search
 		}
 	}
 
 	if h.flags&hashWriting == 0 {
-		fatal("concurrent map writes")
+		mapfatal(t, h, "concurrent map writes")
 	}
 	h.flags &^= hashWriting
 }
@@ -19,7 +19,7 @@ func mapaccess1_faststr(t *maptype, h *hmap, ky string) unsafe.Pointer {
 		return unsafe.Pointer(&zeroVal[0])
 	}
 	if h.flags&hashWriting != 0 {
-		fatal("concurrent map read and map write")
+		mapfatal(t, h, "concurrent map read and map write")
 	}
 	key := stringStructOf(&ky)
 	if h.B == 0 {
@@ -123,7 +123,7 @@ func mapaccess2_faststr(t *maptype, h *hmap, ky string) (unsafe.Pointer, bool) {
 		return unsafe.Pointer(&zeroVal[0]), false
 	}
 	if h.flags&hashWriting != 0 {
-		fatal("concurrent map read and map write")
+		mapfatal(t, h, "concurrent map read and map write")
 	}
 	key := stringStructOf(&ky)
 	if h.B == 0 {
@@ -228,14 +228,15 @@ func mapassign_faststr(t *maptype, h *hmap, s string) unsafe.Pointer {
 		callerpc := getcallerpc()
 		racewritepc(unsafe.Pointer(h), callerpc, abi.FuncPCABIInternal(mapassign_faststr))
 	}
-	if h.flags&hashWriting != 0 {
-		fatal("concurrent map writes")
+	if h.flags&(hashWriting|frozen) != 0 {
+		mapwriteerror(t, h)
 	}
 	key := stringStructOf(&s)
 	hash := t.Hasher(noescape(unsafe.Pointer(&s)), uintptr(h.hash0))
 
 	// Set hashWriting after calling t.hasher for consistency with mapassign.
 	h.flags ^= hashWriting
+	h.writer = getg().goid
 
 	if h.buckets == nil {
 		h.buckets = newobject(t.Bucket) // newarray(t.bucket, 1)
@@ -252,6 +253,7 @@ again:
 	var insertb *bmap
 	var inserti uintptr
 	var insertk unsafe.Pointer
+	chain := 0 // number of overflow buckets searched
 
 bucketloop:
 	for {
@@ -286,6 +288,7 @@ bucketloop:
 			break
 		}
 		b = ovf
+		chain++
 	}
 
 	// Did not find mapping for key. Allocate new cell & add entry.
@@ -297,6 +300,14 @@ bucketloop:
 		goto again // Growing the table invalidates everything, so try again
 	}
 
+	// If the keys in this bucket likely collide on purpose, rehash them
+	// with a new seed.
+	if chain >= reseedChain && canReseed(h) {
+		reseedmap(t, h)
+		hash = t.Hasher(noescape(unsafe.Pointer(&s)), uintptr(h.hash0))
+		goto again
+	}
+
 	if insertb == nil {
 		// The current bucket and all the overflow buckets connected to it are full, allocate a new one.
 		insertb = h.newoverflow(t, b)
@@ -312,7 +323,7 @@ bucketloop:
 done:
 	elem := add(unsafe.Pointer(insertb), dataOffset+abi.MapBucketCount*2*goarch.PtrSize+inserti*uintptr(t.ValueSize))
 	if h.flags&hashWriting == 0 {
-		fatal("concurrent map writes")
+		mapfatal(t, h, "concurrent map writes")
 	}
 	h.flags &^= hashWriting
 	return elem
@@ -326,8 +337,8 @@ func mapdelete_faststr(t *maptype, h *hmap, ky string) {
 	if h == nil || h.count == 0 {
 		return
 	}
-	if h.flags&hashWriting != 0 {
-		fatal("concurrent map writes")
+	if h.flags&(hashWriting|frozen) != 0 {
+		mapwriteerror(t, h)
 	}
 
 	key := stringStructOf(&ky)
@@ -335,6 +346,7 @@ func mapdelete_faststr(t *maptype, h *hmap, ky string) {
 
 	// Set hashWriting after calling t.hasher for consistency with mapdelete
 	h.flags ^= hashWriting
+	h.writer = getg().goid
 
 	bucket := hash & bucketMask(h.B)
 	if h.growing() {
@@ -393,17 +405,21 @@ search:
 			}
 		notLast:
 			h.count--
+			if debug.mapcheckiter != 0 {
+				mapcheckiterModify(h, true, hash, "a key other than the one just produced was deleted")
+			}
 			// Reset the hash seed to make it more difficult for attackers to
 			// repeatedly trigger hash collisions. See issue 25237.
 			if h.count == 0 {
-				h.hash0 = uint32(rand())
+				h.hash0 = newHashSeed()
 			}
+			shrinkIfSparse(t, h)
 			break search
 		}
 	}
 
 	if h.flags&hashWriting == 0 {
-		fatal("concurrent map writes")
+		mapfatal(t, h, "concurrent map writes")
 	}
 	h.flags &^= hashWriting
 }
@@ -9,23 +9,26 @@ import (
 	"internal/abi"
 	"internal/goarch"
 	"internal/testenv"
+	"maps"
 	"math"
 	"os"
 	"reflect"
 	"runtime"
+	"runtime/debug"
 	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 	"unsafe"
 )
 
 func TestHmapSize(t *testing.T) {
 	// The structure of hmap is defined in runtime/map.go
 	// and in cmd/compile/internal/gc/reflect.go and must be in sync.
-	// The size of hmap should be 48 bytes on 64 bit and 28 bytes on 32 bit platforms.
-	var hmapSize = uintptr(8 + 5*goarch.PtrSize)
+	// The size of hmap should be 56 bytes on 64 bit and 36 bytes on 32 bit platforms.
+	var hmapSize = uintptr(16 + 5*goarch.PtrSize)
 	if runtime.RuntimeHmapSize != hmapSize {
 		t.Errorf("sizeof(runtime.hmap{})==%d, want %d", runtime.RuntimeHmapSize, hmapSize)
 	}
@@ -1174,6 +1177,31 @@ func TestIncrementAfterBulkClearKeyStringValueInt(t *testing.T) {
 	}
 }
 
+func TestMapZeroSizeElem(t *testing.T) {
+	// A set stores no elems, so its buckets are no larger than
+	// tophash, keys and the overflow pointer.
+	want := abi.MapBucketCount + abi.MapBucketCount*unsafe.Sizeof(int64(0)) + goarch.PtrSize
+	if got := runtime.MapBucketSize(map[int64]struct{}{}); got != want {
+		t.Errorf("bucket size of map[int64]struct{} = %d, want %d", got, want)
+	}
+	if got := runtime.MapBucketSize(map[int64][0]int{}); got != want {
+		t.Errorf("bucket size of map[int64][0]int = %d, want %d", got, want)
+	}
+
+	s := map[string]struct{}{}
+	for i := range 1000 {
+		s[strconv.Itoa(i)] = struct{}{}
+	}
+	for i := range 1000 {
+		if _, ok := s[strconv.Itoa(i)]; !ok {
+			t.Fatalf("key %d missing from set", i)
+		}
+	}
+	if len(s) != 1000 {
+		t.Errorf("len(s) = %d, want 1000", len(s))
+	}
+}
+
 func TestMapTombstones(t *testing.T) {
 	m := map[int]int{}
 	const N = 10000
@@ -1199,6 +1227,379 @@ func TestMapTombstones(t *testing.T) {
 	runtime.MapTombstoneCheck(m)
 }
 
+func TestMapChurnDoesNotGrow(t *testing.T) {
+	// A FIFO workload deleting the oldest entry for each insert keeps
+	// the number of entries constant. Deleted slots are reused, and
+	// overflow buckets that accumulate are dropped by a same-size grow,
+	// so the map must not get more buckets.
+	const N = 6500 // close to the maximum load of 1024 buckets
+	m := map[int]int{}
+	for i := 0; i < N; i++ {
+		m[i] = i
+	}
+	want := runtime.MapBucketsCount(m)
+	for i := N; i < 20*N; i++ {
+		delete(m, i-N)
+		m[i] = i
+	}
+	if got := runtime.MapBucketsCount(m); got != want {
+		t.Errorf("after churn: %d buckets, want %d", got, want)
+	}
+	runtime.MapTombstoneCheck(m)
+}
+
+func TestMap16ByteKeys(t *testing.T) {
+	// Maps with 16-byte keys without pointers use the _fast128 functions.
+	type pair struct{ a, b uint64 }
+	m1 := map[[16]byte]int{}
+	m2 := map[pair]int{}
+	m3 := map[[4]uint32]int{}
+	key := func(i int) [16]byte {
+		var k [16]byte
+		k[0], k[7], k[8], k[15] = byte(i), byte(i>>8), byte(i>>16), byte(i>>24)
+		return k
+	}
+	const N = 10000
+	for i := 0; i < N; i++ {
+		m1[key(i)] = i
+		m2[pair{uint64(i), ^uint64(i)}] = i
+		m3[[4]uint32{0, uint32(i), 0, 1}] = i
+	}
+	for i := 0; i < N; i += 2 {
+		delete(m1, key(i))
+		delete(m2, pair{uint64(i), ^uint64(i)})
+		delete(m3, [4]uint32{0, uint32(i), 0, 1})
+	}
+	for i := 0; i < N; i += 4 {
+		m1[key(i)] = -i
+	}
+	if got, want := len(m1), N/2+N/4; got != want {
+		t.Errorf("len(m1) = %d, want %d", got, want)
+	}
+	for i := 0; i < N; i++ {
+		v, ok := m1[key(i)]
+		switch {
+		case i%4 == 0:
+			if !ok || v != -i {
+				t.Errorf("m1[key(%d)] = %d, %v, want %d, true", i, v, ok, -i)
+			}
+		case i%2 == 0:
+			if ok || m1[key(i)] != 0 {
+				t.Errorf("m1[key(%d)] = %d, %v, want 0, false", i, v, ok)
+			}
+		default:
+			if !ok || v != i {
+				t.Errorf("m1[key(%d)] = %d, %v, want %d, true", i, v, ok, i)
+			}
+		}
+		v, ok = m2[pair{uint64(i), ^uint64(i)}]
+		if ok != (i%2 != 0) || ok && v != i {
+			t.Errorf("m2[%d] = %d, %v", i, v, ok)
+		}
+		v, ok = m3[[4]uint32{0, uint32(i), 0, 1}]
+		if ok != (i%2 != 0) || ok && v != i {
+			t.Errorf("m3[%d] = %d, %v", i, v, ok)
+		}
+	}
+	n := 0
+	for k, v := range m2 {
+		if k.a != uint64(v) || k.b != ^uint64(v) {
+			t.Errorf("m2 iteration: %v: %d", k, v)
+		}
+		n++
+	}
+	if n != N/2 {
+		t.Errorf("m2 iteration visited %d keys, want %d", n, N/2)
+	}
+}
+
+func TestMapLargeHintDoesNotGrow(t *testing.T) {
+	for _, n := range []int{1 << 16, 1<<20 + 1, 3_000_000} {
+		if testing.Short() && n > 1<<16 {
+			continue
+		}
+		m := make(map[int]int, n)
+		want := runtime.MapBucketsCount(m)
+		for i := 0; i < n; i++ {
+			m[i] = i
+		}
+		if got := runtime.MapBucketsCount(m); got != want {
+			t.Errorf("make with hint %d: %d buckets after filling, want %d", n, got, want)
+		}
+	}
+}
+
+func TestMapGrow(t *testing.T) {
+	for _, n := range []int{0, 5, 26, 1000} {
+		m := map[int]int{}
+		for i := 0; i < n; i++ {
+			m[i] = i
+		}
+		const N = 100000
+		maps.Grow(m, N)
+		want := runtime.MapBucketsCount(m)
+		for i := n; i < n+N; i++ {
+			m[i] = i
+		}
+		if got := runtime.MapBucketsCount(m); got != want {
+			t.Errorf("n=%d: %d buckets after filling grown map, want %d", n, got, want)
+		}
+		for i := 0; i < n+N; i++ {
+			if m[i] != i {
+				t.Fatalf("n=%d: m[%d] = %d, want %d", n, i, m[i], i)
+			}
+		}
+	}
+}
+
+func TestMapGrowDuringIteration(t *testing.T) {
+	m := map[int]int{}
+	const N = 1000
+	for i := 0; i < N; i++ {
+		m[i] = i
+	}
+	seen := make(map[int]int)
+	grown := false
+	for k, v := range m {
+		if k != v {
+			t.Fatalf("iteration returned %d: %d", k, v)
+		}
+		seen[k]++
+		if !grown {
+			maps.Grow(m, 10*N)
+			grown = true
+		}
+	}
+	if len(seen) != N {
+		t.Errorf("iteration visited %d keys, want %d", len(seen), N)
+	}
+	for k, n := range seen {
+		if n != 1 {
+			t.Errorf("iteration visited key %d %d times", k, n)
+		}
+	}
+}
+
+func TestMapClip(t *testing.T) {
+	m := map[int]int{}
+	const N = 100000
+	for i := 0; i < N; i++ {
+		m[i] = i
+	}
+	big := runtime.MapBucketsCount(m)
+	// DeleteFunc iterates over m, which keeps shrinkIfSparse from
+	// shrinking it, but not Clip.
+	maps.DeleteFunc(m, func(k, _ int) bool { return k >= 100 })
+	maps.Clip(m)
+	if got, want := runtime.MapBucketsCount(m), 16; got != want {
+		t.Errorf("after Clip: %d buckets, want %d (had %d)", got, want, big)
+	}
+	for i := 0; i < N; i++ {
+		if v, ok := m[i]; ok != (i < 100) || ok && v != i {
+			t.Errorf("after Clip: m[%d] = %d, %v", i, v, ok)
+		}
+	}
+	runtime.MapTombstoneCheck(m)
+}
+
+func TestMapClipDuringIteration(t *testing.T) {
+	m := map[int]int{}
+	const N = 10000
+	for i := 0; i < N; i++ {
+		m[i] = i
+	}
+	seen := make(map[int]int)
+	clipped := false
+	for k, v := range m {
+		if k != v {
+			t.Fatalf("iteration returned %d: %d", k, v)
+		}
+		seen[k]++
+		if !clipped {
+			// Keep k and the even keys below 100, shrink the map and
+			// grow it back to its old size.
+			for i := 0; i < N; i++ {
+				if i != k && (i >= 100 || i%2 != 0) {
+					delete(m, i)
+				}
+			}
+			maps.Clip(m)
+			for i := N; i < 2*N; i++ {
+				m[i] = i
+			}
+			clipped = true
+		}
+	}
+	for k, n := range seen {
+		if n != 1 {
+			t.Errorf("iteration visited key %d %d times", k, n)
+		}
+		if _, ok := m[k]; !ok {
+			t.Errorf("iteration visited deleted key %d", k)
+		}
+	}
+	for i := 0; i < 100; i += 2 {
+		if seen[i] == 0 {
+			t.Errorf("iteration did not visit key %d", i)
+		}
+	}
+}
+
+func TestMapShrink(t *testing.T) {
+	m := map[int]int{}
+	const N = 100000
+	for i := 0; i < N; i++ {
+		m[i] = i
+	}
+	big := runtime.MapBucketsCount(m)
+	// Delete all but a few entries.
+	for i := 0; i < N; i++ {
+		if i%10000 != 0 {
+			delete(m, i)
+		}
+	}
+	if got := runtime.MapBucketsCount(m); got >= big/16 {
+		t.Errorf("after deleting most entries: %d buckets, want less than %d", got, big/16)
+	}
+	runtime.MapTombstoneCheck(m)
+	if len(m) != N/10000 {
+		t.Errorf("len(m) = %d, want %d", len(m), N/10000)
+	}
+	for i := 0; i < N; i += 10000 {
+		if m[i] != i {
+			t.Errorf("m[%d] = %d, want %d", i, m[i], i)
+		}
+	}
+
+	// A map that is being iterated over must not shrink.
+	for i := 0; i < N; i++ {
+		m[i] = i
+	}
+	big = runtime.MapBucketsCount(m)
+	for k := range m {
+		if k >= 0 { // not the map clearing idiom
+			delete(m, k)
+		}
+	}
+	if got := runtime.MapBucketsCount(m); got != big {
+		t.Errorf("after deleting all entries while iterating: %d buckets, want %d", got, big)
+	}
+}
+
+func TestMapClearReleasesBuckets(t *testing.T) {
+	// A large map that holds few entries gets buckets for as many.
+	m := make(map[int]int, 100000)
+	for i := 0; i < 100; i++ {
+		m[i] = i
+	}
+	clear(m)
+	if got := runtime.MapBucketsCount(m); got != 16 {
+		t.Errorf("after clear: %d buckets, want 16", got)
+	}
+	if len(m) != 0 {
+		t.Errorf("after clear: len(m) = %d, want 0", len(m))
+	}
+	m[1] = 1
+	if len(m) != 1 || m[1] != 1 {
+		t.Errorf("insert after clear failed: %v", m)
+	}
+
+	// A full map keeps its buckets, so that refilling it does not
+	// grow it again.
+	m = map[int]int{}
+	for i := 0; i < 100000; i++ {
+		m[i] = i
+	}
+	full := runtime.MapBucketsCount(m)
+	clear(m)
+	if got := runtime.MapBucketsCount(m); got != full {
+		t.Errorf("after clear of full map: %d buckets, want %d", got, full)
+	}
+
+	// Small maps keep their buckets.
+	m = make(map[int]int, 100)
+	big := runtime.MapBucketsCount(m)
+	m[1] = 1
+	clear(m)
+	if got := runtime.MapBucketsCount(m); got != big {
+		t.Errorf("after clear of small map: %d buckets, want %d", got, big)
+	}
+}
+
+func TestMapClearKeepsBuckets(t *testing.T) {
+	// A map that was never iterated is cleared without marking its
+	// buckets, including overflow buckets, one by one.
+	m := make(map[int64]float64, 1000)
+	for i := range int64(1000) {
+		m[i] = float64(i)
+	}
+	for range 3 {
+		clear(m)
+		if len(m) != 0 {
+			t.Fatalf("after clear: len(m) = %d, want 0", len(m))
+		}
+		for i := range int64(2000) {
+			if _, ok := m[i]; ok {
+				t.Fatalf("after clear: found key %d", i)
+			}
+		}
+		for i := range int64(1000) {
+			m[i+1000] = float64(i)
+		}
+		if len(m) != 1000 || m[1500] != 500 {
+			t.Fatalf("insert after clear failed: len(m) = %d, m[1500] = %v", len(m), m[1500])
+		}
+	}
+
+	// Clearing a map during iteration ends the iteration (issue 59411).
+	n := 0
+	for range m {
+		n++
+		clear(m)
+	}
+	if n != 1 {
+		t.Errorf("range over map cleared on the first iteration ran %d times, want 1", n)
+	}
+}
+
+func TestMapShrinkTypes(t *testing.T) {
+	type big [200]byte // stored indirectly
+	const N = 10000
+	ms := map[string]big{}
+	mb := map[big]string{}
+	for i := 0; i < N; i++ {
+		s := strconv.Itoa(i)
+		var b big
+		copy(b[:], s)
+		ms[s] = b
+		mb[b] = s
+	}
+	for i := 0; i < N; i++ {
+		if i%100 == 0 {
+			continue
+		}
+		s := strconv.Itoa(i)
+		var b big
+		copy(b[:], s)
+		delete(ms, s)
+		delete(mb, b)
+	}
+	if len(ms) != N/100 || len(mb) != N/100 {
+		t.Fatalf("len(ms), len(mb) = %d, %d, want %d", len(ms), len(mb), N/100)
+	}
+	for i := 0; i < N; i += 100 {
+		s := strconv.Itoa(i)
+		var b big
+		copy(b[:], s)
+		if got := ms[s]; got != b {
+			t.Errorf("ms[%q] = %q, want %q", s, got[:len(s)], s)
+		}
+		if mb[b] != s {
+			t.Errorf("mb[%q] = %q, want %q", s, mb[b], s)
+		}
+	}
+}
+
 type canString int
 
 func (c canString) String() string {
@@ -1437,6 +1838,460 @@ func TestLoadFactor(t *testing.T) {
 	}
 }
 
+func TestMapLoadFactorGODEBUG(t *testing.T) {
+	if os.Getenv("TEST_MAPLOADFACTOR") != "1" {
+		testenv.MustHaveExec(t)
+		cmd := testenv.CleanCmdEnv(testenv.Command(t, os.Args[0], "-test.run=^TestMapLoadFactorGODEBUG$"))
+		cmd.Env = append(cmd.Env, "TEST_MAPLOADFACTOR=1", "GODEBUG=maploadfactor=8")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%v\n%s", err, out)
+		}
+		return
+	}
+	// With maploadfactor=8, buckets are at most half full on average.
+	for b := uint8(1); b < 20; b++ {
+		count := 4 << b
+		if runtime.OverLoadFactor(count, b) {
+			t.Errorf("OverLoadFactor(%d,%d)=true, want false", count, b)
+		}
+		if !runtime.OverLoadFactor(count+1, b) {
+			t.Errorf("OverLoadFactor(%d,%d)=false, want true", count+1, b)
+		}
+	}
+}
+
+func TestMapCheckGODEBUG(t *testing.T) {
+	if os.Getenv("TEST_MAPCHECK") != "1" {
+		testenv.MustHaveExec(t)
+		cmd := testenv.CleanCmdEnv(testenv.Command(t, os.Args[0], "-test.run=^TestMapCheckGODEBUG$"))
+		cmd.Env = append(cmd.Env, "TEST_MAPCHECK=1", "GODEBUG=mapcheck=1")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%v\n%s", err, out)
+		}
+		return
+	}
+	const N = 1000
+
+	// Check in the middle of growth, with and without an iterator
+	// holding the old buckets.
+	m := map[int]int{}
+	for i := 0; i < N; i++ {
+		m[i] = i
+		runtime.MapCheck(m)
+	}
+	for k := range m {
+		m[k+N] = k
+		runtime.MapCheck(m)
+	}
+	for i := 0; i < 2*N; i += 2 {
+		delete(m, i)
+		runtime.MapCheck(m)
+	}
+
+	// NaN keys can be in any bucket.
+	mf := map[float64]int{}
+	for i := 0; i < N; i++ {
+		mf[math.NaN()] = i
+		mf[float64(i)] = i
+	}
+	for range mf {
+		mf[math.NaN()] = 0
+	}
+	runtime.MapCheck(mf)
+
+	// Indirect keys and elems, and string keys.
+	mb := map[[200]byte][200]byte{}
+	ms := map[string]int{}
+	for i := 0; i < N; i++ {
+		mb[[200]byte{byte(i), byte(i >> 8)}] = [200]byte{}
+		ms[strconv.Itoa(i)] = i
+	}
+	runtime.MapCheck(mb)
+	runtime.MapCheck(ms)
+
+	// Resizing.
+	maps.Grow(ms, 10*N)
+	maps.DeleteFunc(ms, func(k string, v int) bool { return v > 10 })
+	runtime.MapCheck(ms)
+	maps.Freeze(ms)
+	runtime.MapCheck(ms)
+}
+
+func TestMapSeedGODEBUG(t *testing.T) {
+	if os.Getenv("TEST_MAPSEED") == "1" {
+		m := map[int]int{}
+		fmt.Println(runtime.MapHashSeed(m), runtime.StringHash("hello", 0))
+		return
+	}
+	switch runtime.GOOS {
+	case "aix", "darwin", "ios", "dragonfly", "freebsd", "netbsd", "openbsd", "illumos", "solaris", "linux":
+	default:
+		t.Skipf("GODEBUG=mapseed not supported on %s", runtime.GOOS)
+	}
+	testenv.MustHaveExec(t)
+	run := func(godebug string) string {
+		cmd := testenv.CleanCmdEnv(testenv.Command(t, os.Args[0], "-test.run=^TestMapSeedGODEBUG$"))
+		cmd.Env = append(cmd.Env, "TEST_MAPSEED=1", "GODEBUG="+godebug)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%v\n%s", err, out)
+		}
+		line, _, _ := strings.Cut(string(out), "\n")
+		return line
+	}
+	a, b := run("mapseed=42"), run("mapseed=1,mapseed=42")
+	if a != b {
+		t.Errorf("hash seeds differ between runs with GODEBUG=mapseed=42: %q, %q", a, b)
+	}
+	if c := run("mapseed=43"); c == a {
+		t.Errorf("hash seeds with GODEBUG=mapseed=43 are the same as with mapseed=42: %q", c)
+	}
+}
+
+func TestMapOrderPinned(t *testing.T) {
+	if os.Getenv("TEST_MAPORDER") == "1" {
+		order := func(seed uint64) string {
+			if !runtime.SetMapOrder(seed) {
+				return "not pinned"
+			}
+			m := map[int]int{}
+			for i := range 100 {
+				m[i] = i
+			}
+			var b []byte
+			for k := range m {
+				b = strconv.AppendInt(b, int64(k), 10)
+				b = append(b, ' ')
+			}
+			return string(b)
+		}
+		fmt.Println(order(1))
+		fmt.Println(order(1))
+		fmt.Println(order(2))
+		return
+	}
+	switch runtime.GOOS {
+	case "aix", "darwin", "ios", "dragonfly", "freebsd", "netbsd", "openbsd", "illumos", "solaris", "linux":
+	default:
+		t.Skipf("GODEBUG=mapseed not supported on %s", runtime.GOOS)
+	}
+	testenv.MustHaveExec(t)
+	run := func(godebug string) []string {
+		cmd := testenv.CleanCmdEnv(testenv.Command(t, os.Args[0], "-test.run=^TestMapOrderPinned$"))
+		cmd.Env = append(cmd.Env, "TEST_MAPORDER=1", "GODEBUG="+godebug)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%v\n%s", err, out)
+		}
+		return strings.SplitN(string(out), "\n", 4)[:3]
+	}
+	a := run("mapseed=42")
+	if a[0] != a[1] {
+		t.Errorf("map iteration orders differ after pinning with the same seed:\n%s\n%s", a[0], a[1])
+	}
+	if a[0] == a[2] {
+		t.Errorf("map iteration orders are the same after pinning with different seeds:\n%s", a[0])
+	}
+	if b := run("mapseed=42"); !slices.Equal(a, b) {
+		t.Errorf("map iteration orders differ between runs with GODEBUG=mapseed=42:\n%q\n%q", a, b)
+	}
+	if c := run(""); c[0] != "not pinned" {
+		t.Errorf("map iteration order was pinned without GODEBUG=mapseed: %q", c[0])
+	}
+}
+
+func TestMapTraceGODEBUG(t *testing.T) {
+	if os.Getenv("TEST_MAPTRACE") == "1" {
+		m := map[int]int{}
+		for i := range 100 {
+			m[i] = i
+		}
+		runtime.KeepAlive(m)
+		return
+	}
+	testenv.MustHaveExec(t)
+	cmd := testenv.CleanCmdEnv(testenv.Command(t, os.Args[0], "-test.run=^TestMapTraceGODEBUG$"))
+	cmd.Env = append(cmd.Env, "TEST_MAPTRACE=1", "GODEBUG=maptrace=2")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+	want := "maptrace: grow map[int]int len 13: 2 -> 4 buckets, 576 bytes\n"
+	if goarch.PtrSize == 4 {
+		want = "maptrace: grow map[int]int len 13: 2 -> 4 buckets, 304 bytes\n"
+	}
+	i := strings.Index(string(out), want)
+	if i < 0 {
+		t.Fatalf("output does not contain %q:\n%s", want, out)
+	}
+	if !strings.Contains(string(out[i:]), "runtime_test.TestMapTraceGODEBUG") {
+		t.Errorf("output does not contain stack of growing goroutine:\n%s", out)
+	}
+}
+
+func TestMapCheckIterGODEBUG(t *testing.T) {
+	tests := map[string]func(m map[int]int){
+		"deleteCurrent": func(m map[int]int) {
+			for k := range m {
+				delete(m, k)
+			}
+		},
+		"update": func(m map[int]int) {
+			for k := range m {
+				m[k]++
+			}
+		},
+		"deleteOther": func(m map[int]int) {
+			for k := range m {
+				delete(m, k+1)
+				delete(m, k-1)
+			}
+		},
+		"grow": func(m map[int]int) {
+			for k := range m {
+				m[k+1000] = k
+			}
+		},
+		"clear": func(m map[int]int) {
+			for range m {
+				clear(m)
+			}
+		},
+	}
+	if name := os.Getenv("TEST_MAPCHECKITER"); name != "" {
+		m := map[int]int{}
+		for i := range 100 {
+			m[i] = i
+		}
+		tests[name](m)
+		return
+	}
+	testenv.MustHaveExec(t)
+	for name := range tests {
+		t.Run(name, func(t *testing.T) {
+			cmd := testenv.CleanCmdEnv(testenv.Command(t, os.Args[0], "-test.run=^TestMapCheckIterGODEBUG$"))
+			cmd.Env = append(cmd.Env, "TEST_MAPCHECKITER="+name, "GODEBUG=mapcheckiter=1")
+			out, err := cmd.CombinedOutput()
+			wantPanic := name != "deleteCurrent" && name != "update"
+			if got := strings.Contains(string(out), "panic: map modified during iteration"); got != wantPanic {
+				t.Errorf("panicked: %v, want %v (err %v)\n%s", got, wantPanic, err, out)
+			}
+		})
+	}
+}
+
+func TestMapAllocGODEBUG(t *testing.T) {
+	if os.Getenv("TEST_MAPALLOC") != "1" {
+		testenv.MustHaveExec(t)
+		for _, godebug := range []string{"mapprefault=1", "maphugepage=1", "maphugepage=1,mapprefault=1"} {
+			cmd := testenv.CleanCmdEnv(testenv.Command(t, os.Args[0], "-test.run=^TestMapAllocGODEBUG$"))
+			cmd.Env = append(cmd.Env, "TEST_MAPALLOC=1", "GODEBUG="+godebug)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("GODEBUG=%s: %v\n%s", godebug, err, out)
+			}
+		}
+		return
+	}
+	const N = 100000
+	m := make(map[int][16]byte, N)
+	m2 := map[int]int{}
+	maps.Grow(m2, N)
+	for i := 0; i < 2*N; i++ {
+		m[i] = [16]byte{byte(i)}
+		m2[i] = i
+	}
+	for i := 0; i < 2*N; i++ {
+		if m[i][0] != byte(i) || m2[i] != i {
+			t.Fatalf("m[%d] = %v, m2[%d] = %d", i, m[i], i, m2[i])
+		}
+	}
+}
+
+func TestMapReuseGODEBUG(t *testing.T) {
+	if os.Getenv("TEST_MAPREUSE") != "1" {
+		testenv.MustHaveExec(t)
+		cmd := testenv.CleanCmdEnv(testenv.Command(t, os.Args[0], "-test.run=^TestMapReuseGODEBUG$"))
+		cmd.Env = append(cmd.Env, "TEST_MAPREUSE=1", "GODEBUG=mapreuse=1,mapcheck=1")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%v\n%s", err, out)
+		}
+		return
+	}
+
+	// Churn at constant size close to the maximum load to accumulate
+	// overflow buckets, causing same-size grows, which should alternate
+	// between two bucket arrays. Disable the GC so that the allocator
+	// cannot hand out the same memory again.
+	defer debug.SetGCPercent(debug.SetGCPercent(-1))
+	const N = 6500
+	m := map[int]int{}
+	for i := 0; i < N; i++ {
+		m[i] = i
+	}
+	B := runtime.MapBucketsCount(m)
+	buckets := map[uintptr]bool{runtime.MapBuckets(m): true}
+	changes := 0
+	for i := N; i < 200*N; i++ {
+		delete(m, i-N)
+		prev := runtime.MapBuckets(m)
+		m[i] = i
+		if p := runtime.MapBuckets(m); p != prev {
+			buckets[p] = true
+			changes++
+		}
+		if i == 100*N {
+			// Iterate while modifying the map, so that the next
+			// growth must not reuse the old buckets.
+			for k, v := range m {
+				if k != v {
+					t.Fatalf("m[%d] = %d", k, v)
+				}
+				if k%10 == 0 {
+					delete(m, k)
+					m[k] = k
+				}
+			}
+		}
+	}
+	if n := runtime.MapBucketsCount(m); n != B {
+		t.Fatalf("map has %d buckets, want %d", n, B)
+	}
+	if changes < 10 {
+		t.Fatalf("map was rehashed %d times, want at least 10", changes)
+	}
+	// The map alternates between two arrays, except that the growth
+	// after the iteration cannot reuse one. Allow one more for slack.
+	if len(buckets) > 4 {
+		t.Errorf("map used %d bucket arrays for %d rehashes, want at most 4", len(buckets), changes)
+	}
+	for i := 199 * N; i < 200*N; i++ {
+		if m[i] != i {
+			t.Fatalf("m[%d] = %d, want %d", i, m[i], i)
+		}
+	}
+}
+
+func TestMapReuseCollectGODEBUG(t *testing.T) {
+	if os.Getenv("TEST_MAPREUSE") != "1" {
+		testenv.MustHaveExec(t)
+		cmd := testenv.CleanCmdEnv(testenv.Command(t, os.Args[0], "-test.run=^TestMapReuseCollectGODEBUG$"))
+		cmd.Env = append(cmd.Env, "TEST_MAPREUSE=1", "GODEBUG=mapreuse=1,mapcheck=1")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%v\n%s", err, out)
+		}
+		return
+	}
+
+	// Put keys in a preallocated overflow bucket of a map with 16
+	// buckets, grow the map once so that it keeps that bucket array,
+	// and check that the keys are collected once deleted.
+	type key [16]int64
+	const N = 12
+	m := make(map[*key]int, 100)
+	collected := make(chan bool, N)
+	var fill []*key
+	func() {
+		var keys []*key
+		for len(keys) < N {
+			k := new(key)
+			if runtime.MapHash(m, unsafe.Pointer(&k))&15 != 0 {
+				fill = append(fill, k)
+				continue
+			}
+			runtime.SetFinalizer(k, func(*key) { collected <- true })
+			keys = append(keys, k)
+			m[k] = 0
+		}
+		if n := runtime.MapLongestChain(m); n == 0 {
+			t.Fatalf("no overflow buckets")
+		}
+		// Grow to 32 buckets, which is enough for 200 keys.
+		for len(fill) < 200-N {
+			fill = append(fill, new(key))
+		}
+		for _, k := range fill[:200-N] {
+			m[k] = 0
+		}
+		for _, k := range keys {
+			delete(m, k)
+		}
+	}()
+	for i := 0; i < N; i++ {
+		for j := 0; ; j++ {
+			runtime.GC()
+			select {
+			case <-collected:
+			case <-time.After(10 * time.Millisecond):
+				if j < 100 {
+					continue
+				}
+				t.Fatalf("%d of %d deleted keys were not collected", N-i, N)
+			}
+			break
+		}
+	}
+	runtime.KeepAlive(m)
+}
+
+func TestMapReseed(t *testing.T) {
+	t.Run("generic", func(t *testing.T) {
+		testMapReseed(t, func(i, j int64) [3]int64 { return [3]int64{i, j} })
+	})
+	t.Run("fast128", func(t *testing.T) {
+		testMapReseed(t, func(i, j int64) [2]int64 { return [2]int64{i, j} })
+	})
+	t.Run("faststr", func(t *testing.T) {
+		testMapReseed(t, func(i, j int64) string { return fmt.Sprint(i, j) })
+	})
+}
+
+// testMapReseed checks that a map of keys chosen to collide is reseeded
+// once. key must return distinct keys for distinct arguments.
+func testMapReseed[K comparable](t *testing.T, key func(i, j int64) K) {
+	m := make(map[K]int)
+	hash := func(k K) uintptr { return runtime.MapHash(m, unsafe.Pointer(&k)) }
+	// collisions returns n keys whose hashes agree in their low 12 bits,
+	// so that they share a bucket until the map has 4096 buckets.
+	collisions := func(n int) []K {
+		var keys []K
+		for i := int64(0); len(keys) < n; i++ {
+			if k := key(i, int64(n)); hash(k)&(1<<12-1) == 0 {
+				keys = append(keys, k)
+			}
+		}
+		return keys
+	}
+
+	keys := collisions(200)
+	before := hash(keys[0])
+	for i, k := range keys {
+		m[k] = i
+	}
+	if hash(keys[0]) == before {
+		t.Fatalf("map with colliding keys was not reseeded")
+	}
+	if n := runtime.MapLongestChain(m); n >= 8 {
+		t.Errorf("map has a chain of %d overflow buckets after reseeding", n)
+	}
+	for i, k := range keys {
+		if v, ok := m[k]; !ok || v != i {
+			t.Fatalf("m[%v] = %d, %v, want %d, true", k, v, ok, i)
+		}
+	}
+
+	// Keys that collide with the new seed too are not rehashed again.
+	before = hash(keys[0])
+	for i, k := range collisions(100) {
+		m[k] = i
+	}
+	if hash(keys[0]) != before {
+		t.Errorf("map was reseeded twice")
+	}
+	if len(m) != 300 {
+		t.Errorf("len(m) = %d, want 300", len(m))
+	}
+}
+
 func TestMapKeys(t *testing.T) {
 	type key struct {
 		s   string
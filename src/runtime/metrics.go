@@ -327,6 +327,24 @@ func initMetrics() {
 				out.scalar = uint64(startingStackSize)
 			},
 		},
+		"/maps/grows:events": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = mapStats.grows.Load()
+			},
+		},
+		"/maps/same-size-grows:events": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = mapStats.sameSizeGrows.Load()
+			},
+		},
+		"/maps/shrinks:events": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = mapStats.shrinks.Load()
+			},
+		},
 		"/memory/classes/heap/free:bytes": {
 			deps: makeStatDepSet(heapStatsDep),
 			compute: func(in *statAggregate, out *metricValue) {
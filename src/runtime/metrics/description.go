@@ -319,6 +319,24 @@ var allDesc = []Description{
 		Kind:        KindUint64,
 		Cumulative:  false,
 	},
+	{
+		Name:        "/maps/grows:events",
+		Description: "Count of times a map increased its number of buckets, because it was full or in maps.Grow.",
+		Kind:        KindUint64,
+		Cumulative:  true,
+	},
+	{
+		Name:        "/maps/same-size-grows:events",
+		Description: "Count of times a map was rewritten with the same number of buckets to drop overflow buckets left by deletions.",
+		Kind:        KindUint64,
+		Cumulative:  true,
+	},
+	{
+		Name:        "/maps/shrinks:events",
+		Description: "Count of times a map released most of its buckets after deletions, a clear, or in maps.Clip.",
+		Kind:        KindUint64,
+		Cumulative:  true,
+	},
 	{
 		Name: "/memory/classes/heap/free:bytes",
 		Description: "Memory that is completely free and eligible to be returned to the underlying system, " +
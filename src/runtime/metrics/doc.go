@@ -368,6 +368,18 @@ Below is the full list of supported metrics, ordered lexicographically.
 		package due to a non-default GODEBUG=zipinsecurepath=...
 		setting.
 
+	/maps/grows:events
+		Count of times a map increased its number of buckets, because it
+		was full or in maps.Grow.
+
+	/maps/same-size-grows:events
+		Count of times a map was rewritten with the same number of
+		buckets to drop overflow buckets left by deletions.
+
+	/maps/shrinks:events
+		Count of times a map released most of its buckets after
+		deletions, a clear, or in maps.Clip.
+
 	/memory/classes/heap/free:bytes
 		Memory that is completely free and eligible to be returned to
 		the underlying system, but has not been. This metric is the
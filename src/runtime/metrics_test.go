@@ -1380,3 +1380,50 @@ func TestMetricHeapUnusedLargeObjectOverflow(t *testing.T) {
 	done <- struct{}{}
 	wg.Wait()
 }
+
+func TestMapMetrics(t *testing.T) {
+	s := []metrics.Sample{
+		{Name: "/maps/grows:events"},
+		{Name: "/maps/same-size-grows:events"},
+		{Name: "/maps/shrinks:events"},
+	}
+	read := func() (grows, sameSizeGrows, shrinks uint64) {
+		metrics.Read(s)
+		return s[0].Value.Uint64(), s[1].Value.Uint64(), s[2].Value.Uint64()
+	}
+	grows0, sameSizeGrows0, shrinks0 := read()
+
+	m := map[int]int{}
+	for i := 0; i < 100000; i++ {
+		m[i] = i
+	}
+
+	// Clearing a large map that holds few entries shrinks it.
+	sparse := make(map[int]int, 100000)
+	sparse[0] = 0
+	clear(sparse)
+
+	// Churn at constant size close to the maximum load to accumulate
+	// overflow buckets, causing same-size grows.
+	const N = 6500
+	m = map[int]int{}
+	for i := 0; i < N; i++ {
+		m[i] = i
+	}
+	for i := N; i < 20*N; i++ {
+		delete(m, i-N)
+		m[i] = i
+	}
+
+	grows, sameSizeGrows, shrinks := read()
+	// Growing to 100000 entries doubles the buckets at least 10 times.
+	if grows-grows0 < 10 {
+		t.Errorf("/maps/grows:events increased by %d, want at least 10", grows-grows0)
+	}
+	if sameSizeGrows == sameSizeGrows0 {
+		t.Errorf("/maps/same-size-grows:events did not increase")
+	}
+	if shrinks == shrinks0 {
+		t.Errorf("/maps/shrinks:events did not increase after clear")
+	}
+}
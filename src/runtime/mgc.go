@@ -1703,6 +1703,7 @@ func gcResetMarkState() {
 var poolcleanup func()
 var boringCaches []unsafe.Pointer  // for crypto/internal/boring
 var uniqueMapCleanup chan struct{} // for unique
+var weakMapCleanup chan struct{}   // for internal/weak
 
 // sync_runtime_registerPoolCleanup should be an internal detail,
 // but widely used packages access it using linkname.
@@ -1725,14 +1726,26 @@ func boring_registerCache(p unsafe.Pointer) {
 
 //go:linkname unique_runtime_registerUniqueMapCleanup unique.runtime_registerUniqueMapCleanup
 func unique_runtime_registerUniqueMapCleanup(f func()) {
+	uniqueMapCleanup = startMapCleanup(f)
+}
+
+//go:linkname weak_runtime_registerMapCleanup internal/weak.runtime_registerMapCleanup
+func weak_runtime_registerMapCleanup(f func()) {
+	weakMapCleanup = startMapCleanup(f)
+}
+
+// startMapCleanup starts a goroutine that calls cleanup each time
+// a value is sent on the returned channel.
+func startMapCleanup(cleanup func()) chan struct{} {
 	// Start the goroutine in the runtime so it's counted as a system goroutine.
-	uniqueMapCleanup = make(chan struct{}, 1)
-	go func(cleanup func()) {
+	c := make(chan struct{}, 1)
+	go func() {
 		for {
-			<-uniqueMapCleanup
+			<-c
 			cleanup()
 		}
-	}(f)
+	}()
+	return c
 }
 
 func clearpools() {
@@ -1754,6 +1767,14 @@ func clearpools() {
 		}
 	}
 
+	// clear dead keys from weak maps
+	if weakMapCleanup != nil {
+		select {
+		case weakMapCleanup <- struct{}{}:
+		default:
+		}
+	}
+
 	// Clear central sudog cache.
 	// Leave per-P caches alone, they have strictly bounded size.
 	// Disconnect cached list before dropping it on the floor,
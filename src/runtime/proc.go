@@ -819,7 +819,7 @@ func schedinit() {
 	godebug := getGodebugEarly()
 	cpuinit(godebug) // must run before alginit
 	randinit()       // must run before alginit, mcommoninit
-	alginit()        // maps, hash, rand must not be used before this call
+	alginit(godebug) // maps, hash, rand must not be used before this call
 	mcommoninit(gp.m, -1)
 	modulesinit()   // provides activeModules
 	typelinksinit() // uses maps, activeModules
@@ -4290,6 +4290,7 @@ func gdestroy(gp *g) {
 	gp.param = nil
 	gp.labels = nil
 	gp.timer = nil
+	gp.mapIters = nil
 
 	if gcBlackenEnabled != 0 && gp.gcAssistBytes > 0 {
 		// Flush assist credit to the global pool. This gives
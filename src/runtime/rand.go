@@ -114,11 +114,13 @@ func bootstrapRandReseed() {
 	unlock(&globalRand.lock)
 }
 
-// rand32 is uint32(rand()), called from compiler-generated code.
+// rand32 is called from compiler-generated code to choose the hash seed
+// of a map allocated on the stack. It is uint32(rand()) unless
+// GODEBUG=mapseed is set; see newHashSeed.
 //
 //go:nosplit
 func rand32() uint32 {
-	return uint32(rand())
+	return newHashSeed()
 }
 
 // rand returns a random uint64 from the per-m chacha8 state.
@@ -319,6 +319,14 @@ var debug struct {
 	gctrace                  int32
 	invalidptr               int32
 	madvdontneed             int32 // for Linux; issue 28466
+	mapcheck                 int32
+	mapcheckiter             int32
+	mapclearshrink           int32
+	maphugepage              int32
+	maploadfactor            int32
+	mapprefault              int32
+	mapreuse                 int32
+	maptrace                 int32
 	runtimeContentionStacks  atomic.Int32
 	scavtrace                int32
 	scheddetail              int32
@@ -379,6 +387,14 @@ var dbgvars = []*dbgVar{
 	{name: "inittrace", value: &debug.inittrace},
 	{name: "invalidptr", value: &debug.invalidptr},
 	{name: "madvdontneed", value: &debug.madvdontneed},
+	{name: "mapcheck", value: &debug.mapcheck},
+	{name: "mapcheckiter", value: &debug.mapcheckiter},
+	{name: "mapclearshrink", value: &debug.mapclearshrink, def: 10},
+	{name: "maphugepage", value: &debug.maphugepage},
+	{name: "maploadfactor", value: &debug.maploadfactor},
+	{name: "mapprefault", value: &debug.mapprefault},
+	{name: "mapreuse", value: &debug.mapreuse},
+	{name: "maptrace", value: &debug.maptrace},
 	{name: "panicnil", atomic: &debug.panicnil},
 	{name: "profstackdepth", value: &debug.profstackdepth, def: 128},
 	{name: "runtimecontentionstacks", atomic: &debug.runtimeContentionStacks},
@@ -436,6 +452,7 @@ func parsedebugvars() {
 	parsegodebug(godebug, nil)
 
 	debug.malloc = (debug.inittrace | debug.sbrk) != 0
+	setMapLoadFactor(debug.maploadfactor)
 	debug.profstackdepth = min(debug.profstackdepth, maxProfStackDepth)
 
 	setTraceback(gogetenv("GOTRACEBACK"))
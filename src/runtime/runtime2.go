@@ -516,6 +516,8 @@ type g struct {
 
 	coroarg *coro // argument during coroutine transfers
 
+	mapIters *mapIterChecks // map iterations started, for GODEBUG=mapcheckiter
+
 	// Per-G tracer state.
 	trace gTraceState
 
@@ -152,7 +152,7 @@ func reflect_memmove(to, from unsafe.Pointer, n uintptr) {
 }
 
 // exported value for testing
-const hashLoad = float32(loadFactorNum) / float32(loadFactorDen)
+const hashLoad = float32(defaultLoadFactorNum) / float32(loadFactorDen)
 
 // in internal/bytealg/equal_*.s
 //
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"sync"
+	"sync/atomic"
 )
 
 type httpPkg struct{}
@@ -111,3 +112,36 @@ func ExampleOnceValues() {
 	// Output:
 	// Reading file once
 }
+
+// This example uses a HashTrieMap as a cache shared by several
+// goroutines. The map holds a function made by OnceValue for each key,
+// so that the value is computed only once, however many goroutines ask
+// for it at the same time. Storing the value itself with LoadOrStore
+// would not be enough: each goroutine that misses would compute it, and
+// all but the first result would be discarded.
+func ExampleHashTrieMap() {
+	var computed atomic.Int32
+	var squares sync.HashTrieMap[int, func() int]
+	square := func(i int) int {
+		f, _ := squares.LoadOrStore(i, sync.OnceValue(func() int {
+			computed.Add(1)
+			return i * i
+		}))
+		return f()
+	}
+
+	var sums [4]int
+	var wg sync.WaitGroup
+	for g := range sums {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 10 {
+				sums[g] += square(i)
+			}
+		}()
+	}
+	wg.Wait()
+	fmt.Println(sums, computed.Load())
+	// Output: [285 285 285 285] 10
+}
@@ -4,6 +4,8 @@
 
 package sync
 
+import "unsafe"
+
 // Export for testing.
 var Runtime_Semacquire = runtime_Semacquire
 var Runtime_Semrelease = runtime_Semrelease
@@ -55,3 +57,12 @@ func (c *poolChain) PopHead() (any, bool) {
 func (c *poolChain) PopTail() (any, bool) {
 	return c.popTail()
 }
+
+// SetBadHashTrieMapHash replaces m's hash function with one that
+// maps every key to the same hash.
+func SetBadHashTrieMapHash[K comparable, V any](m *HashTrieMap[K, V]) {
+	m.init()
+	m.keyHash = func(_ unsafe.Pointer, _ uintptr) uintptr {
+		return 0
+	}
+}
@@ -0,0 +1,654 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync
+
+import (
+	"internal/abi"
+	"internal/goarch"
+	"sync/atomic"
+	"unsafe"
+)
+
+// HashTrieMap is like a Go map[K]V but is safe for concurrent use by
+// multiple goroutines without additional locking or coordination.
+//
+// HashTrieMap is a concurrent hash-trie. Loads never block and take no
+// locks. Stores and deletes lock only the trie node holding the key,
+// so writers to different parts of the map do not contend. Compared to
+// [Map], it is type-safe, it does not need to copy its contents as
+// writes accumulate, and it performs better for workloads with many
+// writes to overlapping sets of keys.
+//
+// The zero HashTrieMap is empty and ready for use. A HashTrieMap must
+// not be copied after first use.
+//
+// In the terminology of [the Go memory model], HashTrieMap arranges that
+// a write operation “synchronizes before” any read operation that
+// observes the effect of the write, where read and write operations are
+// defined as for [Map], and [HashTrieMap.Clear] is a write operation.
+//
+// [the Go memory model]: https://go.dev/ref/mem
+type HashTrieMap[K comparable, V any] struct {
+	inited   atomic.Uint32
+	initMu   Mutex
+	root     atomic.Pointer[trieIndirect[K, V]]
+	keyHash  hashFunc
+	keyEqual equalFunc
+	valEqual equalFunc // nil if V is not comparable
+	seed     uintptr
+}
+
+type hashFunc func(unsafe.Pointer, uintptr) uintptr
+type equalFunc func(unsafe.Pointer, unsafe.Pointer) bool
+
+// Provided by runtime via linkname.
+//
+//go:linkname runtime_rand runtime.rand
+func runtime_rand() uint64
+
+func (ht *HashTrieMap[K, V]) init() {
+	if ht.inited.Load() == 0 {
+		ht.initSlow()
+	}
+}
+
+//go:noinline
+func (ht *HashTrieMap[K, V]) initSlow() {
+	ht.initMu.Lock()
+	defer ht.initMu.Unlock()
+
+	if ht.inited.Load() != 0 {
+		// Someone got to it while we were waiting.
+		return
+	}
+
+	// Use the hash and equality functions of the builtin map type.
+	var m map[K]V
+	mapType := abi.TypeOf(m).MapType()
+	ht.root.Store(newTrieIndirect[K, V](nil))
+	ht.keyHash = mapType.Hasher
+	ht.keyEqual = mapType.Key.Equal
+	ht.valEqual = mapType.Elem.Equal
+	ht.seed = uintptr(runtime_rand())
+
+	ht.inited.Store(1)
+}
+
+// Load returns the value stored in the map for a key, or the zero value
+// if no value is present.
+// The ok result indicates whether value was found in the map.
+func (ht *HashTrieMap[K, V]) Load(key K) (value V, ok bool) {
+	ht.init()
+	hash := ht.keyHash(abi.NoEscape(unsafe.Pointer(&key)), ht.seed)
+
+	i := ht.root.Load()
+	hashShift := 8 * goarch.PtrSize
+	for hashShift != 0 {
+		hashShift -= nChildrenLog2
+
+		n := i.children[(hash>>hashShift)&nChildrenMask].Load()
+		if n == nil {
+			return *new(V), false
+		}
+		if n.isEntry {
+			return n.entry().lookup(key, ht.keyEqual)
+		}
+		i = n.indirect()
+	}
+	panic("sync.HashTrieMap: ran out of hash bits while iterating")
+}
+
+// LoadOrStore returns the existing value for the key if present.
+// Otherwise, it stores and returns the given value.
+// The loaded result is true if the value was loaded, false if stored.
+func (ht *HashTrieMap[K, V]) LoadOrStore(key K, value V) (result V, loaded bool) {
+	ht.init()
+	hash := ht.keyHash(abi.NoEscape(unsafe.Pointer(&key)), ht.seed)
+	var i *trieIndirect[K, V]
+	var hashShift uint
+	var slot *atomic.Pointer[trieNode[K, V]]
+	var n *trieNode[K, V]
+	for {
+		// Find the key or a candidate location for insertion.
+		i = ht.root.Load()
+		hashShift = 8 * goarch.PtrSize
+		haveInsertPoint := false
+		for hashShift != 0 {
+			hashShift -= nChildrenLog2
+
+			slot = &i.children[(hash>>hashShift)&nChildrenMask]
+			n = slot.Load()
+			if n == nil {
+				// We found a nil slot which is a candidate for insertion.
+				haveInsertPoint = true
+				break
+			}
+			if n.isEntry {
+				// We found an existing entry, which is as far as we can go.
+				// If it stays this way, we'll have to replace it with an
+				// indirect node.
+				if v, ok := n.entry().lookup(key, ht.keyEqual); ok {
+					return v, true
+				}
+				haveInsertPoint = true
+				break
+			}
+			i = n.indirect()
+		}
+		if !haveInsertPoint {
+			panic("sync.HashTrieMap: ran out of hash bits while iterating")
+		}
+
+		// Grab the lock and double-check what we saw.
+		i.mu.Lock()
+		n = slot.Load()
+		if (n == nil || n.isEntry) && !i.dead.Load() {
+			// What we saw is still true, so we can continue with the insert.
+			break
+		}
+		// We have to start over.
+		i.mu.Unlock()
+	}
+	// N.B. This lock is held from when we broke out of the outer loop above.
+	// We specifically break this out so that we can use defer here safely.
+	// One option is to break this out into a new function instead, but
+	// there's so much local iteration state used below that this turns out
+	// to be cleaner.
+	defer i.mu.Unlock()
+
+	var oldEntry *trieEntry[K, V]
+	if n != nil {
+		oldEntry = n.entry()
+		if v, ok := oldEntry.lookup(key, ht.keyEqual); ok {
+			// Easy case: by loading again, it turns out exactly what we wanted is here!
+			return v, true
+		}
+	}
+	newEntry := newTrieEntry(key, value)
+	if oldEntry == nil {
+		// Easy case: create a new entry and store it.
+		slot.Store(&newEntry.trieNode)
+	} else {
+		// We possibly need to expand the entry already there into one or more new nodes.
+		//
+		// Publish the node last, which will make both oldEntry and newEntry visible. We
+		// don't want readers to be able to observe that oldEntry isn't in the tree.
+		slot.Store(ht.expand(oldEntry, newEntry, hash, hashShift, i))
+	}
+	return value, false
+}
+
+// expand takes oldEntry and newEntry whose hashes conflict from bit 64 down to hashShift and
+// produces a subtree of indirect nodes to hold the two new entries.
+func (ht *HashTrieMap[K, V]) expand(oldEntry, newEntry *trieEntry[K, V], newHash uintptr, hashShift uint, parent *trieIndirect[K, V]) *trieNode[K, V] {
+	// Check for a hash collision.
+	oldHash := ht.keyHash(unsafe.Pointer(&oldEntry.key), ht.seed)
+	if oldHash == newHash {
+		// Store the old entry in the new entry's overflow list, then store
+		// the new entry.
+		newEntry.overflow.Store(oldEntry)
+		return &newEntry.trieNode
+	}
+	// We have to add an indirect node. Worse still, we may need to add more than one.
+	newIndirect := newTrieIndirect(parent)
+	top := newIndirect
+	for {
+		if hashShift == 0 {
+			panic("sync.HashTrieMap: ran out of hash bits while inserting")
+		}
+		hashShift -= nChildrenLog2 // hashShift is for the level parent is at. We need to go deeper.
+		oi := (oldHash >> hashShift) & nChildrenMask
+		ni := (newHash >> hashShift) & nChildrenMask
+		if oi != ni {
+			newIndirect.children[oi].Store(&oldEntry.trieNode)
+			newIndirect.children[ni].Store(&newEntry.trieNode)
+			break
+		}
+		nextIndirect := newTrieIndirect(newIndirect)
+		newIndirect.children[oi].Store(&nextIndirect.trieNode)
+		newIndirect = nextIndirect
+	}
+	return &top.trieNode
+}
+
+// Store sets the value for a key.
+func (ht *HashTrieMap[K, V]) Store(key K, value V) {
+	ht.Swap(key, value)
+}
+
+// Swap swaps the value for a key and returns the previous value if any.
+// The loaded result reports whether the key was present.
+func (ht *HashTrieMap[K, V]) Swap(key K, value V) (previous V, loaded bool) {
+	ht.init()
+	hash := ht.keyHash(abi.NoEscape(unsafe.Pointer(&key)), ht.seed)
+	var i *trieIndirect[K, V]
+	var hashShift uint
+	var slot *atomic.Pointer[trieNode[K, V]]
+	var n *trieNode[K, V]
+	for {
+		// Find the entry chain for the key or a candidate location for insertion.
+		i = ht.root.Load()
+		hashShift = 8 * goarch.PtrSize
+		haveInsertPoint := false
+		for hashShift != 0 {
+			hashShift -= nChildrenLog2
+
+			slot = &i.children[(hash>>hashShift)&nChildrenMask]
+			n = slot.Load()
+			if n == nil || n.isEntry {
+				haveInsertPoint = true
+				break
+			}
+			i = n.indirect()
+		}
+		if !haveInsertPoint {
+			panic("sync.HashTrieMap: ran out of hash bits while iterating")
+		}
+
+		// Grab the lock and double-check what we saw.
+		i.mu.Lock()
+		n = slot.Load()
+		if (n == nil || n.isEntry) && !i.dead.Load() {
+			// What we saw is still true, so we can continue with the swap.
+			break
+		}
+		// We have to start over.
+		i.mu.Unlock()
+	}
+	defer i.mu.Unlock()
+
+	newEntry := newTrieEntry(key, value)
+	if n == nil {
+		// Easy case: create a new entry and store it.
+		slot.Store(&newEntry.trieNode)
+		return *new(V), false
+	}
+	oldEntry := n.entry()
+	if head, old, ok := oldEntry.swap(key, newEntry, ht.keyEqual); ok {
+		// The key was in the chain, and has been replaced.
+		slot.Store(&head.trieNode)
+		return old, true
+	}
+	// The key is new. Expand the entry already there as in LoadOrStore.
+	slot.Store(ht.expand(oldEntry, newEntry, hash, hashShift, i))
+	return *new(V), false
+}
+
+// CompareAndSwap swaps the old and new values for key
+// if the value stored in the map is equal to old.
+// CompareAndSwap panics if V is not a comparable type.
+func (ht *HashTrieMap[K, V]) CompareAndSwap(key K, old, new V) (swapped bool) {
+	ht.init()
+	if ht.valEqual == nil {
+		panic("sync.HashTrieMap: CompareAndSwap called with a value type that is not comparable")
+	}
+	hash := ht.keyHash(abi.NoEscape(unsafe.Pointer(&key)), ht.seed)
+	i, _, slot, n := ht.findLocked(key, hash)
+	if n == nil {
+		return false
+	}
+	defer i.mu.Unlock()
+	head, swapped := n.entry().compareAndSwap(key, old, new, ht.keyEqual, ht.valEqual)
+	if swapped {
+		slot.Store(&head.trieNode)
+	}
+	return swapped
+}
+
+// LoadAndDelete deletes the value for a key, returning the previous value if any.
+// The loaded result reports whether the key was present.
+func (ht *HashTrieMap[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	ht.init()
+	hash := ht.keyHash(abi.NoEscape(unsafe.Pointer(&key)), ht.seed)
+	i, hashShift, slot, n := ht.findLocked(key, hash)
+	if n == nil {
+		return *new(V), false
+	}
+	head, value, loaded := n.entry().loadAndDelete(key, ht.keyEqual)
+	if !loaded {
+		// Nothing was actually deleted, which means the node is no longer there.
+		i.mu.Unlock()
+		return *new(V), false
+	}
+	ht.storeAndUnlock(i, hash, hashShift, slot, head)
+	return value, true
+}
+
+// Delete deletes the value for a key.
+func (ht *HashTrieMap[K, V]) Delete(key K) {
+	ht.LoadAndDelete(key)
+}
+
+// CompareAndDelete deletes the entry for key if its value is equal to old.
+// CompareAndDelete panics if V is not a comparable type.
+//
+// If there is no current value for key in the map, CompareAndDelete returns false
+// (even if the old value is the nil interface value).
+func (ht *HashTrieMap[K, V]) CompareAndDelete(key K, old V) (deleted bool) {
+	ht.init()
+	if ht.valEqual == nil {
+		panic("sync.HashTrieMap: CompareAndDelete called with a value type that is not comparable")
+	}
+	hash := ht.keyHash(abi.NoEscape(unsafe.Pointer(&key)), ht.seed)
+	i, hashShift, slot, n := ht.findLocked(key, hash)
+	if n == nil {
+		return false
+	}
+	head, deleted := n.entry().compareAndDelete(key, old, ht.keyEqual, ht.valEqual)
+	if !deleted {
+		// Nothing was actually deleted, which means the node is no longer there.
+		i.mu.Unlock()
+		return false
+	}
+	ht.storeAndUnlock(i, hash, hashShift, slot, head)
+	return true
+}
+
+// findLocked finds the entry chain that contains key, whose hash is hash.
+// If it finds one, it returns the chain in n, with the indirect node i
+// that holds it in slot locked. Otherwise, n is nil and no lock is held.
+func (ht *HashTrieMap[K, V]) findLocked(key K, hash uintptr) (i *trieIndirect[K, V], hashShift uint, slot *atomic.Pointer[trieNode[K, V]], n *trieNode[K, V]) {
+	for {
+		// Find the key or return when it is not there.
+		i = ht.root.Load()
+		hashShift = 8 * goarch.PtrSize
+		found := false
+		for hashShift != 0 {
+			hashShift -= nChildrenLog2
+
+			slot = &i.children[(hash>>hashShift)&nChildrenMask]
+			n = slot.Load()
+			if n == nil {
+				// The key is not in the map.
+				return nil, 0, nil, nil
+			}
+			if n.isEntry {
+				// We found an entry. Check if it matches.
+				if _, ok := n.entry().lookup(key, ht.keyEqual); !ok {
+					return nil, 0, nil, nil
+				}
+				found = true
+				break
+			}
+			i = n.indirect()
+		}
+		if !found {
+			panic("sync.HashTrieMap: ran out of hash bits while iterating")
+		}
+
+		// Grab the lock and double-check what we saw.
+		i.mu.Lock()
+		n = slot.Load()
+		if !i.dead.Load() {
+			if n == nil {
+				// Valid node that doesn't contain what we need.
+				i.mu.Unlock()
+				return nil, 0, nil, nil
+			}
+			if n.isEntry {
+				// What we saw is still true.
+				return i, hashShift, slot, n
+			}
+		}
+		// We have to start over.
+		i.mu.Unlock()
+	}
+}
+
+// storeAndUnlock stores head, the entry chain left after a deletion, in
+// slot, which is held by i. If that leaves i empty, it removes i and any
+// parents left empty from the trie. It unlocks i.
+func (ht *HashTrieMap[K, V]) storeAndUnlock(i *trieIndirect[K, V], hash uintptr, hashShift uint, slot *atomic.Pointer[trieNode[K, V]], head *trieEntry[K, V]) {
+	if head != nil {
+		// We didn't actually delete the whole entry, just one entry in the chain.
+		// Nothing else to do, since the parent is definitely not empty.
+		slot.Store(&head.trieNode)
+		i.mu.Unlock()
+		return
+	}
+	// Delete the entry.
+	slot.Store(nil)
+
+	// Check if the node is now empty (and isn't the root), and delete it if able.
+	for i.parent != nil && i.empty() {
+		if hashShift == 8*goarch.PtrSize {
+			panic("sync.HashTrieMap: ran out of hash bits while iterating")
+		}
+		hashShift += nChildrenLog2
+
+		// Delete the current node in the parent.
+		parent := i.parent
+		parent.mu.Lock()
+		i.dead.Store(true)
+		parent.children[(hash>>hashShift)&nChildrenMask].Store(nil)
+		i.mu.Unlock()
+		i = parent
+	}
+	i.mu.Unlock()
+}
+
+// All returns an iterator over all key-value pairs in the map.
+// The enumeration does not represent any consistent snapshot of the map,
+// but is guaranteed to visit each unique key-value pair only once. It is
+// safe to operate on the map during iteration. No particular enumeration
+// order is guaranteed.
+func (ht *HashTrieMap[K, V]) All() func(yield func(K, V) bool) {
+	ht.init()
+	return func(yield func(key K, value V) bool) {
+		ht.iter(ht.root.Load(), yield)
+	}
+}
+
+// Range calls f sequentially for each key and value present in the map.
+// If f returns false, Range stops the iteration.
+//
+// Range has the same guarantees as [HashTrieMap.All].
+func (ht *HashTrieMap[K, V]) Range(f func(key K, value V) bool) {
+	ht.init()
+	ht.iter(ht.root.Load(), f)
+}
+
+func (ht *HashTrieMap[K, V]) iter(i *trieIndirect[K, V], yield func(key K, value V) bool) bool {
+	for j := range i.children {
+		n := i.children[j].Load()
+		if n == nil {
+			continue
+		}
+		if !n.isEntry {
+			if !ht.iter(n.indirect(), yield) {
+				return false
+			}
+			continue
+		}
+		e := n.entry()
+		for e != nil {
+			if !yield(e.key, e.value) {
+				return false
+			}
+			e = e.overflow.Load()
+		}
+	}
+	return true
+}
+
+// Clear deletes all the entries, resulting in an empty HashTrieMap.
+func (ht *HashTrieMap[K, V]) Clear() {
+	ht.init()
+
+	// It's sufficient to just drop the root on the floor, but the root
+	// must always be non-nil.
+	ht.root.Store(newTrieIndirect[K, V](nil))
+}
+
+const (
+	// 16 children. This seems to be the sweet spot for
+	// load performance: any smaller and we lose out on
+	// 50% or more in CPU performance. Any larger and the
+	// returns are minuscule (~1% improvement for 32 children).
+	nChildrenLog2 = 4
+	nChildren     = 1 << nChildrenLog2
+	nChildrenMask = nChildren - 1
+)
+
+// trieIndirect is an internal node in the hash-trie.
+type trieIndirect[K comparable, V any] struct {
+	trieNode[K, V]
+	dead     atomic.Bool
+	mu       Mutex // Protects mutation to children and any children that are entry nodes.
+	parent   *trieIndirect[K, V]
+	children [nChildren]atomic.Pointer[trieNode[K, V]]
+}
+
+func newTrieIndirect[K comparable, V any](parent *trieIndirect[K, V]) *trieIndirect[K, V] {
+	return &trieIndirect[K, V]{trieNode: trieNode[K, V]{isEntry: false}, parent: parent}
+}
+
+func (i *trieIndirect[K, V]) empty() bool {
+	nc := 0
+	for j := range i.children {
+		if i.children[j].Load() != nil {
+			nc++
+		}
+	}
+	return nc == 0
+}
+
+// trieEntry is a leaf node in the hash-trie.
+//
+// The key and value of an entry never change once it is published.
+// Writes replace entries in the overflow chain instead.
+type trieEntry[K comparable, V any] struct {
+	trieNode[K, V]
+	overflow atomic.Pointer[trieEntry[K, V]] // Overflow for hash collisions.
+	key      K
+	value    V
+}
+
+func newTrieEntry[K comparable, V any](key K, value V) *trieEntry[K, V] {
+	return &trieEntry[K, V]{
+		trieNode: trieNode[K, V]{isEntry: true},
+		key:      key,
+		value:    value,
+	}
+}
+
+func (e *trieEntry[K, V]) lookup(key K, equal equalFunc) (V, bool) {
+	for e != nil {
+		if equal(unsafe.Pointer(&e.key), abi.NoEscape(unsafe.Pointer(&key))) {
+			return e.value, true
+		}
+		e = e.overflow.Load()
+	}
+	return *new(V), false
+}
+
+// swap replaces the entry for key in the overflow chain with newEntry.
+// It returns the new entry chain, the old value, and whether key was found.
+//
+// swap must be called under the mutex of the indirect node which head is a child of.
+func (head *trieEntry[K, V]) swap(key K, newEntry *trieEntry[K, V], keyEqual equalFunc) (*trieEntry[K, V], V, bool) {
+	if keyEqual(unsafe.Pointer(&head.key), abi.NoEscape(unsafe.Pointer(&key))) {
+		// Replace the head of the list.
+		newEntry.overflow.Store(head.overflow.Load())
+		return newEntry, head.value, true
+	}
+	i := &head.overflow
+	e := i.Load()
+	for e != nil {
+		if keyEqual(unsafe.Pointer(&e.key), abi.NoEscape(unsafe.Pointer(&key))) {
+			newEntry.overflow.Store(e.overflow.Load())
+			i.Store(newEntry)
+			return head, e.value, true
+		}
+		i = &e.overflow
+		e = e.overflow.Load()
+	}
+	return head, *new(V), false
+}
+
+// compareAndSwap replaces the entry for key in the overflow chain with a
+// new entry holding new if its value is equal to old. It returns the new
+// entry chain and whether the value was swapped.
+//
+// compareAndSwap must be called under the mutex of the indirect node which head is a child of.
+func (head *trieEntry[K, V]) compareAndSwap(key K, old, new V, keyEqual, valEqual equalFunc) (*trieEntry[K, V], bool) {
+	for e := head; e != nil; e = e.overflow.Load() {
+		if keyEqual(unsafe.Pointer(&e.key), abi.NoEscape(unsafe.Pointer(&key))) {
+			if !valEqual(unsafe.Pointer(&e.value), abi.NoEscape(unsafe.Pointer(&old))) {
+				return head, false
+			}
+			head, _, _ = head.swap(key, newTrieEntry(key, new), keyEqual)
+			return head, true
+		}
+	}
+	return head, false
+}
+
+// loadAndDelete deletes the entry for key in the overflow chain. It returns
+// the new entry chain, the value of the deleted entry, and whether anything
+// was deleted.
+//
+// loadAndDelete must be called under the mutex of the indirect node which head is a child of.
+func (head *trieEntry[K, V]) loadAndDelete(key K, keyEqual equalFunc) (*trieEntry[K, V], V, bool) {
+	if keyEqual(unsafe.Pointer(&head.key), abi.NoEscape(unsafe.Pointer(&key))) {
+		// Drop the head of the list.
+		return head.overflow.Load(), head.value, true
+	}
+	i := &head.overflow
+	e := i.Load()
+	for e != nil {
+		if keyEqual(unsafe.Pointer(&e.key), abi.NoEscape(unsafe.Pointer(&key))) {
+			i.Store(e.overflow.Load())
+			return head, e.value, true
+		}
+		i = &e.overflow
+		e = e.overflow.Load()
+	}
+	return head, *new(V), false
+}
+
+// compareAndDelete deletes an entry in the overflow chain if both the key and value compare
+// equal. Returns the new entry chain and whether or not anything was deleted.
+//
+// compareAndDelete must be called under the mutex of the indirect node which head is a child of.
+func (head *trieEntry[K, V]) compareAndDelete(key K, value V, keyEqual, valEqual equalFunc) (*trieEntry[K, V], bool) {
+	if keyEqual(unsafe.Pointer(&head.key), abi.NoEscape(unsafe.Pointer(&key))) &&
+		valEqual(unsafe.Pointer(&head.value), abi.NoEscape(unsafe.Pointer(&value))) {
+		// Drop the head of the list.
+		return head.overflow.Load(), true
+	}
+	i := &head.overflow
+	e := i.Load()
+	for e != nil {
+		if keyEqual(unsafe.Pointer(&e.key), abi.NoEscape(unsafe.Pointer(&key))) &&
+			valEqual(unsafe.Pointer(&e.value), abi.NoEscape(unsafe.Pointer(&value))) {
+			i.Store(e.overflow.Load())
+			return head, true
+		}
+		i = &e.overflow
+		e = e.overflow.Load()
+	}
+	return head, false
+}
+
+// trieNode is the header for a node. It's polymorphic and
+// is actually either a trieEntry or a trieIndirect.
+type trieNode[K comparable, V any] struct {
+	isEntry bool
+}
+
+func (n *trieNode[K, V]) entry() *trieEntry[K, V] {
+	if !n.isEntry {
+		panic("called entry on non-entry node")
+	}
+	return (*trieEntry[K, V])(unsafe.Pointer(n))
+}
+
+func (n *trieNode[K, V]) indirect() *trieIndirect[K, V] {
+	if n.isEntry {
+		panic("called indirect on entry node")
+	}
+	return (*trieIndirect[K, V])(unsafe.Pointer(n))
+}
@@ -2,38 +2,34 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-package concurrent
+package sync_test
 
 import (
 	"fmt"
 	"math"
 	"runtime"
 	"strconv"
-	"strings"
 	"sync"
 	"testing"
-	"unsafe"
 )
 
 func TestHashTrieMap(t *testing.T) {
-	testHashTrieMap(t, func() *HashTrieMap[string, int] {
-		return NewHashTrieMap[string, int]()
+	testHashTrieMap(t, func() *sync.HashTrieMap[string, int] {
+		return new(sync.HashTrieMap[string, int])
 	})
 }
 
 func TestHashTrieMapBadHash(t *testing.T) {
-	testHashTrieMap(t, func() *HashTrieMap[string, int] {
+	testHashTrieMap(t, func() *sync.HashTrieMap[string, int] {
 		// Stub out the good hash function with a terrible one.
 		// Everything should still work as expected.
-		m := NewHashTrieMap[string, int]()
-		m.keyHash = func(_ unsafe.Pointer, _ uintptr) uintptr {
-			return 0
-		}
+		m := new(sync.HashTrieMap[string, int])
+		sync.SetBadHashTrieMapHash(m)
 		return m
 	})
 }
 
-func testHashTrieMap(t *testing.T, newMap func() *HashTrieMap[string, int]) {
+func testHashTrieMap(t *testing.T, newMap func() *sync.HashTrieMap[string, int]) {
 	t.Run("LoadEmpty", func(t *testing.T) {
 		m := newMap()
 
@@ -119,6 +115,75 @@ func testHashTrieMap(t *testing.T, newMap func() *HashTrieMap[string, int]) {
 			}
 		}
 	})
+	t.Run("StoreSwap", func(t *testing.T) {
+		m := newMap()
+
+		for i, s := range testData {
+			expectMissing(t, s, 0)(m.Load(s))
+			m.Store(s, i)
+			expectPresent(t, s, i)(m.Load(s))
+			expectLoaded(t, s, i)(m.Swap(s, i+1))
+			expectPresent(t, s, i+1)(m.Load(s))
+		}
+		for i, s := range testData {
+			expectPresent(t, s, i+1)(m.Load(s))
+			m.Store(s, i)
+			expectPresent(t, s, i)(m.Load(s))
+		}
+		m.Clear()
+		for i, s := range testData {
+			expectMissing(t, s, 0)(m.Swap(s, i))
+			expectPresent(t, s, i)(m.Load(s))
+		}
+	})
+	t.Run("CompareAndSwap", func(t *testing.T) {
+		m := newMap()
+
+		for i, s := range testData {
+			expectNotSwapped(t, s, 0)(m.CompareAndSwap(s, 0, i))
+			expectStored(t, s, i)(m.LoadOrStore(s, i))
+			expectNotSwapped(t, s, math.MaxInt)(m.CompareAndSwap(s, math.MaxInt, 0))
+			expectSwapped(t, s, i)(m.CompareAndSwap(s, i, i+1))
+			expectPresent(t, s, i+1)(m.Load(s))
+		}
+		for i, s := range testData {
+			expectPresent(t, s, i+1)(m.Load(s))
+		}
+	})
+	t.Run("LoadAndDelete", func(t *testing.T) {
+		m := newMap()
+
+		for range 3 {
+			for i, s := range testData {
+				expectStored(t, s, i)(m.LoadOrStore(s, i))
+			}
+			for i, s := range testData {
+				expectLoaded(t, s, i)(m.LoadAndDelete(s))
+				expectMissing(t, s, 0)(m.LoadAndDelete(s))
+				expectMissing(t, s, 0)(m.Load(s))
+			}
+		}
+		for i, s := range testData {
+			m.Store(s, i)
+			m.Delete(s)
+			expectMissing(t, s, 0)(m.Load(s))
+		}
+	})
+	t.Run("Clear", func(t *testing.T) {
+		m := newMap()
+
+		for i, s := range testData {
+			expectStored(t, s, i)(m.LoadOrStore(s, i))
+		}
+		m.Clear()
+		for _, s := range testData {
+			expectMissing(t, s, 0)(m.Load(s))
+		}
+		m.Range(func(key string, _ int) bool {
+			t.Errorf("unexpected key %v in cleared map", key)
+			return true
+		})
+	})
 	t.Run("All", func(t *testing.T) {
 		m := newMap()
 
@@ -137,6 +202,21 @@ func testHashTrieMap(t *testing.T, newMap func() *HashTrieMap[string, int]) {
 			expectMissing(t, s, 0)(m.Load(s))
 		}
 	})
+	t.Run("RangeStop", func(t *testing.T) {
+		m := newMap()
+
+		for i, s := range testData {
+			m.Store(s, i)
+		}
+		n := 0
+		m.Range(func(_ string, _ int) bool {
+			n++
+			return n < 10
+		})
+		if n != 10 {
+			t.Errorf("Range visited %d entries after stopping at 10", n)
+		}
+	})
 	t.Run("ConcurrentLifecycleUnsharedKeys", func(t *testing.T) {
 		m := newMap()
 
@@ -200,7 +280,7 @@ func testHashTrieMap(t *testing.T, newMap func() *HashTrieMap[string, int]) {
 	})
 }
 
-func testAll[K, V comparable](t *testing.T, m *HashTrieMap[K, V], testData map[K]V, yield func(K, V) bool) {
+func testAll[K, V comparable](t *testing.T, m *sync.HashTrieMap[K, V], testData map[K]V, yield func(K, V) bool) {
 	for k, v := range testData {
 		expectStored(t, k, v)(m.LoadOrStore(k, v))
 	}
@@ -308,6 +388,28 @@ func expectNotDeleted[K, V comparable](t *testing.T, key K, old V) func(deleted
 	}
 }
 
+func expectSwapped[K, V comparable](t *testing.T, key K, old V) func(swapped bool) {
+	t.Helper()
+	return func(swapped bool) {
+		t.Helper()
+
+		if !swapped {
+			t.Errorf("expected key %v with value %v to be in map and swapped", key, old)
+		}
+	}
+}
+
+func expectNotSwapped[K, V comparable](t *testing.T, key K, old V) func(swapped bool) {
+	t.Helper()
+	return func(swapped bool) {
+		t.Helper()
+
+		if swapped {
+			t.Errorf("expected key %v with value %v to not be in map and thus not swapped", key, old)
+		}
+	}
+}
+
 func testDataMap(data []string) map[string]int {
 	m := make(map[string]int)
 	for i, s := range data {
@@ -334,38 +436,21 @@ func init() {
 	}
 }
 
-func dumpMap[K, V comparable](ht *HashTrieMap[K, V]) {
-	dumpNode(ht, &ht.root.node, 0)
-}
-
-func dumpNode[K, V comparable](ht *HashTrieMap[K, V], n *node[K, V], depth int) {
-	var sb strings.Builder
-	for range depth {
-		fmt.Fprintf(&sb, "\t")
-	}
-	prefix := sb.String()
-	if n.isEntry {
-		e := n.entry()
-		for e != nil {
-			fmt.Printf("%s%p [Entry Key=%v Value=%v Overflow=%p, Hash=%016x]\n", prefix, e, e.key, e.value, e.overflow.Load(), ht.keyHash(unsafe.Pointer(&e.key), ht.seed))
-			e = e.overflow.Load()
-		}
-		return
-	}
-	i := n.indirect()
-	fmt.Printf("%s%p [Indirect Parent=%p Dead=%t Children=[", prefix, i, i.parent, i.dead.Load())
-	for j := range i.children {
-		c := i.children[j].Load()
-		fmt.Printf("%p", c)
-		if j != len(i.children)-1 {
-			fmt.Printf(", ")
-		}
+func TestHashTrieMapNotComparable(t *testing.T) {
+	var m sync.HashTrieMap[string, []int]
+	m.Store("a", []int{1})
+	expectPanic := func(name string, f func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s with non-comparable value type did not panic", name)
+			}
+		}()
+		f()
 	}
-	fmt.Printf("]]\n")
-	for j := range i.children {
-		c := i.children[j].Load()
-		if c != nil {
-			dumpNode(ht, c, depth+1)
-		}
+	expectPanic("CompareAndSwap", func() { m.CompareAndSwap("a", nil, nil) })
+	expectPanic("CompareAndDelete", func() { m.CompareAndDelete("a", nil) })
+	if v, ok := m.Load("a"); !ok || len(v) != 1 || v[0] != 1 {
+		t.Errorf("Load(%q) = %v, %v; want [1], true", "a", v, ok)
 	}
 }
@@ -22,6 +22,10 @@ import (
 // sets of keys. In these two cases, use of a Map may significantly reduce lock
 // contention compared to a Go map paired with a separate [Mutex] or [RWMutex].
 //
+// Map stores its keys and values as interfaces, so storing a value that is
+// not a pointer usually allocates. [HashTrieMap] offers the same operations
+// with typed keys and values and does not box them.
+//
 // The zero Map is empty and ready for use. A Map must not be copied after first use.
 //
 // In the terminology of [the Go memory model], Map arranges that a write operation
@@ -68,6 +72,11 @@ type Map struct {
 	// map, the dirty map will be promoted to the read map (in the unamended
 	// state) and the next store to the map will make a new dirty copy.
 	misses int
+
+	// count is the number of keys present in the map. It is updated by
+	// whichever operation changes an entry from deleted to present or
+	// back, whether or not it holds mu.
+	count atomic.Int64
 }
 
 // readOnly is an immutable struct stored atomically in the Map.read field.
@@ -169,7 +178,17 @@ func (m *Map) Clear() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	// Expunge every entry before dropping it, so that no operation
+	// that loaded the entry without holding mu can store a value into
+	// it, or delete one from it, once it has left the map. Such an
+	// operation would otherwise leave m.count wrong.
 	read = m.loadReadOnly()
+	for _, e := range read.m {
+		m.expungeLocked(e)
+	}
+	for _, e := range m.dirty {
+		m.expungeLocked(e)
+	}
 	if len(read.m) > 0 || read.amended {
 		m.read.Store(&readOnly{})
 	}
@@ -179,6 +198,14 @@ func (m *Map) Clear() {
 	m.misses = 0
 }
 
+// expungeLocked marks e as expunged, whatever its value, and updates
+// m.count if e was present.
+func (m *Map) expungeLocked(e *entry) {
+	if p := e.p.Swap(expunged); p != nil && p != expunged {
+		m.count.Add(-1)
+	}
+}
+
 // tryCompareAndSwap compare the entry with the given old value and swaps
 // it with a new value if the entry is equal to the old value, and the entry
 // has not been expunged.
@@ -230,12 +257,23 @@ func (m *Map) LoadOrStore(key, value any) (actual any, loaded bool) {
 	if e, ok := read.m[key]; ok {
 		actual, loaded, ok := e.tryLoadOrStore(value)
 		if ok {
+			if !loaded {
+				m.count.Add(1)
+			}
 			return actual, loaded
 		}
 	}
 
 	m.mu.Lock()
-	read = m.loadReadOnly()
+	actual, loaded = m.loadOrStoreLocked(key, value)
+	m.mu.Unlock()
+
+	return actual, loaded
+}
+
+// loadOrStoreLocked is the slow path of LoadOrStore, run with m.mu held.
+func (m *Map) loadOrStoreLocked(key, value any) (actual any, loaded bool) {
+	read := m.loadReadOnly()
 	if e, ok := read.m[key]; ok {
 		if e.unexpungeLocked() {
 			m.dirty[key] = e
@@ -254,8 +292,51 @@ func (m *Map) LoadOrStore(key, value any) (actual any, loaded bool) {
 		m.dirty[key] = newEntry(value)
 		actual, loaded = value, false
 	}
-	m.mu.Unlock()
+	if !loaded {
+		m.count.Add(1)
+	}
+	return actual, loaded
+}
 
+// LoadOrStoreMany is like calling [Map.LoadOrStore] for each key in keys
+// and the value at the same index in values, but it locks the map at
+// most once for the whole batch. It returns the results of each call
+// at the same index in actual and loaded. LoadOrStoreMany panics if
+// keys and values have different lengths.
+//
+// The calls are not performed atomically as a group: concurrent
+// operations may observe some of them and not others.
+func (m *Map) LoadOrStoreMany(keys, values []any) (actual []any, loaded []bool) {
+	if len(keys) != len(values) {
+		panic("sync: LoadOrStoreMany with different numbers of keys and values")
+	}
+	actual = make([]any, len(keys))
+	loaded = make([]bool, len(keys))
+
+	// Avoid locking for the clean hits, and remember the rest.
+	var slow []int
+	read := m.loadReadOnly()
+	for i, key := range keys {
+		if e, ok := read.m[key]; ok {
+			if v, l, ok := e.tryLoadOrStore(values[i]); ok {
+				actual[i], loaded[i] = v, l
+				if !l {
+					m.count.Add(1)
+				}
+				continue
+			}
+		}
+		slow = append(slow, i)
+	}
+	if len(slow) == 0 {
+		return actual, loaded
+	}
+
+	m.mu.Lock()
+	for _, i := range slow {
+		actual[i], loaded[i] = m.loadOrStoreLocked(keys[i], values[i])
+	}
+	m.mu.Unlock()
 	return actual, loaded
 }
 
@@ -311,7 +392,11 @@ func (m *Map) LoadAndDelete(key any) (value any, loaded bool) {
 		m.mu.Unlock()
 	}
 	if ok {
-		return e.delete()
+		value, loaded = e.delete()
+		if loaded {
+			m.count.Add(-1)
+		}
+		return value, loaded
 	}
 	return nil, false
 }
@@ -321,6 +406,47 @@ func (m *Map) Delete(key any) {
 	m.LoadAndDelete(key)
 }
 
+// DeleteMany is like calling [Map.Delete] for each key in keys, but it
+// locks the map at most once for the whole batch.
+//
+// The deletions are not performed atomically as a group: concurrent
+// operations may observe some of them and not others.
+func (m *Map) DeleteMany(keys []any) {
+	var slow []any
+	read := m.loadReadOnly()
+	for _, key := range keys {
+		if e, ok := read.m[key]; ok {
+			if _, ok := e.delete(); ok {
+				m.count.Add(-1)
+			}
+		} else if read.amended {
+			slow = append(slow, key)
+		}
+	}
+	if len(slow) == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	for _, key := range slow {
+		read = m.loadReadOnly()
+		e, ok := read.m[key]
+		if !ok && read.amended {
+			e, ok = m.dirty[key]
+			delete(m.dirty, key)
+			// As in LoadAndDelete, record a miss whether or not the
+			// entry was present.
+			m.missLocked()
+		}
+		if ok {
+			if _, ok := e.delete(); ok {
+				m.count.Add(-1)
+			}
+		}
+	}
+	m.mu.Unlock()
+}
+
 func (e *entry) delete() (value any, ok bool) {
 	for {
 		p := e.p.Load()
@@ -356,6 +482,7 @@ func (m *Map) Swap(key, value any) (previous any, loaded bool) {
 	if e, ok := read.m[key]; ok {
 		if v, ok := e.trySwap(&value); ok {
 			if v == nil {
+				m.count.Add(1)
 				return nil, false
 			}
 			return *v, true
@@ -388,6 +515,9 @@ func (m *Map) Swap(key, value any) (previous any, loaded bool) {
 		}
 		m.dirty[key] = newEntry(value)
 	}
+	if !loaded {
+		m.count.Add(1)
+	}
 	m.mu.Unlock()
 	return previous, loaded
 }
@@ -453,6 +583,7 @@ func (m *Map) CompareAndDelete(key, old any) (deleted bool) {
 			return false
 		}
 		if e.p.CompareAndSwap(p, nil) {
+			m.count.Add(-1)
 			return true
 		}
 	}
@@ -504,6 +635,22 @@ func (m *Map) Range(f func(key, value any) bool) {
 	}
 }
 
+// Len returns the number of entries in the map.
+//
+// If entries are stored or deleted concurrently, Len reflects the count
+// at some point during the call, which need not match the number of
+// entries seen by a concurrent [Map.Range].
+func (m *Map) Len() int {
+	return int(m.count.Load())
+}
+
+// All returns an iterator over each key and value present in the map.
+// It visits the entries that [Map.Range] would visit, with the same
+// guarantees.
+func (m *Map) All() func(yield func(key, value any) bool) {
+	return m.Range
+}
+
 func (m *Map) missLocked() {
 	m.misses++
 	if m.misses < len(m.dirty) {
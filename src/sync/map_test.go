@@ -133,6 +133,10 @@ func applyDeepCopyMap(calls []mapCall) ([]mapResult, map[any]any) {
 	return applyCalls(new(DeepCopyMap), calls)
 }
 
+func applyHashTrieMap(calls []mapCall) ([]mapResult, map[any]any) {
+	return applyCalls(new(sync.HashTrieMap[any, any]), calls)
+}
+
 func TestMapMatchesRWMutex(t *testing.T) {
 	if err := quick.CheckEqual(applyMap, applyRWMutexMap, nil); err != nil {
 		t.Error(err)
@@ -145,6 +149,12 @@ func TestMapMatchesDeepCopy(t *testing.T) {
 	}
 }
 
+func TestHashTrieMapMatchesRWMutex(t *testing.T) {
+	if err := quick.CheckEqual(applyHashTrieMap, applyRWMutexMap, nil); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestConcurrentRange(t *testing.T) {
 	const mapSize = 1 << 10
 
@@ -357,3 +367,139 @@ func TestMapClearNoAllocations(t *testing.T) {
 		t.Errorf("AllocsPerRun of m.Clear = %v; want 0", allocs)
 	}
 }
+
+func TestMapLen(t *testing.T) {
+	check := func(calls []mapCall) bool {
+		var m sync.Map
+		_, final := applyCalls(&m, calls)
+		if m.Len() != len(final) {
+			t.Logf("Len() = %d, want %d", m.Len(), len(final))
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(check, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMapLenConcurrent(t *testing.T) {
+	const mapSize = 1 << 10
+
+	var m sync.Map
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range 100 {
+				for i := range mapSize {
+					k := g*mapSize + i
+					switch (n + i) % 4 {
+					case 0:
+						m.Store(k, n)
+					case 1:
+						m.LoadOrStore(k, n)
+					case 2:
+						m.CompareAndDelete(k, n-1)
+					case 3:
+						m.Delete(k)
+					}
+				}
+				if g == 0 && n%10 == 0 {
+					m.Clear()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	n := 0
+	m.Range(func(k, v any) bool {
+		n++
+		return true
+	})
+	if m.Len() != n {
+		t.Errorf("Len() = %d, but Range visited %d entries", m.Len(), n)
+	}
+}
+
+func TestMapAll(t *testing.T) {
+	var m sync.Map
+	want := make(map[any]any)
+	for i := range 100 {
+		m.Store(i, i*10)
+		want[i] = i * 10
+	}
+	for k, v := range m.All() {
+		if want[k] != v {
+			t.Errorf("All produced (%v, %v), want (%v, %v)", k, v, k, want[k])
+		}
+		delete(want, k)
+	}
+	if len(want) != 0 {
+		t.Errorf("All did not produce %d entries", len(want))
+	}
+
+	n := 0
+	for range m.All() {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("All produced %d entries before break, want 1", n)
+	}
+}
+
+func TestMapLoadOrStoreMany(t *testing.T) {
+	var m sync.Map
+	m.Store("a", 1)
+	m.Store("b", 2)
+	m.Delete("b")
+	m.Store("c", 3) // in the dirty map only
+
+	keys := []any{"a", "b", "c", "d", "d"}
+	values := []any{10, 20, 30, 40, 50}
+	actual, loaded := m.LoadOrStoreMany(keys, values)
+	wantActual := []any{1, 20, 3, 40, 40}
+	wantLoaded := []bool{true, false, true, false, true}
+	if !reflect.DeepEqual(actual, wantActual) || !reflect.DeepEqual(loaded, wantLoaded) {
+		t.Errorf("LoadOrStoreMany(%v, %v) = %v, %v, want %v, %v", keys, values, actual, loaded, wantActual, wantLoaded)
+	}
+	if m.Len() != 4 {
+		t.Errorf("Len() = %d, want 4", m.Len())
+	}
+	for i, k := range keys {
+		if v, _ := m.Load(k); v != wantActual[i] {
+			t.Errorf("Load(%v) = %v, want %v", k, v, wantActual[i])
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("LoadOrStoreMany with mismatched lengths did not panic")
+		}
+	}()
+	m.LoadOrStoreMany(keys, values[:1])
+}
+
+func TestMapDeleteMany(t *testing.T) {
+	var m sync.Map
+	for i := range 10 {
+		m.Store(i, i)
+	}
+	m.Range(func(k, v any) bool { return true }) // promote to the read map
+	m.Store(10, 10)                              // in the dirty map only
+
+	m.DeleteMany([]any{0, 2, 4, 10, 11, 4})
+	if m.Len() != 7 {
+		t.Errorf("Len() = %d, want 7", m.Len())
+	}
+	for i := range 12 {
+		_, ok := m.Load(i)
+		want := i < 10 && (i > 4 || i%2 != 0)
+		if ok != want {
+			t.Errorf("after DeleteMany, Load(%d) found = %v, want %v", i, ok, want)
+		}
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"runtime"
 	"strings"
 	"time"
+	_ "unsafe" // for linkname
 )
 
 func initFuzzFlags() {
@@ -38,6 +39,42 @@ var (
 	corpusDir = "testdata/fuzz"
 )
 
+// setMapOrder is implemented in the runtime package. If GODEBUG=mapseed
+// is set, it makes the order of map iteration from now on a function of
+// seed and reports true. Otherwise it reports false.
+//
+//go:linkname setMapOrder
+func setMapOrder(seed uint64) bool
+
+// mapOrderSeed returns a hash of the values of a corpus entry,
+// from which the map iteration order of the fuzz target is derived.
+func mapOrderSeed(values []any) uint64 {
+	// FNV-1a
+	const prime = 1099511628211
+	h := uint64(14695981039346656037)
+	var buf []byte
+	for _, v := range values {
+		switch v := v.(type) {
+		case []byte:
+			for _, c := range v {
+				h = (h ^ uint64(c)) * prime
+			}
+		case string:
+			for i := 0; i < len(v); i++ {
+				h = (h ^ uint64(v[i])) * prime
+			}
+		default:
+			buf = fmt.Appendf(buf[:0], "%T %v", v, v)
+			for _, c := range buf {
+				h = (h ^ uint64(c)) * prime
+			}
+		}
+		// Separate the values, so that ("ab", "c") and ("a", "bc") differ.
+		h = (h ^ 0xff) * prime
+	}
+	return h
+}
+
 // fuzzWorkerExitCode is used as an exit code by fuzz worker processes after an
 // internal error. This distinguishes internal errors from uncontrolled panics
 // and other failures. Keep in sync with internal/fuzz.workerExitCode.
@@ -272,6 +309,11 @@ func (f *F) Fuzz(ff any) {
 	// run calls fn on a given input, as a subtest with its own T.
 	// run is analogous to T.Run. The test filtering and cleanup works similarly.
 	// fn is called in its own goroutine.
+	// With GODEBUG=mapseed, run each input with a map iteration order
+	// derived from it, so that failures that depend on the order
+	// reproduce when the input is run again from the corpus.
+	pinMapOrder := setMapOrder(0)
+
 	run := func(captureOut io.Writer, e corpusEntry) (ok bool) {
 		if e.Values == nil {
 			// The corpusEntry must have non-nil Values in order to run the
@@ -332,6 +374,9 @@ func (f *F) Fuzz(ff any) {
 				defer f.fuzzContext.deps.SnapshotCoverage()
 				f.fuzzContext.deps.ResetCoverage()
 			}
+			if pinMapOrder {
+				setMapOrder(mapOrderSeed(e.Values))
+			}
 			fn.Call(args)
 		})
 		<-t.signal
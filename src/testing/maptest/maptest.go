@@ -0,0 +1,286 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package maptest implements support for testing implementations of
+// map-like containers.
+package maptest
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"math/rand/v2"
+)
+
+// Map is the interface implemented by the map-like containers that
+// [TestMap] tests.
+type Map[K comparable, V comparable] interface {
+	// Load returns the value stored for key, and whether one was present.
+	Load(key K) (value V, ok bool)
+
+	// Store sets the value for key.
+	Store(key K, value V)
+
+	// Delete removes the value for key, if any.
+	Delete(key K)
+
+	// Len returns the number of keys in the map.
+	Len() int
+
+	// All returns an iterator over the keys and values in the map.
+	// As with a range loop over a builtin map, an entry deleted during
+	// iteration before it is reached must not be produced, and an entry
+	// stored during iteration may or may not be produced.
+	All() iter.Seq2[K, V]
+}
+
+// TestMap tests a map implementation. It creates maps with newMap and
+// runs randomized sequences of stores, loads and deletes on them,
+// checking the results against a builtin map. It also checks the
+// behavior of All when the map is modified during iteration or the
+// iteration is stopped early.
+//
+// The function key must return distinct keys for distinct arguments,
+// which are non-negative. The function value returns the values to store;
+// it may return equal values for different arguments.
+//
+// If TestMap finds any misbehaviors, it returns a list of errors.
+// Use [errors.Is] or [errors.As] to inspect.
+//
+// Typical usage inside a test is:
+//
+//	newMap := func() maptest.Map[string, int] { return new(MyMap) }
+//	if err := maptest.TestMap(newMap, strconv.Itoa, func(i int) int { return i }); err != nil {
+//		t.Fatal(err)
+//	}
+func TestMap[K comparable, V comparable](newMap func() Map[K, V], key func(i int) K, value func(i int) V) error {
+	t := mapTester[K, V]{newMap: newMap, key: key, value: value}
+	t.testEmpty()
+	for _, n := range []int{10, 100, 10000} {
+		t.testRandom(n)
+	}
+	t.testDeleteDuringAll()
+	t.testStoreDuringAll()
+	t.testStopAll()
+	if len(t.errors) == 0 {
+		return nil
+	}
+	return fmt.Errorf("TestMap found errors:\n%w", errors.Join(t.errors...))
+}
+
+// A mapTester holds state for running the test.
+type mapTester[K comparable, V comparable] struct {
+	newMap func() Map[K, V]
+	key    func(int) K
+	value  func(int) V
+	errors []error
+}
+
+// errorf adds an error to the list of errors.
+func (t *mapTester[K, V]) errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Errorf(format, args...))
+}
+
+// check reports whether m holds exactly the entries of model,
+// adding errors for any differences.
+func (t *mapTester[K, V]) check(phase string, m Map[K, V], model map[K]V) bool {
+	ok := true
+	if n := m.Len(); n != len(model) {
+		t.errorf("%s: Len() = %d, want %d", phase, n, len(model))
+		ok = false
+	}
+	for k, want := range model {
+		if v, found := m.Load(k); !found || v != want {
+			t.errorf("%s: Load(%v) = %v, %v, want %v, true", phase, k, v, found, want)
+			return false
+		}
+	}
+	seen := make(map[K]bool, len(model))
+	for k, v := range m.All() {
+		want, found := model[k]
+		switch {
+		case !found:
+			t.errorf("%s: All produced key %v, which is not in the map", phase, k)
+			return false
+		case seen[k]:
+			t.errorf("%s: All produced key %v more than once", phase, k)
+			return false
+		case v != want:
+			t.errorf("%s: All produced %v for key %v, want %v", phase, v, k, want)
+			return false
+		}
+		seen[k] = true
+	}
+	if len(seen) != len(model) {
+		t.errorf("%s: All produced %d keys, want %d", phase, len(seen), len(model))
+		ok = false
+	}
+	return ok
+}
+
+func (t *mapTester[K, V]) testEmpty() {
+	m := t.newMap()
+	if !t.check("empty map", m, nil) {
+		return
+	}
+	if v, ok := m.Load(t.key(0)); ok {
+		t.errorf("empty map: Load(%v) = %v, true, want not found", t.key(0), v)
+	}
+	m.Delete(t.key(0))
+	t.check("empty map after Delete", m, nil)
+}
+
+// testRandom runs random operations on keys drawn from key(0)
+// through key(n-1), checking the map after each.
+func (t *mapTester[K, V]) testRandom(n int) {
+	phase := fmt.Sprintf("random operations on %d keys", n)
+	r := rand.New(rand.NewPCG(1, uint64(n)))
+	m := t.newMap()
+	model := make(map[K]V)
+	ops := 10 * n
+	for i := range ops {
+		k := t.key(r.IntN(n))
+		switch op := r.IntN(4); op {
+		case 0, 1:
+			// Store more often than delete, so the map grows.
+			v := t.value(r.IntN(1 << 20))
+			m.Store(k, v)
+			model[k] = v
+		case 2:
+			m.Delete(k)
+			delete(model, k)
+		case 3:
+			want, wantOK := model[k]
+			if v, ok := m.Load(k); v != want || ok != wantOK {
+				t.errorf("%s: Load(%v) = %v, %v, want %v, %v", phase, k, v, ok, want, wantOK)
+				return
+			}
+		}
+		if got := m.Len(); got != len(model) {
+			t.errorf("%s: after %d operations, Len() = %d, want %d", phase, i+1, got, len(model))
+			return
+		}
+		if (i+1)%(ops/10) == 0 && !t.check(phase, m, model) {
+			return
+		}
+	}
+	for k := range model {
+		m.Delete(k)
+		delete(model, k)
+	}
+	t.check(phase+", then deleting all keys", m, model)
+}
+
+// testDeleteDuringAll checks that All does not produce keys that were
+// deleted before it reached them.
+func (t *mapTester[K, V]) testDeleteDuringAll() {
+	const phase = "deleting during All"
+	const n = 100
+	m := t.newMap()
+	want := make(map[K]V)
+	for i := range n {
+		m.Store(t.key(i), t.value(i))
+		want[t.key(i)] = t.value(i)
+	}
+	deleted := make(map[K]bool)
+	seen := make(map[K]bool)
+	for k, v := range m.All() {
+		if len(seen) == 0 {
+			// Delete every other key not yet produced.
+			for i := range n {
+				if dk := t.key(i); i%2 == 0 && dk != k {
+					m.Delete(dk)
+					delete(want, dk)
+					deleted[dk] = true
+				}
+			}
+		}
+		switch {
+		case deleted[k]:
+			t.errorf("%s: All produced key %v after it was deleted", phase, k)
+			return
+		case seen[k]:
+			t.errorf("%s: All produced key %v more than once", phase, k)
+			return
+		case v != want[k]:
+			t.errorf("%s: All produced %v for key %v, want %v", phase, v, k, want[k])
+			return
+		}
+		seen[k] = true
+	}
+	if len(seen) != len(want) {
+		t.errorf("%s: All produced %d keys, want %d", phase, len(seen), len(want))
+		return
+	}
+	t.check(phase, m, want)
+}
+
+// testStoreDuringAll checks that All produces every key that was present
+// when it started, even if enough keys are added during iteration
+// for the map to grow.
+func (t *mapTester[K, V]) testStoreDuringAll() {
+	const phase = "storing during All"
+	const n = 100
+	m := t.newMap()
+	want := make(map[K]V)
+	for i := range n {
+		m.Store(t.key(i), t.value(i))
+		want[t.key(i)] = t.value(i)
+	}
+	added := make(map[K]V)
+	seen := make(map[K]bool)
+	for k, v := range m.All() {
+		if len(seen) == 0 {
+			for i := n; i < 10*n; i++ {
+				m.Store(t.key(i), t.value(i))
+				added[t.key(i)] = t.value(i)
+			}
+		}
+		wantV, found := want[k]
+		if !found {
+			wantV, found = added[k]
+		}
+		switch {
+		case !found:
+			t.errorf("%s: All produced key %v, which is not in the map", phase, k)
+			return
+		case seen[k]:
+			t.errorf("%s: All produced key %v more than once", phase, k)
+			return
+		case v != wantV:
+			t.errorf("%s: All produced %v for key %v, want %v", phase, v, k, wantV)
+			return
+		}
+		seen[k] = true
+	}
+	for k := range want {
+		if !seen[k] {
+			t.errorf("%s: All did not produce key %v", phase, k)
+			return
+		}
+	}
+	for k, v := range added {
+		want[k] = v
+	}
+	t.check(phase, m, want)
+}
+
+// testStopAll checks that All stops calling yield once it returns false.
+func (t *mapTester[K, V]) testStopAll() {
+	m := t.newMap()
+	for i := range 10 {
+		m.Store(t.key(i), t.value(i))
+	}
+	calls := 0
+	m.All()(func(K, V) bool {
+		calls++
+		return false
+	})
+	switch {
+	case calls == 0:
+		t.errorf("All did not call yield for a map with %d keys", m.Len())
+	case calls > 1:
+		t.errorf("All called yield %d times after it returned false", calls-1)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package maptest
+
+import (
+	"iter"
+	"maps"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// builtinMap adapts a builtin map to the Map interface.
+type builtinMap[K comparable, V comparable] map[K]V
+
+func (m builtinMap[K, V]) Load(key K) (V, bool) { v, ok := m[key]; return v, ok }
+func (m builtinMap[K, V]) Store(key K, value V) { m[key] = value }
+func (m builtinMap[K, V]) Delete(key K)         { delete(m, key) }
+func (m builtinMap[K, V]) Len() int             { return len(m) }
+func (m builtinMap[K, V]) All() iter.Seq2[K, V] { return maps.All(m) }
+
+func identity(i int) int { return i }
+
+func TestBuiltinMap(t *testing.T) {
+	if err := TestMap(func() Map[int, int] { return builtinMap[int, int]{} }, identity, identity); err != nil {
+		t.Fatal(err)
+	}
+	if err := TestMap(func() Map[string, string] { return builtinMap[string, string]{} }, strconv.Itoa, strconv.Itoa); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// lazyDeleteMap forgets to decrement its length on Delete.
+type lazyDeleteMap struct {
+	builtinMap[int, int]
+	n int
+}
+
+func (m *lazyDeleteMap) Store(key, value int) {
+	if _, ok := m.builtinMap[key]; !ok {
+		m.n++
+	}
+	m.builtinMap[key] = value
+}
+
+func (m *lazyDeleteMap) Len() int { return m.n }
+
+// snapshotMap iterates over a copy of itself, so it produces keys that
+// were deleted during iteration.
+type snapshotMap struct {
+	builtinMap[int, int]
+}
+
+func (m snapshotMap) All() iter.Seq2[int, int] {
+	return maps.All(maps.Clone(m.builtinMap))
+}
+
+// eagerMap keeps calling yield after it returns false.
+type eagerMap struct {
+	builtinMap[int, int]
+}
+
+func (m eagerMap) All() iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		for k, v := range m.builtinMap {
+			yield(k, v)
+		}
+	}
+}
+
+func TestBrokenMaps(t *testing.T) {
+	tests := []struct {
+		name   string
+		newMap func() Map[int, int]
+		want   string
+	}{
+		{"lazyDelete", func() Map[int, int] { return &lazyDeleteMap{builtinMap: builtinMap[int, int]{}} }, "random operations on 10 keys: after 16 operations, Len() = 6, want 5"},
+		{"snapshot", func() Map[int, int] { return snapshotMap{builtinMap[int, int]{}} }, "deleting during All: All produced key"},
+		{"eager", func() Map[int, int] { return eagerMap{builtinMap[int, int]{}} }, "All called yield 9 times after it returned false"},
+	}
+	for _, tt := range tests {
+		err := TestMap(tt.newMap, identity, identity)
+		if err == nil {
+			t.Errorf("%s: TestMap succeeded, want error", tt.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: TestMap error does not contain %q:\n%v", tt.name, tt.want, err)
+		}
+	}
+}
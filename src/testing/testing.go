@@ -241,6 +241,14 @@
 // mode, the fuzz test acts much like a regular test, with subtests started
 // with F.Fuzz instead of T.Run.
 //
+// Fuzz targets whose failures depend on the order of map iteration can be
+// made reproducible by setting the environment variable GODEBUG to
+// mapseed=N (see the runtime package) both when fuzzing and when running
+// the inputs again. The fuzz target is then called with a map iteration
+// order derived from its input, so an input saved for a failure iterates
+// over maps in the same order when it is run again, as long as the fuzz
+// target does the same map operations and its map keys contain no pointers.
+//
 // See https://go.dev/doc/fuzz for documentation about fuzzing.
 //
 // # Skipping
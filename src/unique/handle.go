@@ -6,7 +6,6 @@ package unique
 
 import (
 	"internal/abi"
-	"internal/concurrent"
 	"internal/weak"
 	"runtime"
 	"sync"
@@ -90,7 +89,7 @@ var (
 	// benefit of not cramming every different type into a single map, but that's certainly
 	// not enough to outweigh the cost of two map lookups. What is worth it though, is saving
 	// on those allocations.
-	uniqueMaps = concurrent.NewHashTrieMap[*abi.Type, any]() // any is always a *uniqueMap[T].
+	uniqueMaps sync.HashTrieMap[*abi.Type, any] // any is always a *uniqueMap[T].
 
 	// cleanupFuncs are functions that clean up dead weak pointers in type-specific
 	// maps in uniqueMaps. We express cleanup this way because there's no way to iterate
@@ -106,7 +105,7 @@ var (
 )
 
 type uniqueMap[T comparable] struct {
-	*concurrent.HashTrieMap[T, weak.Pointer[T]]
+	*sync.HashTrieMap[T, weak.Pointer[T]]
 	cloneSeq
 }
 
@@ -116,7 +115,7 @@ func addUniqueMap[T comparable](typ *abi.Type) *uniqueMap[T] {
 	// small, stray allocation. The number of allocations
 	// this can create is bounded by a small constant.
 	m := &uniqueMap[T]{
-		HashTrieMap: concurrent.NewHashTrieMap[T, weak.Pointer[T]](),
+		HashTrieMap: new(sync.HashTrieMap[T, weak.Pointer[T]]),
 		cloneSeq:    makeCloneSeq(typ),
 	}
 	a, loaded := uniqueMaps.LoadOrStore(typ, m)
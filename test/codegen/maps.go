@@ -66,6 +66,68 @@ func LookupStringConversionKeyedArrayLit(m map[[2]string]int, bytes []byte) int
 	return m[[2]string{0: string(bytes)}]
 }
 
+// ------------------- //
+//   Repeated Access   //
+// ------------------- //
+
+func AccessTwice(m map[int]int, k int) int {
+	// amd64:`.*mapaccess1_fast64`
+	x := m[k]
+	// amd64:-`.*mapaccess1_fast64`
+	return x + m[k]
+}
+
+func AccessAfterCheck(m map[string]int, k string) int {
+	// amd64:`.*mapaccess2_faststr`
+	if _, ok := m[k]; ok {
+		// amd64:-`.*mapaccess1_faststr`
+		return m[k]
+	}
+	return 0
+}
+
+func AccessCheckTwice(m map[int32]int, k int32) (int, bool) {
+	// amd64:`.*mapaccess2_fast32`
+	x, ok := m[k]
+	// amd64:-`.*mapaccess2_fast32`
+	y, ok2 := m[k]
+	return x + y, ok && ok2
+}
+
+func AccessAfterWrite(m map[int]int, k int) int {
+	x := m[k]
+	m[k+1] = 0
+	// amd64:`.*mapaccess1_fast64`
+	return x + m[k]
+}
+
+type UUID [16]byte
+
+func AccessUUID(m map[UUID]int, k UUID) int {
+	// amd64:`.*mapaccess2_fast128`
+	if _, ok := m[k]; ok {
+		// amd64:-`.*mapaccess1_fast128`
+		return m[k]
+	}
+	return 0
+}
+
+func AccessPair(m map[[2]uint64]int, k [2]uint64) int {
+	// amd64:`.*mapaccess2_fast128`
+	if _, ok := m[k]; ok {
+		// amd64:-`.*mapaccess1_fast128`
+		return m[k]
+	}
+	return 0
+}
+
+func AccessUUIDAfterKeyWrite(m map[UUID]int, k UUID) int {
+	x := m[k]
+	k[0]++
+	// amd64:`.*mapaccess1_fast128`
+	return x + m[k]
+}
+
 // ------------------- //
 //     Map Clear       //
 // ------------------- //
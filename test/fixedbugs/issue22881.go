@@ -13,7 +13,7 @@ import "fmt"
 
 func main() {
 	for i, f := range []func(map[int]int){
-		f0, f1, f2, f3, f4, f5, f6, f7, f8,
+		f0, f1, f2, f3, f4, f5, f6, f7, f8, f9, f10,
 	} {
 		m := map[int]int{}
 		func() { // wrapper to scope the defer.
@@ -91,6 +91,16 @@ func f8(m map[int]int) {
 	m[0] %= z
 }
 
+func f9(m map[int]int) {
+	s := -1
+	m[0] = m[0] << s
+}
+
+func f10(m map[int]int) {
+	s := -1
+	m[0] = m[0] >> s
+}
+
 func fa0(m map[int][]int) {
 	var p *int
 	m[0] = append(m[0], *p)